@@ -15,7 +15,18 @@
 //
 // Features:
 // - OTLP exporter configuration (HTTP/gRPC, secure/insecure modes)
-// - Sampling strategies: probabilistic, always_on, always_off
+// - Endpoints as host:port or full URLs, where the scheme selects TLS and the path is kept or extended per signal
+// - Export retry with exponential backoff, configurable or disabled
+// - TLS and mTLS with custom CA bundles and periodically reloaded client certificates
+// - Console exporters for local development (pretty tree or JSON lines on stdout)
+// - Zipkin exporter for Zipkin-compatible backends
+// - File exporter writing OTLP/JSON lines with size/age based rotation
+// - Optional disk-backed queue that keeps failed batches across collector outages and restarts
+// - Fan-out to additional exporters, each with its own batch processor
+// - Span limits with marked truncation of oversized attribute values
+// - Exporter health reporting and an optional startup probe of the collector
+// - Sampling strategies: probabilistic, always_on, always_off, rate_limited, rule_based and the
+//   specification's traceidratio, parentbased_always_on, parentbased_always_off, parentbased_traceidratio
 // - Service metadata (name, version, environment, instance ID)
 // - Context propagation and resource attribution
 // - HTTP and gRPC instrumentation compatibility
@@ -40,16 +51,68 @@
 // - OTEL_SERVICE_NAME                          (e.g., "user-service")
 // - OTEL_SERVICE_VERSION                       (e.g., "1.2.3")
 // - OTEL_ENVIRONMENT                           (e.g., "production")
-// - OTEL_EXPORTER_OTLP_ENDPOINT                (e.g., "localhost:4317")
+// - OTEL_EXPORTER_OTLP_ENDPOINT                (e.g., "localhost:4317" or "https://collector.example.com/otlp";
+//   OTLP/HTTP appends /v1/traces, /v1/metrics or /v1/logs to a URL path)
+// - OTEL_EXPORTER_OTLP_TRACES_ENDPOINT         (takes precedence for traces, path used as-is, e.g.,
+//   "https://collector.example.com/custom/v1/traces")
 // - OTEL_EXPORTER_OTLP_INSECURE                (true/false)
-// - OTEL_EXPORTER_OTLP_PROTOCOL                ("grpc" or "http")
+// - OTEL_EXPORTER_OTLP_PROTOCOL                ("grpc", "http", "stdout", "console", "stdout_json", "file" or "zipkin")
+// - OTEL_EXPORTER_OTLP_HEADERS                 (e.g., "x-api-key=secret,x-team=payments"; values are redacted when printed)
+// - OTEL_EXPORTER_OTLP_COMPRESSION             ("gzip" or "none")
+// - OTEL_EXPORTER_OTLP_TIMEOUT                 (milliseconds, e.g., "10000", or a duration such as "10s")
+// - OTEL_EXPORTER_OTLP_CERTIFICATE             (CA bundle used to verify the collector, PEM file)
+// - OTEL_EXPORTER_OTLP_CLIENT_CERTIFICATE      (client certificate for mTLS, PEM file)
+// - OTEL_EXPORTER_OTLP_CLIENT_KEY              (client private key for mTLS, PEM file)
+// - OTEL_EXPORTER_OTLP_CERTIFICATE_RELOAD_INTERVAL (how often to check certificate files for changes, e.g., "1m")
+// - OTEL_EXPORTER_OTLP_RETRY_ENABLED          (true/false, default true)
+// - OTEL_EXPORTER_OTLP_RETRY_INITIAL_INTERVAL (first backoff, e.g., "5s")
+// - OTEL_EXPORTER_OTLP_RETRY_MAX_INTERVAL     (backoff cap, e.g., "30s")
+// - OTEL_EXPORTER_OTLP_RETRY_MAX_ELAPSED_TIME (give up after, e.g., "1m")
+// - OTEL_TRACES_EXPORTER                       ("otlp", "console", "stdout", "stdout_json", "file", "zipkin" or "none";
+//   a list such as "otlp,console" adds the other entries as additional exporters)
+// - OTEL_EXPORTER_FILE_PATH                    (e.g., "/var/spool/traces.jsonl")
+// - OTEL_EXPORTER_FILE_MAX_SIZE                (bytes before rotation, e.g., "104857600")
+// - OTEL_EXPORTER_FILE_MAX_AGE                 (age before rotation, e.g., "24h")
+// - OTEL_EXPORTER_FILE_MAX_BACKUPS             (rotated files to keep, e.g., "5")
+// - OTEL_EXPORTER_ZIPKIN_ENDPOINT              (e.g., "http://zipkin:9411/api/v2/spans")
+// - OTEL_EXPORTER_PERSISTENT_QUEUE_DIR        (spill failed batches here, e.g., "/var/lib/otelkit/queue")
+// - OTEL_EXPORTER_PERSISTENT_QUEUE_MAX_SIZE   (disk budget in bytes, e.g., "268435456")
+// - OTEL_EXPORTERS                             (additional exporter names, e.g., "vendor,archive")
+// - OTEL_EXPORTER_<NAME>_PROTOCOL              (per additional exporter, e.g., "grpc")
+// - OTEL_EXPORTER_<NAME>_ENDPOINT              (per additional exporter, e.g., "vendor:4317")
+// - OTEL_EXPORTER_<NAME>_INSECURE              (per additional exporter, true/false)
+// - OTEL_EXPORTER_<NAME>_HEADERS               (per additional exporter, e.g., "x-api-key=secret")
+// - OTEL_EXPORTER_<NAME>_BSP_TIMEOUT, _TIMEOUT, _BSP_MAX_EXPORT_BATCH_SIZE, _BSP_MAX_QUEUE_SIZE
 // - OTEL_BSP_TIMEOUT                           (e.g., "5s")
 // - OTEL_EXPORTER_TIMEOUT                      (e.g., "30s")
 // - OTEL_BSP_MAX_EXPORT_BATCH_SIZE             (e.g., "512")
 // - OTEL_BSP_MAX_QUEUE_SIZE                    (e.g., "2048")
-// - OTEL_TRACES_SAMPLER                        ("probabilistic", "always_on", "always_off")
-// - OTEL_TRACES_SAMPLER_ARG                    (e.g., "0.25")
+// - OTEL_TRACES_SAMPLER                        ("probabilistic", "always_on", "always_off", "rate_limited", "rule_based",
+//   "traceidratio", "parentbased_always_on", "parentbased_always_off", "parentbased_traceidratio",
+//   "jaeger_remote" or "parentbased_jaeger_remote")
+// - OTEL_TRACES_SAMPLER_ARG                    (ratio, e.g., "0.25", default 0.2 or 1.0 for the traceidratio types;
+//   root traces per second for rate_limited, e.g., "100"; for the jaeger_remote types comma-separated
+//   settings, e.g., "endpoint=http://jaeger-agent:5778/sampling,pollingIntervalMs=60000,initialSamplingRate=0.01")
+// - OTEL_TRACES_SAMPLER_RULES_FILE             (JSON or YAML sampling rules for rule_based, e.g., "/etc/otel/sampling.yaml")
 // - OTEL_RESOURCE_ATTRIBUTES_SERVICE_INSTANCE_ID (optional unique instance ID)
+// - OTEL_METRICS_EXPORTER                      ("otlp" or "none", default none; SetupTracing also sets up metrics when "otlp")
+// - OTEL_EXPORTER_OTLP_METRICS_ENDPOINT        (metrics endpoint used as-is, default OTEL_EXPORTER_OTLP_ENDPOINT with /v1/metrics)
+// - OTEL_METRIC_EXPORT_INTERVAL                (milliseconds between metric exports, default 60000)
+// - OTEL_METRIC_EXPORT_TIMEOUT                 (milliseconds allowed per metric export, default 30000)
+// - OTEL_EXPORTER_OTLP_METRICS_TEMPORALITY_PREFERENCE ("cumulative", "delta" or "lowmemory", default cumulative)
+// - OTEL_LOGS_EXPORTER                         ("otlp" or "none", default none; SetupTracing also sets up logs when "otlp")
+// - OTEL_EXPORTER_OTLP_LOGS_ENDPOINT           (logs endpoint used as-is, default OTEL_EXPORTER_OTLP_ENDPOINT with /v1/logs)
+// - OTEL_SPAN_ATTRIBUTE_COUNT_LIMIT            (attributes per span, default 128; falls back to OTEL_ATTRIBUTE_COUNT_LIMIT)
+// - OTEL_SPAN_ATTRIBUTE_VALUE_LENGTH_LIMIT     (characters per string value, default unlimited; falls back to
+//   OTEL_ATTRIBUTE_VALUE_LENGTH_LIMIT; truncated values end in "...[truncated]")
+// - OTEL_SPAN_EVENT_COUNT_LIMIT                (events per span, default 128)
+// - OTEL_SPAN_LINK_COUNT_LIMIT                 (links per span, default 128)
+// - OTEL_EVENT_ATTRIBUTE_COUNT_LIMIT           (attributes per span event, default 128)
+// - OTEL_LINK_ATTRIBUTE_COUNT_LIMIT            (attributes per span link, default 128)
+// - OTEL_EXPORTER_STARTUP_PROBE                ("none", "warn" or "fail": check at startup that the OTLP endpoints
+//   accept connections; "fail" makes SetupTracing return an error, default none)
+// - OTEL_EXPORTER_STARTUP_PROBE_TIMEOUT        (time allowed to connect, e.g., "5s")
+// - OTEL_EXPORTER_HEALTH_FAILING_AFTER         (how long exports must keep failing before health is failing, default "2m")
 //
 // Note:
 // Validation must be explicitly called after config construction to ensure correctness.
@@ -57,8 +120,11 @@
 package config
 
 import (
+	"errors"
+	"fmt"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -75,9 +141,41 @@ type Config struct {
 	Environment    string // Deployment environment (development/staging/production)
 
 	// OTLP exporter settings
-	OTLPExporterEndpoint string // Collector endpoint (host:port)
-	OTLPExporterInsecure bool   // Disable TLS verification
-	OTLPExporterProtocol string // Protocol for OTLP exporter (default: grpc)
+	OTLPExporterEndpoint string        // Base collector endpoint (host:port or http(s):// URL, to which /v1/traces is appended)
+	OTLPTracesEndpoint   string        // Overrides OTLPExporterEndpoint for traces; a URL path is used as-is
+	OTLPExporterInsecure bool          // Disable TLS verification (URL endpoints use their scheme instead)
+	OTLPExporterProtocol string        // Exporter protocol: grpc, http, stdout, console, stdout_json, file, zipkin or none (default: http)
+	OTLPExporterHeaders  Headers       // Extra headers sent with every export request (redacted when printed)
+	OTLPCompression      string        // Payload compression: gzip or none (default: none)
+	OTLPTimeout          time.Duration // Timeout for a single OTLP export request (0 uses the exporter default)
+
+	// TLS settings for OTLP exporters (ignored when OTLPExporterInsecure is set)
+	TLSCAFile         string        // PEM CA bundle used to verify the collector certificate
+	TLSClientCertFile string        // PEM client certificate for mTLS
+	TLSClientKeyFile  string        // PEM client private key for mTLS
+	TLSReloadInterval time.Duration // How often certificate files are checked for changes (default: 1m, 0 disables)
+
+	// Export retry policy for OTLP exporters (exponential backoff with jitter)
+	RetryDisabled        bool          // Send each batch once and drop it on failure
+	RetryInitialInterval time.Duration // Wait before the first retry (default: 5s)
+	RetryMaxInterval     time.Duration // Upper bound for the backoff interval (default: 30s)
+	RetryMaxElapsedTime  time.Duration // Give up on a batch after this long (default: 1m)
+
+	// Zipkin exporter settings (used when OTLPExporterProtocol is "zipkin")
+	ZipkinEndpoint string // Zipkin v2 spans URL (default: http://localhost:9411/api/v2/spans)
+
+	// Disk-backed queue for batches that fail to export (OTLP exporters only)
+	PersistentQueueDir     string // Directory for spilled batches (empty disables the queue)
+	PersistentQueueMaxSize int64  // Disk budget in bytes; the oldest batches are dropped beyond it (0 = unlimited)
+
+	// Additional export destinations receiving the same spans as the primary exporter
+	Exporters []ExporterConfig
+
+	// File exporter settings (used when OTLPExporterProtocol is "file")
+	FileExporterPath       string        // Target file for OTLP/JSON lines
+	FileExporterMaxSize    int64         // Rotate when the file exceeds this many bytes (0 disables)
+	FileExporterMaxAge     time.Duration // Rotate when the file is older than this (0 disables)
+	FileExporterMaxBackups int           // Number of rotated files to keep (0 keeps all)
 
 	// Batch processing configuration
 	BatchTimeout       time.Duration // Timeout for batch processing (default: 5s)
@@ -86,8 +184,48 @@ type Config struct {
 	MaxQueueSize       int           // Maximum queue size for spans (default: 2048)
 
 	// Sampling configuration
-	SamplingRatio float64      // Ratio of traces to sample (0.0 - 1.0)
-	SamplingType  SamplingType // Sampling strategy (type-safe)
+	SamplingRatio     float64      // Ratio of traces to sample (0.0 - 1.0)
+	SamplingType      SamplingType // Sampling strategy (type-safe)
+	SamplingRateLimit float64      // Root traces per second for rate_limited sampling (default: 100)
+
+	// Rule-based sampling (used when SamplingType is "rule_based"); spans matching no
+	// rule are sampled at SamplingRatio
+	SamplingRules     []SamplingRule // Ordered rules; the first match decides
+	SamplingRulesFile string         // JSON or YAML file with rules evaluated after SamplingRules
+
+	// Jaeger remote sampling (used when SamplingType is "jaeger_remote" or
+	// "parentbased_jaeger_remote"); SamplingRatio applies until a strategy is fetched
+	JaegerRemoteSamplingEndpoint string        // Strategy URL, queried with ?service=<ServiceName>
+	JaegerRemoteSamplingInterval time.Duration // How often strategies are polled (default: 1m)
+
+	// Metrics settings; metrics are exported over OTLP/gRPC when OTLPExporterProtocol is
+	// "grpc" and OTLP/HTTP otherwise, with the headers, compression, TLS and retry
+	// settings above
+	MetricsExporter       string        // "otlp" or "none" (default: none)
+	MetricsEndpoint       string        // Overrides OTLPExporterEndpoint for metrics; a URL path is used as-is
+	MetricsExportInterval time.Duration // Time between periodic exports (default: 60s)
+	MetricsExportTimeout  time.Duration // Timeout of a periodic export (default: 30s)
+	MetricsTemporality    string        // cumulative, delta or lowmemory (default: cumulative)
+
+	// Logs settings; log records are exported like metrics, over OTLP/gRPC or OTLP/HTTP
+	LogsExporter string // "otlp" or "none" (default: none)
+	LogsEndpoint string // Overrides OTLPExporterEndpoint for logs; a URL path is used as-is
+
+	// Span limits; a negative limit means unlimited and zero keeps the default. String
+	// values cut to SpanAttributeValueLengthLimit end in TruncatedValueSuffix. Values
+	// are cut after redaction, so the full values are held until a span ends
+	SpanAttributeCountLimit       int // Attributes per span (default: 128)
+	SpanAttributeValueLengthLimit int // Characters per string attribute value of spans, events and links (default: unlimited)
+	SpanEventCountLimit           int // Events per span (default: 128)
+	SpanLinkCountLimit            int // Links per span (default: 128)
+	EventAttributeCountLimit      int // Attributes per span event (default: 128)
+	LinkAttributeCountLimit       int // Attributes per span link (default: 128)
+
+	// Exporter health; the startup probe checks that the OTLP endpoints accept
+	// connections when the provider is created
+	StartupProbe        string        // "none", "warn" or "fail" (default: none)
+	StartupProbeTimeout time.Duration // Time allowed to connect to each endpoint (default: 5s)
+	HealthFailingAfter  time.Duration // How long exports must keep failing before health is failing (default: 2m)
 
 	// Resource attributes
 	InstanceID string // Unique instance identifier
@@ -98,21 +236,56 @@ type Config struct {
 func NewConfig(serviceName, serviceVersion string) *Config {
 	hostname, _ := os.Hostname()
 	return &Config{
-		ServiceName:          serviceName,
-		ServiceVersion:       serviceVersion,
-		Environment:          DefaultEnvironment,
-		OTLPExporterEndpoint: DefaultOTLPExporterEndpoint,
-		OTLPExporterInsecure: false,
-		SamplingRatio:        DefaultSamplingRatio,
-		SamplingType:         DefaultSamplingType,
-		InstanceID:           generateInstanceID(),
-		Hostname:             hostname,
-		OTLPExporterProtocol: DefaultOTLPExporterProtocol,
+		ServiceName:            serviceName,
+		ServiceVersion:         serviceVersion,
+		Environment:            DefaultEnvironment,
+		OTLPExporterEndpoint:   DefaultOTLPExporterEndpoint,
+		OTLPExporterInsecure:   false,
+		SamplingRatio:          DefaultSamplingRatio,
+		SamplingType:           DefaultSamplingType,
+		SamplingRateLimit:      DefaultSamplingRate,
+		InstanceID:             generateInstanceID(),
+		Hostname:               hostname,
+		OTLPExporterProtocol:   DefaultOTLPExporterProtocol,
+		TLSReloadInterval:      DefaultTLSReloadInterval,
+		RetryInitialInterval:   DefaultRetryInitialInterval,
+		RetryMaxInterval:       DefaultRetryMaxInterval,
+		RetryMaxElapsedTime:    DefaultRetryMaxElapsedTime,
+		PersistentQueueMaxSize: DefaultPersistentQueueMaxSize,
+		ZipkinEndpoint:         DefaultZipkinEndpoint,
+		FileExporterPath:       DefaultFileExporterPath,
+		FileExporterMaxSize:    DefaultFileExporterMaxSize,
+		FileExporterMaxAge:     DefaultFileExporterMaxAge,
+		FileExporterMaxBackups: DefaultFileExporterBackups,
+
+		JaegerRemoteSamplingEndpoint: DefaultJaegerRemoteSamplingEndpoint,
+		JaegerRemoteSamplingInterval: DefaultJaegerRemoteSamplingInterval,
+
+		MetricsExporter:       DefaultMetricsExporter,
+		MetricsExportInterval: DefaultMetricsExportInterval,
+		MetricsExportTimeout:  DefaultMetricsExportTimeout,
+		MetricsTemporality:    DefaultMetricsTemporality,
+
+		LogsExporter: DefaultLogsExporter,
+
+		SpanAttributeCountLimit:       DefaultSpanAttributeCountLimit,
+		SpanAttributeValueLengthLimit: DefaultSpanAttributeValueLengthLimit,
+		SpanEventCountLimit:           DefaultSpanEventCountLimit,
+		SpanLinkCountLimit:            DefaultSpanLinkCountLimit,
+		EventAttributeCountLimit:      DefaultEventAttributeCountLimit,
+		LinkAttributeCountLimit:       DefaultLinkAttributeCountLimit,
+
+		StartupProbe:        DefaultStartupProbe,
+		StartupProbeTimeout: DefaultStartupProbeTimeout,
+		HealthFailingAfter:  DefaultHealthFailingAfter,
 	}
 }
 
-// NewConfigFromEnv creates configuration from environment variables
-func NewConfigFromEnv() *Config {
+// NewConfigFromEnv creates configuration from environment variables. Settings it
+// cannot use, such as an unsupported sampler, are replaced by defaults and returned
+// as warnings for the caller to report; the configuration is usable either way.
+func NewConfigFromEnv() (*Config, []error) {
+	var warnings []error
 	cfg := NewConfig(
 		getEnv(EnvServiceName, DefaultServiceName),
 		getEnv(EnvServiceVersion, DefaultServiceVersion),
@@ -121,19 +294,80 @@ func NewConfigFromEnv() *Config {
 	// Apply environment overrides
 	cfg.Environment = getEnv(EnvEnvironment, DefaultEnvironment)
 
-	cfg.OTLPExporterProtocol = getEnv(EnvOTLPExporterProtocol, DefaultOTLPExporterProtocol)
+	protocol, tracesExporters := exporterProtocolFromEnv()
+	cfg.OTLPExporterProtocol = protocol
 	cfg.BatchTimeout = getEnvDuration(EnvBatchTimeout, DefaultBatchTimeout)
 	cfg.ExportTimeout = getEnvDuration(EnvExportTimeout, DefaultExportTimeout)
 	cfg.MaxExportBatchSize = getEnvInt(EnvMaxExportBatchSize, DefaultMaxExportBatchSize)
 	cfg.MaxQueueSize = getEnvInt(EnvMaxQueueSize, DefaultMaxQueueSize)
 
 	cfg.OTLPExporterEndpoint = getEnv(EnvOTLPExporterEndpoint, DefaultOTLPExporterEndpoint)
+	cfg.OTLPTracesEndpoint = os.Getenv(EnvOTLPTracesEndpoint)
 	cfg.OTLPExporterInsecure = getEnvBool(EnvOTLPExporterInsecure, false)
-	cfg.SamplingRatio = getEnvFloat(EnvSamplingRatio, DefaultSamplingRatio)
-	cfg.SamplingType = ParseSamplingType(getEnv(EnvSamplingType, string(DefaultSamplingType)))
+	cfg.OTLPExporterHeaders = parseHeaders(os.Getenv(EnvOTLPExporterHeaders))
+	cfg.OTLPCompression = strings.ToLower(getEnv(EnvOTLPCompression, ""))
+	cfg.OTLPTimeout = getEnvMillis(EnvOTLPTimeout, 0)
+	cfg.TLSCAFile = os.Getenv(EnvOTLPCertificate)
+	cfg.TLSClientCertFile = os.Getenv(EnvOTLPClientCert)
+	cfg.TLSClientKeyFile = os.Getenv(EnvOTLPClientKey)
+	cfg.TLSReloadInterval = getEnvDuration(EnvTLSReloadInterval, DefaultTLSReloadInterval)
+	cfg.RetryDisabled = !getEnvBool(EnvRetryEnabled, true)
+	cfg.RetryInitialInterval = getEnvDuration(EnvRetryInitialInterval, DefaultRetryInitialInterval)
+	cfg.RetryMaxInterval = getEnvDuration(EnvRetryMaxInterval, DefaultRetryMaxInterval)
+	cfg.RetryMaxElapsedTime = getEnvDuration(EnvRetryMaxElapsedTime, DefaultRetryMaxElapsedTime)
+	sampler := getEnv(EnvSamplingType, string(DefaultSamplingType))
+	cfg.SamplingType = ParseSamplingType(sampler)
+	if !SamplingType(strings.ToLower(strings.TrimSpace(sampler))).IsValid() {
+		warnings = append(warnings, fmt.Errorf("otelkit: unsupported %s value %q, using %q", EnvSamplingType, sampler, cfg.SamplingType))
+	}
+	switch cfg.SamplingType {
+	case SamplingRateLimited:
+		cfg.SamplingRateLimit = getEnvFloat(EnvSamplingRatio, DefaultSamplingRate)
+	case SamplingTraceIDRatio, SamplingParentBasedTraceIDRatio:
+		cfg.SamplingRatio = getEnvFloat(EnvSamplingRatio, DefaultSpecSamplingRatio)
+	case SamplingJaegerRemote, SamplingParentBasedJaegerRemote:
+		cfg.SamplingRatio = DefaultJaegerRemoteRatio
+		warnings = append(warnings, cfg.applyJaegerRemoteSamplerArg(os.Getenv(EnvSamplingRatio))...)
+	default:
+		cfg.SamplingRatio = getEnvFloat(EnvSamplingRatio, DefaultSamplingRatio)
+	}
+	cfg.SamplingRulesFile = os.Getenv(EnvSamplingRulesFile)
 	cfg.InstanceID = getEnv(EnvInstanceID, cfg.InstanceID)
 
-	return cfg
+	cfg.FileExporterPath = getEnv(EnvFileExporterPath, DefaultFileExporterPath)
+	cfg.FileExporterMaxSize = int64(getEnvInt(EnvFileExporterMaxSize, DefaultFileExporterMaxSize))
+	cfg.FileExporterMaxAge = getEnvDuration(EnvFileExporterMaxAge, DefaultFileExporterMaxAge)
+	cfg.FileExporterMaxBackups = getEnvInt(EnvFileExporterBackups, DefaultFileExporterBackups)
+
+	cfg.ZipkinEndpoint = getEnv(EnvZipkinEndpoint, DefaultZipkinEndpoint)
+	cfg.PersistentQueueDir = os.Getenv(EnvPersistentQueueDir)
+	cfg.PersistentQueueMaxSize = int64(getEnvInt(EnvPersistentQueueSize, DefaultPersistentQueueMaxSize))
+
+	cfg.Exporters = append(tracesExporters, exportersFromEnv()...)
+
+	cfg.MetricsExporter = strings.ToLower(getEnv(EnvMetricsExporter, DefaultMetricsExporter))
+	cfg.MetricsEndpoint = os.Getenv(EnvMetricsEndpoint)
+	cfg.MetricsExportInterval = getEnvMillis(EnvMetricsInterval, DefaultMetricsExportInterval)
+	cfg.MetricsExportTimeout = getEnvMillis(EnvMetricsTimeout, DefaultMetricsExportTimeout)
+	cfg.MetricsTemporality = strings.ToLower(getEnv(EnvMetricsTemporality, DefaultMetricsTemporality))
+
+	cfg.LogsExporter = strings.ToLower(getEnv(EnvLogsExporter, DefaultLogsExporter))
+	cfg.LogsEndpoint = os.Getenv(EnvLogsEndpoint)
+
+	cfg.StartupProbe = strings.ToLower(getEnv(EnvStartupProbe, DefaultStartupProbe))
+	cfg.StartupProbeTimeout = getEnvDuration(EnvStartupProbeTimeout, DefaultStartupProbeTimeout)
+	cfg.HealthFailingAfter = getEnvDuration(EnvHealthFailingAfter, DefaultHealthFailingAfter)
+
+	cfg.SpanAttributeCountLimit = getEnvInt(EnvSpanAttributeCountLimit,
+		getEnvInt(EnvAttributeCountLimit, DefaultSpanAttributeCountLimit))
+	cfg.SpanAttributeValueLengthLimit = getEnvInt(EnvSpanAttributeValueLengthLimit,
+		getEnvInt(EnvAttributeValueLengthLimit, DefaultSpanAttributeValueLengthLimit))
+	cfg.SpanEventCountLimit = getEnvInt(EnvSpanEventCountLimit, DefaultSpanEventCountLimit)
+	cfg.SpanLinkCountLimit = getEnvInt(EnvSpanLinkCountLimit, DefaultSpanLinkCountLimit)
+	cfg.EventAttributeCountLimit = getEnvInt(EnvEventAttributeCountLimit, DefaultEventAttributeCountLimit)
+	cfg.LinkAttributeCountLimit = getEnvInt(EnvLinkAttributeCountLimit, DefaultLinkAttributeCountLimit)
+
+	return cfg, warnings
 }
 
 // Validate ensures configuration parameters are correct
@@ -147,22 +381,239 @@ func (c *Config) Validate() error {
 	if !contains(ValidEnvironments, c.Environment) {
 		return &ConfigError{Field: "Environment", Message: ErrInvalidEnvironment}
 	}
-	if c.OTLPExporterEndpoint == "" {
-		return &ConfigError{Field: "OTLPExporterEndpoint", Message: ErrInvalidExporterEndpoint}
+	if c.UsesOTLPEndpoint() {
+		if c.OTLPExporterEndpoint == "" && c.OTLPTracesEndpoint == "" {
+			return &ConfigError{Field: "OTLPExporterEndpoint", Message: ErrInvalidExporterEndpoint}
+		}
+		if c.OTLPExporterEndpoint != "" {
+			if _, err := c.ParsedEndpoint(); err != nil {
+				return err
+			}
+		}
+		if _, err := c.TracesEndpoint(); err != nil {
+			return err
+		}
+	}
+	if err := c.ValidateSampling(); err != nil {
+		return err
+	}
+	if !contains(ValidOTLPProtocols, c.OTLPExporterProtocol) {
+		return &ConfigError{Field: "OTLPExporterProtocol", Message: ErrInvalidExporterProtocol}
+	}
+	if err := c.OTLPExporterHeaders.validate("OTLPExporterHeaders"); err != nil {
+		return err
+	}
+	if !contains(ValidCompressions, c.OTLPCompression) {
+		return &ConfigError{Field: "OTLPCompression", Message: ErrInvalidCompression}
+	}
+	if c.OTLPTimeout < 0 {
+		return &ConfigError{Field: "OTLPTimeout", Message: ErrInvalidExporterTimeout}
+	}
+	if err := c.validateRetry(); err != nil {
+		return err
+	}
+	if (c.TLSClientCertFile == "") != (c.TLSClientKeyFile == "") {
+		return &ConfigError{Field: "TLSClientCertFile", Message: ErrIncompleteClientCert}
+	}
+	if c.OTLPExporterProtocol == ProtocolFile {
+		if c.FileExporterPath == "" {
+			return &ConfigError{Field: "FileExporterPath", Message: ErrFileExporterPath}
+		}
+		if c.FileExporterMaxSize < 0 || c.FileExporterMaxAge < 0 || c.FileExporterMaxBackups < 0 {
+			return &ConfigError{Field: "FileExporterRotation", Message: ErrInvalidFileRotation}
+		}
+	}
+	if c.OTLPExporterProtocol == ProtocolZipkin {
+		endpoint, err := ParseEndpoint("ZipkinEndpoint", c.ZipkinEndpoint)
+		if err != nil {
+			return err
+		}
+		if !endpoint.IsURL() {
+			return &ConfigError{Field: "ZipkinEndpoint", Message: ErrZipkinEndpointURL}
+		}
+	}
+	if c.PersistentQueueMaxSize < 0 {
+		return &ConfigError{Field: "PersistentQueueMaxSize", Message: ErrInvalidPersistentQueue}
+	}
+	if err := c.validateExporters(); err != nil {
+		return err
+	}
+	if err := c.validateMetrics(); err != nil {
+		return err
+	}
+	if err := c.validateLogs(); err != nil {
+		return err
+	}
+	if err := c.validateHealth(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// validateRetry checks the retry intervals. Zero intervals select the defaults, so
+// the initial interval is compared with the maximum interval that will apply.
+func (c *Config) validateRetry() error {
+	if c.RetryInitialInterval < 0 || c.RetryMaxInterval < 0 || c.RetryMaxElapsedTime < 0 {
+		return &ConfigError{Field: "RetryInitialInterval", Message: ErrInvalidRetryInterval}
+	}
+	initial, maxInterval := c.RetryInitialInterval, c.RetryMaxInterval
+	if initial == 0 {
+		initial = DefaultRetryInitialInterval
+	}
+	if maxInterval == 0 {
+		maxInterval = DefaultRetryMaxInterval
+	}
+	if initial > maxInterval {
+		return &ConfigError{Field: "RetryInitialInterval", Message: ErrInvalidRetryInterval}
+	}
+	return nil
+}
+
+// validateMetrics checks the metrics settings. An empty exporter or temporality
+// selects the default.
+func (c *Config) validateMetrics() error {
+	switch c.MetricsExporter {
+	case "", MetricsExporterNone, MetricsExporterOTLP:
+	default:
+		return &ConfigError{Field: "MetricsExporter", Message: ErrInvalidMetricsExporter}
+	}
+	switch c.MetricsTemporality {
+	case "", TemporalityCumulative, TemporalityDelta, TemporalityLowMemory:
+	default:
+		return &ConfigError{Field: "MetricsTemporality", Message: ErrInvalidTemporality}
+	}
+	if c.MetricsExportInterval < 0 || c.MetricsExportTimeout < 0 {
+		return &ConfigError{Field: "MetricsExportInterval", Message: ErrInvalidMetricsInterval}
 	}
+	if c.MetricsEndpoint != "" {
+		if _, err := ParseEndpoint("MetricsEndpoint", c.MetricsEndpoint); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// MetricsEnabled reports whether metrics are exported over OTLP.
+func (c *Config) MetricsEnabled() bool {
+	return c.MetricsExporter == MetricsExporterOTLP
+}
+
+// validateLogs checks the logs settings. An empty exporter selects the default.
+func (c *Config) validateLogs() error {
+	switch c.LogsExporter {
+	case "", LogsExporterNone, LogsExporterOTLP:
+	default:
+		return &ConfigError{Field: "LogsExporter", Message: ErrInvalidLogsExporter}
+	}
+	if c.LogsEndpoint != "" {
+		if _, err := ParseEndpoint("LogsEndpoint", c.LogsEndpoint); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// LogsEnabled reports whether log records are exported over OTLP.
+func (c *Config) LogsEnabled() bool {
+	return c.LogsExporter == LogsExporterOTLP
+}
+
+// validateHealth checks the startup probe and health settings. An empty probe mode
+// selects the default.
+func (c *Config) validateHealth() error {
+	switch c.StartupProbe {
+	case "", StartupProbeNone, StartupProbeWarn, StartupProbeFail:
+	default:
+		return &ConfigError{Field: "StartupProbe", Message: ErrInvalidStartupProbe}
+	}
+	if c.StartupProbeTimeout < 0 || c.HealthFailingAfter < 0 {
+		return &ConfigError{Field: "StartupProbeTimeout", Message: ErrInvalidHealthSettings}
+	}
+	return nil
+}
+
+// ValidateSampling checks the sampling type, its ratio or rate, and for rule-based
+// sampling the rules. It is part of Validate and is also used when the sampling
+// configuration is changed at runtime.
+func (c *Config) ValidateSampling() error {
 	if c.SamplingRatio < 0 || c.SamplingRatio > 1 {
 		return &ConfigError{Field: "SamplingRatio", Message: ErrInvalidSamplingRatio}
 	}
+	if c.SamplingType == SamplingRateLimited && c.SamplingRateLimit <= 0 {
+		return &ConfigError{Field: "SamplingRateLimit", Message: ErrInvalidSamplingRate}
+	}
 	if !c.SamplingType.IsValid() {
 		return &ConfigError{Field: "SamplingType", Message: ErrInvalidSamplingType}
 	}
-	if !contains(ValidOTLPProtocols, c.OTLPExporterProtocol) {
-		return &ConfigError{Field: "OTLPExporterProtocol", Message: ErrInvalidExporterProtocol}
+	if c.SamplingType == SamplingRuleBased {
+		if _, err := c.SamplingRuleSet(); err != nil {
+			return err
+		}
+	}
+	if c.SamplingType == SamplingJaegerRemote || c.SamplingType == SamplingParentBasedJaegerRemote {
+		endpoint, err := ParseEndpoint("JaegerRemoteSamplingEndpoint", c.JaegerRemoteSamplingEndpoint)
+		if err != nil {
+			return err
+		}
+		if !endpoint.IsURL() {
+			return &ConfigError{Field: "JaegerRemoteSamplingEndpoint", Message: ErrJaegerRemoteEndpointURL}
+		}
+		if c.JaegerRemoteSamplingInterval < 0 {
+			return &ConfigError{Field: "JaegerRemoteSamplingInterval", Message: ErrInvalidJaegerRemotePoll}
+		}
 	}
-
 	return nil
 }
 
+// applyJaegerRemoteSamplerArg applies the OTEL_TRACES_SAMPLER_ARG settings of the
+// jaeger_remote sampler types, a comma-separated list of endpoint, pollingIntervalMs
+// and initialSamplingRate as defined by the OpenTelemetry specification. Unknown keys
+// and malformed values are skipped and returned as warnings.
+func (c *Config) applyJaegerRemoteSamplerArg(arg string) []error {
+	var warnings []error
+	for _, entry := range strings.Split(arg, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		key, value, _ := strings.Cut(entry, "=")
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+		var err error
+		switch key {
+		case "endpoint":
+			c.JaegerRemoteSamplingEndpoint = value
+		case "pollingIntervalMs":
+			var ms int
+			if ms, err = strconv.Atoi(value); err == nil {
+				c.JaegerRemoteSamplingInterval = time.Duration(ms) * time.Millisecond
+			}
+		case "initialSamplingRate":
+			var ratio float64
+			if ratio, err = strconv.ParseFloat(value, 64); err == nil {
+				c.SamplingRatio = ratio
+			}
+		default:
+			err = errors.New("unknown setting")
+		}
+		if err != nil {
+			warnings = append(warnings, fmt.Errorf("otelkit: ignoring %s entry %q: %w", EnvSamplingRatio, key, err))
+		}
+	}
+	return warnings
+}
+
+// UsesOTLPEndpoint reports whether the configured protocol sends spans to a
+// remote collector endpoint. The console protocols write locally and ignore it.
+func (c *Config) UsesOTLPEndpoint() bool {
+	return c.OTLPExporterProtocol == ProtocolGRPC || c.OTLPExporterProtocol == ProtocolHTTP
+}
+
+// HasCustomTLS reports whether a CA bundle or client certificate is configured.
+func (c *Config) HasCustomTLS() bool {
+	return c.TLSCAFile != "" || c.TLSClientCertFile != ""
+}
+
 // WithEnvironment sets the deployment environment
 func (c *Config) WithEnvironment(env string) *Config {
 	c.Environment = env
@@ -184,6 +635,58 @@ func (c *Config) WithSampling(samplingType SamplingType, ratio float64) *Config
 	return c
 }
 
+// WithRateLimitedSampling configures rate_limited sampling of at most tracesPerSecond root traces
+func (c *Config) WithRateLimitedSampling(tracesPerSecond float64) *Config {
+	c.SamplingType = SamplingRateLimited
+	c.SamplingRateLimit = tracesPerSecond
+	return c
+}
+
+// exporterProtocolFromEnv resolves the exporter protocol. OTEL_TRACES_EXPORTER
+// selects the exporter family; "otlp" (or unset) defers to OTEL_EXPORTER_OTLP_PROTOCOL
+// and "none" discards spans. The variable may list several exporters: "otlp" is the
+// primary one if listed, otherwise the first entry, and the other entries are
+// returned as additional exporters named after their protocol. "none" in a list
+// with other exporters is ignored.
+func exporterProtocolFromEnv() (string, []ExporterConfig) {
+	otlpProtocol := getEnv(EnvOTLPExporterProtocol, DefaultOTLPExporterProtocol)
+
+	var protocols []string
+	var none bool
+	for _, exporter := range strings.Split(os.Getenv(EnvTracesExporter), ",") {
+		switch exporter = strings.ToLower(strings.TrimSpace(exporter)); exporter {
+		case "":
+		case ProtocolNone:
+			none = true
+		case "otlp":
+			exporter = otlpProtocol
+			fallthrough
+		default:
+			if !contains(protocols, exporter) {
+				protocols = append(protocols, exporter)
+			}
+		}
+	}
+	if len(protocols) == 0 {
+		if none {
+			return ProtocolNone, nil
+		}
+		return otlpProtocol, nil
+	}
+
+	primary := protocols[0]
+	if contains(protocols, otlpProtocol) {
+		primary = otlpProtocol
+	}
+	var additional []ExporterConfig
+	for _, protocol := range protocols {
+		if protocol != primary {
+			additional = append(additional, ExporterConfig{Name: protocol, Protocol: protocol})
+		}
+	}
+	return primary, additional
+}
+
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
@@ -227,6 +730,21 @@ func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
 	return defaultValue
 }
 
+// getEnvMillis reads a timeout given in milliseconds, as the OpenTelemetry
+// specification defines for OTEL_EXPORTER_OTLP_TIMEOUT. Go duration strings
+// such as "10s" are accepted as well.
+func getEnvMillis(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if ms, err := strconv.Atoi(value); err == nil {
+			return time.Duration(ms) * time.Millisecond
+		}
+		if duration, err := time.ParseDuration(value); err == nil {
+			return duration
+		}
+	}
+	return defaultValue
+}
+
 func contains(slice []string, value string) bool {
 	for _, item := range slice {
 		if item == value {
@@ -240,16 +758,12 @@ func generateInstanceID() string {
 	return uuid.NewString()
 }
 
-// ParseSamplingType converts a string to SamplingType with validation
+// ParseSamplingType converts a string to SamplingType with validation. Both otelkit's
+// names and the values defined by the OpenTelemetry specification are accepted,
+// case-insensitively; unknown values fall back to DefaultSamplingType.
 func ParseSamplingType(s string) SamplingType {
-	switch SamplingType(s) {
-	case SamplingProbabilistic:
-		return SamplingProbabilistic
-	case SamplingAlwaysOn:
-		return SamplingAlwaysOn
-	case SamplingAlwaysOff:
-		return SamplingAlwaysOff
-	default:
-		return DefaultSamplingType
+	if samplingType := SamplingType(strings.ToLower(strings.TrimSpace(s))); samplingType.IsValid() {
+		return samplingType
 	}
+	return DefaultSamplingType
 }
@@ -2,6 +2,7 @@ package config
 
 import (
 	"os"
+	"strings"
 	"testing"
 	"time"
 )
@@ -120,7 +121,7 @@ func TestNewConfigFromEnv(t *testing.T) {
 				os.Setenv(key, value)
 			}
 
-			cfg := NewConfigFromEnv()
+			cfg, _ := NewConfigFromEnv()
 			tt.validate(t, cfg)
 
 			// Clean up environment variables for next test
@@ -390,3 +391,432 @@ func TestGenerateInstanceID(t *testing.T) {
 		t.Error("generateInstanceID() should return unique IDs")
 	}
 }
+
+func TestExporterProtocolFromEnv(t *testing.T) {
+	tests := []struct {
+		name           string
+		tracesExporter string
+		protocol       string
+		want           string
+		wantAdditional []string
+	}{
+		{name: "defaults to http", want: ProtocolHTTP},
+		{name: "otlp defers to protocol", tracesExporter: "otlp", protocol: "grpc", want: ProtocolGRPC},
+		{name: "console overrides protocol", tracesExporter: "console", protocol: "grpc", want: ProtocolConsole},
+		{name: "stdout_json", tracesExporter: "STDOUT_JSON", want: ProtocolStdoutJSON},
+		{name: "protocol stdout", protocol: "stdout", want: ProtocolStdout},
+		{name: "none disables export", tracesExporter: "none", want: ProtocolNone},
+		{name: "list with otlp primary", tracesExporter: "otlp, console", want: ProtocolHTTP, wantAdditional: []string{ProtocolConsole}},
+		{name: "otlp is primary anywhere in list", tracesExporter: "console,otlp,zipkin", protocol: "grpc", want: ProtocolGRPC, wantAdditional: []string{ProtocolConsole, ProtocolZipkin}},
+		{name: "list without otlp", tracesExporter: "zipkin,console,zipkin", want: ProtocolZipkin, wantAdditional: []string{ProtocolConsole}},
+		{name: "none ignored in list", tracesExporter: "none,console", want: ProtocolConsole},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(EnvTracesExporter, tt.tracesExporter)
+			t.Setenv(EnvOTLPExporterProtocol, tt.protocol)
+
+			got, additional := exporterProtocolFromEnv()
+			if got != tt.want {
+				t.Errorf("exporterProtocolFromEnv() = %q, want %q", got, tt.want)
+			}
+			var names []string
+			for _, e := range additional {
+				if e.Name != e.Protocol {
+					t.Errorf("Expected additional exporter %q to be named after its protocol %q", e.Name, e.Protocol)
+				}
+				names = append(names, e.Protocol)
+			}
+			if strings.Join(names, ",") != strings.Join(tt.wantAdditional, ",") {
+				t.Errorf("Expected additional exporters %v, got %v", tt.wantAdditional, names)
+			}
+		})
+	}
+}
+
+func TestNewConfigFromEnv_TracesExporterList(t *testing.T) {
+	t.Setenv(EnvTracesExporter, "otlp,console")
+	t.Setenv(EnvExporters, "vendor")
+	t.Setenv("OTEL_EXPORTER_VENDOR_ENDPOINT", "vendor:4317")
+
+	cfg, _ := NewConfigFromEnv()
+	if cfg.OTLPExporterProtocol != ProtocolHTTP || len(cfg.Exporters) != 2 ||
+		cfg.Exporters[0].Name != ProtocolConsole || cfg.Exporters[1].Name != "vendor" {
+		t.Fatalf("Unexpected exporters: %s %+v", cfg.OTLPExporterProtocol, cfg.Exporters)
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Expected the list to validate, got %v", err)
+	}
+
+	t.Setenv(EnvTracesExporter, "none")
+	t.Setenv(EnvExporters, "")
+	cfg, _ = NewConfigFromEnv()
+	if err := cfg.Validate(); err != nil || cfg.UsesOTLPEndpoint() {
+		t.Errorf("Expected none to validate without an OTLP endpoint, got %v", err)
+	}
+}
+
+func TestConfig_ValidateConsoleWithoutEndpoint(t *testing.T) {
+	cfg := NewConfig("test-service", "1.0.0")
+	cfg.OTLPExporterProtocol = ProtocolConsole
+	cfg.OTLPExporterEndpoint = ""
+
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate() unexpected error for console protocol: %v", err)
+	}
+
+	cfg.OTLPExporterProtocol = ProtocolHTTP
+	if err := cfg.Validate(); err == nil {
+		t.Error("Validate() expected error for http protocol without endpoint")
+	}
+}
+
+func TestNewConfigFromEnv_TLS(t *testing.T) {
+	t.Setenv(EnvOTLPCertificate, "/etc/otel/ca.pem")
+	t.Setenv(EnvOTLPClientCert, "/etc/otel/client.pem")
+	t.Setenv(EnvOTLPClientKey, "/etc/otel/client-key.pem")
+	t.Setenv(EnvTLSReloadInterval, "30s")
+
+	cfg, _ := NewConfigFromEnv()
+	if cfg.TLSCAFile != "/etc/otel/ca.pem" || cfg.TLSClientCertFile != "/etc/otel/client.pem" || cfg.TLSClientKeyFile != "/etc/otel/client-key.pem" {
+		t.Errorf("Unexpected TLS files: %s %s %s", cfg.TLSCAFile, cfg.TLSClientCertFile, cfg.TLSClientKeyFile)
+	}
+	if cfg.TLSReloadInterval != 30*time.Second {
+		t.Errorf("Expected reload interval 30s, got %v", cfg.TLSReloadInterval)
+	}
+	if !cfg.HasCustomTLS() {
+		t.Error("Expected HasCustomTLS to be true")
+	}
+}
+
+func TestConfig_ValidateIncompleteClientCert(t *testing.T) {
+	cfg := NewConfig("test-service", "1.0.0")
+	cfg.TLSClientCertFile = "/etc/otel/client.pem"
+
+	err := cfg.Validate()
+	if configErr, ok := err.(*ConfigError); !ok || configErr.Field != "TLSClientCertFile" {
+		t.Errorf("Expected TLSClientCertFile ConfigError, got %v", err)
+	}
+}
+
+func TestNewConfigFromEnv_Retry(t *testing.T) {
+	t.Setenv(EnvRetryEnabled, "false")
+	t.Setenv(EnvRetryInitialInterval, "100ms")
+	t.Setenv(EnvRetryMaxInterval, "2s")
+	t.Setenv(EnvRetryMaxElapsedTime, "10s")
+
+	cfg, _ := NewConfigFromEnv()
+	if !cfg.RetryDisabled {
+		t.Error("Expected retry to be disabled")
+	}
+	if cfg.RetryInitialInterval != 100*time.Millisecond || cfg.RetryMaxInterval != 2*time.Second || cfg.RetryMaxElapsedTime != 10*time.Second {
+		t.Errorf("Unexpected retry intervals: %v %v %v", cfg.RetryInitialInterval, cfg.RetryMaxInterval, cfg.RetryMaxElapsedTime)
+	}
+}
+
+func TestConfig_ValidateRetry(t *testing.T) {
+	cfg := NewConfig("test-service", "1.0.0")
+	cfg.RetryInitialInterval = time.Minute
+	cfg.RetryMaxInterval = time.Second
+
+	err := cfg.Validate()
+	if configErr, ok := err.(*ConfigError); !ok || configErr.Field != "RetryInitialInterval" {
+		t.Errorf("Expected RetryInitialInterval ConfigError, got %v", err)
+	}
+
+	// A zero maximum selects the default of 30s, which a 5m initial interval exceeds.
+	cfg.RetryInitialInterval = 5 * time.Minute
+	cfg.RetryMaxInterval = 0
+	if configErr, ok := cfg.Validate().(*ConfigError); !ok || configErr.Field != "RetryInitialInterval" {
+		t.Errorf("Expected RetryInitialInterval ConfigError against the default maximum, got %v", cfg.Validate())
+	}
+	cfg.RetryInitialInterval = 0
+	cfg.RetryMaxInterval = time.Second
+	if configErr, ok := cfg.Validate().(*ConfigError); !ok || configErr.Field != "RetryInitialInterval" {
+		t.Errorf("Expected RetryInitialInterval ConfigError for a maximum below the default initial interval, got %v", cfg.Validate())
+	}
+	cfg.RetryMaxInterval = 0
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Expected default intervals to be valid, got %v", err)
+	}
+}
+
+func TestNewConfigFromEnv_Zipkin(t *testing.T) {
+	t.Setenv(EnvTracesExporter, "zipkin")
+	t.Setenv(EnvZipkinEndpoint, "http://zipkin:9411/api/v2/spans")
+
+	cfg, _ := NewConfigFromEnv()
+	if cfg.OTLPExporterProtocol != ProtocolZipkin {
+		t.Errorf("Expected zipkin protocol, got %s", cfg.OTLPExporterProtocol)
+	}
+	if cfg.ZipkinEndpoint != "http://zipkin:9411/api/v2/spans" {
+		t.Errorf("Unexpected zipkin endpoint %s", cfg.ZipkinEndpoint)
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+}
+
+func TestConfig_ValidateZipkinEndpoint(t *testing.T) {
+	cfg := NewConfig("test-service", "1.0.0")
+	cfg.OTLPExporterProtocol = ProtocolZipkin
+	cfg.ZipkinEndpoint = "zipkin:9411"
+
+	err := cfg.Validate()
+	if configErr, ok := err.(*ConfigError); !ok || configErr.Field != "ZipkinEndpoint" {
+		t.Errorf("Expected ZipkinEndpoint ConfigError, got %v", err)
+	}
+}
+
+func TestNewConfigFromEnv_RateLimited(t *testing.T) {
+	t.Setenv(EnvSamplingType, "rate_limited")
+
+	cfg, _ := NewConfigFromEnv()
+	if cfg.SamplingType != SamplingRateLimited {
+		t.Errorf("Expected rate_limited sampling, got %s", cfg.SamplingType)
+	}
+	if cfg.SamplingRateLimit != DefaultSamplingRate || cfg.SamplingRatio != DefaultSamplingRatio {
+		t.Errorf("Expected default rate %d and ratio %v, got %v and %v", DefaultSamplingRate, DefaultSamplingRatio, cfg.SamplingRateLimit, cfg.SamplingRatio)
+	}
+
+	t.Setenv(EnvSamplingRatio, "250")
+	cfg, _ = NewConfigFromEnv()
+	if cfg.SamplingRateLimit != 250 {
+		t.Errorf("Expected rate 250, got %v", cfg.SamplingRateLimit)
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+}
+
+func TestConfig_ValidateRateLimited(t *testing.T) {
+	cfg := NewConfig("test-service", "1.0.0").WithRateLimitedSampling(0)
+
+	err := cfg.Validate()
+	if configErr, ok := err.(*ConfigError); !ok || configErr.Field != "SamplingRateLimit" || configErr.Message != ErrInvalidSamplingRate {
+		t.Errorf("Expected SamplingRateLimit ConfigError, got %v", err)
+	}
+
+	// The ratio is not a rate: a rate above 1 is valid, a ratio above 1 is not.
+	cfg.SamplingRateLimit = 50
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+	cfg.SamplingRatio = 50
+	err = cfg.Validate()
+	if configErr, ok := err.(*ConfigError); !ok || configErr.Field != "SamplingRatio" {
+		t.Errorf("Expected SamplingRatio ConfigError, got %v", err)
+	}
+}
+
+func TestParseSamplingType_SpecValues(t *testing.T) {
+	tests := map[string]SamplingType{
+		"traceidratio":             SamplingTraceIDRatio,
+		"parentbased_traceidratio": SamplingParentBasedTraceIDRatio,
+		"parentbased_always_on":    SamplingParentBasedAlwaysOn,
+		"parentbased_always_off":   SamplingParentBasedAlwaysOff,
+		" ParentBased_Always_On ":  SamplingParentBasedAlwaysOn,
+		"always_on":                SamplingAlwaysOn,
+		"xray":                     DefaultSamplingType,
+	}
+	for input, want := range tests {
+		if got := ParseSamplingType(input); got != want {
+			t.Errorf("ParseSamplingType(%q) = %s, want %s", input, got, want)
+		}
+	}
+}
+
+func TestNewConfigFromEnv_SpecSamplerRatioDefault(t *testing.T) {
+	t.Setenv(EnvSamplingType, "parentbased_traceidratio")
+
+	cfg, _ := NewConfigFromEnv()
+	if cfg.SamplingRatio != DefaultSpecSamplingRatio {
+		t.Errorf("Expected spec default ratio %v without %s, got %v", DefaultSpecSamplingRatio, EnvSamplingRatio, cfg.SamplingRatio)
+	}
+
+	t.Setenv(EnvSamplingRatio, "0.1")
+	if cfg, _ = NewConfigFromEnv(); cfg.SamplingRatio != 0.1 {
+		t.Errorf("Expected ratio 0.1, got %v", cfg.SamplingRatio)
+	}
+}
+
+func TestNewConfigFromEnv_JaegerRemoteSamplerArg(t *testing.T) {
+	t.Setenv(EnvSamplingType, "jaeger_remote")
+
+	cfg, _ := NewConfigFromEnv()
+	if cfg.SamplingRatio != DefaultJaegerRemoteRatio || cfg.JaegerRemoteSamplingEndpoint != DefaultJaegerRemoteSamplingEndpoint ||
+		cfg.JaegerRemoteSamplingInterval != DefaultJaegerRemoteSamplingInterval {
+		t.Errorf("Unexpected defaults: %v %s %v", cfg.SamplingRatio, cfg.JaegerRemoteSamplingEndpoint, cfg.JaegerRemoteSamplingInterval)
+	}
+
+	t.Setenv(EnvSamplingType, "parentbased_jaeger_remote")
+	t.Setenv(EnvSamplingRatio, "endpoint=http://jaeger:14268/api/sampling, pollingIntervalMs=5000,initialSamplingRate=0.25")
+	cfg, warnings := NewConfigFromEnv()
+	if len(warnings) != 0 {
+		t.Errorf("Expected no warnings, got %v", warnings)
+	}
+	if cfg.SamplingType != SamplingParentBasedJaegerRemote || cfg.SamplingRatio != 0.25 ||
+		cfg.JaegerRemoteSamplingEndpoint != "http://jaeger:14268/api/sampling" || cfg.JaegerRemoteSamplingInterval != 5*time.Second {
+		t.Errorf("Unexpected config: %s %v %s %v", cfg.SamplingType, cfg.SamplingRatio, cfg.JaegerRemoteSamplingEndpoint, cfg.JaegerRemoteSamplingInterval)
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate failed: %v", err)
+	}
+
+	t.Setenv(EnvSamplingRatio, "pollingIntervalMs=soon,region=eu,initialSamplingRate=0.5")
+	cfg, warnings = NewConfigFromEnv()
+	if len(warnings) != 2 || cfg.SamplingRatio != 0.5 || cfg.JaegerRemoteSamplingInterval != DefaultJaegerRemoteSamplingInterval {
+		t.Errorf("Expected the two bad entries to be skipped with warnings, got %v (ratio %v, interval %v)",
+			warnings, cfg.SamplingRatio, cfg.JaegerRemoteSamplingInterval)
+	}
+}
+
+func TestNewConfigFromEnv_UnsupportedSamplerWarning(t *testing.T) {
+	t.Setenv(EnvSamplingType, "xray")
+
+	cfg, warnings := NewConfigFromEnv()
+	if cfg.SamplingType != DefaultSamplingType {
+		t.Errorf("Expected fallback to %s, got %s", DefaultSamplingType, cfg.SamplingType)
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0].Error(), `"xray"`) {
+		t.Errorf("Expected a warning naming the unsupported sampler, got %v", warnings)
+	}
+}
+
+func TestConfig_ValidateJaegerRemoteSampling(t *testing.T) {
+	tests := []struct {
+		name     string
+		endpoint string
+		interval time.Duration
+		field    string
+	}{
+		{"host without scheme", "localhost:5778", time.Minute, "JaegerRemoteSamplingEndpoint"},
+		{"unsupported scheme", "grpc://localhost:14250", time.Minute, "JaegerRemoteSamplingEndpoint"},
+		{"negative interval", "http://localhost:5778/sampling", -time.Second, "JaegerRemoteSamplingInterval"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := NewConfig("test-service", "1.0.0").WithSampling(SamplingJaegerRemote, 0.01)
+			cfg.JaegerRemoteSamplingEndpoint = tt.endpoint
+			cfg.JaegerRemoteSamplingInterval = tt.interval
+
+			err := cfg.Validate()
+			if configErr, ok := err.(*ConfigError); !ok || configErr.Field != tt.field {
+				t.Errorf("Expected %s ConfigError, got %v", tt.field, err)
+			}
+		})
+	}
+}
+
+func TestNewConfigFromEnv_Metrics(t *testing.T) {
+	cfg, _ := NewConfigFromEnv()
+	if cfg.MetricsEnabled() || cfg.MetricsExportInterval != DefaultMetricsExportInterval ||
+		cfg.MetricsTemporality != DefaultMetricsTemporality {
+		t.Errorf("Unexpected defaults: %s %v %s", cfg.MetricsExporter, cfg.MetricsExportInterval, cfg.MetricsTemporality)
+	}
+
+	t.Setenv(EnvMetricsExporter, "OTLP")
+	t.Setenv(EnvMetricsEndpoint, "http://collector:4318/custom/metrics")
+	t.Setenv(EnvMetricsInterval, "15000")
+	t.Setenv(EnvMetricsTimeout, "5000")
+	t.Setenv(EnvMetricsTemporality, "Delta")
+	cfg, _ = NewConfigFromEnv()
+	if !cfg.MetricsEnabled() || cfg.MetricsEndpoint != "http://collector:4318/custom/metrics" ||
+		cfg.MetricsExportInterval != 15*time.Second || cfg.MetricsExportTimeout != 5*time.Second ||
+		cfg.MetricsTemporality != TemporalityDelta {
+		t.Errorf("Unexpected config: %s %s %v %v %s", cfg.MetricsExporter, cfg.MetricsEndpoint,
+			cfg.MetricsExportInterval, cfg.MetricsExportTimeout, cfg.MetricsTemporality)
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate failed: %v", err)
+	}
+}
+
+func TestConfig_ValidateMetrics(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*Config)
+		field  string
+	}{
+		{"unknown exporter", func(c *Config) { c.MetricsExporter = "prometheus" }, "MetricsExporter"},
+		{"unknown temporality", func(c *Config) { c.MetricsTemporality = "gauge" }, "MetricsTemporality"},
+		{"negative interval", func(c *Config) { c.MetricsExportInterval = -time.Second }, "MetricsExportInterval"},
+		{"endpoint path without scheme", func(c *Config) { c.MetricsEndpoint = "collector:4318/v1/metrics" }, "MetricsEndpoint"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := NewConfig("test-service", "1.0.0")
+			tt.modify(cfg)
+
+			err := cfg.Validate()
+			if configErr, ok := err.(*ConfigError); !ok || configErr.Field != tt.field {
+				t.Errorf("Expected %s ConfigError, got %v", tt.field, err)
+			}
+		})
+	}
+}
+
+func TestNewConfigFromEnv_Logs(t *testing.T) {
+	if cfg, _ := NewConfigFromEnv(); cfg.LogsEnabled() {
+		t.Errorf("Expected logs to be disabled by default, got %q", cfg.LogsExporter)
+	}
+
+	t.Setenv(EnvLogsExporter, "otlp")
+	t.Setenv(EnvLogsEndpoint, "https://logs.example.com/v1/logs")
+	cfg, _ := NewConfigFromEnv()
+	if !cfg.LogsEnabled() || cfg.LogsEndpoint != "https://logs.example.com/v1/logs" {
+		t.Errorf("Unexpected config: %s %s", cfg.LogsExporter, cfg.LogsEndpoint)
+	}
+
+	cfg.LogsExporter = "loki"
+	if err, ok := cfg.Validate().(*ConfigError); !ok || err.Field != "LogsExporter" {
+		t.Errorf("Expected LogsExporter ConfigError, got %v", err)
+	}
+}
+
+func TestNewConfigFromEnv_SpanLimits(t *testing.T) {
+	t.Setenv(EnvAttributeCountLimit, "64")
+	t.Setenv(EnvAttributeValueLengthLimit, "2048")
+	t.Setenv(EnvSpanAttributeValueLengthLimit, "1024")
+	t.Setenv(EnvSpanEventCountLimit, "16")
+	t.Setenv(EnvLinkAttributeCountLimit, "-1")
+
+	// Programmatic configs do not read the environment.
+	cfg := NewConfig("svc", "1.0.0")
+	if cfg.SpanAttributeCountLimit != DefaultSpanAttributeCountLimit ||
+		cfg.SpanAttributeValueLengthLimit != DefaultSpanAttributeValueLengthLimit {
+		t.Errorf("Unexpected defaults: %d %d", cfg.SpanAttributeCountLimit, cfg.SpanAttributeValueLengthLimit)
+	}
+
+	cfg, _ = NewConfigFromEnv()
+	if cfg.SpanAttributeCountLimit != 64 || cfg.SpanAttributeValueLengthLimit != 1024 ||
+		cfg.SpanEventCountLimit != 16 || cfg.SpanLinkCountLimit != DefaultSpanLinkCountLimit ||
+		cfg.LinkAttributeCountLimit != -1 {
+		t.Errorf("Unexpected limits: %+v", cfg)
+	}
+}
+
+func TestNewConfigFromEnv_Health(t *testing.T) {
+	t.Setenv(EnvStartupProbe, "FAIL")
+	t.Setenv(EnvStartupProbeTimeout, "2s")
+	t.Setenv(EnvHealthFailingAfter, "30s")
+	cfg, _ := NewConfigFromEnv()
+	if cfg.StartupProbe != StartupProbeFail || cfg.StartupProbeTimeout != 2*time.Second ||
+		cfg.HealthFailingAfter != 30*time.Second {
+		t.Errorf("Unexpected health settings: %q %v %v", cfg.StartupProbe, cfg.StartupProbeTimeout, cfg.HealthFailingAfter)
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate failed: %v", err)
+	}
+
+	cfg.StartupProbe = "panic"
+	if configErr, ok := cfg.Validate().(*ConfigError); !ok || configErr.Field != "StartupProbe" {
+		t.Errorf("Expected a StartupProbe ConfigError, got %v", cfg.Validate())
+	}
+	cfg.StartupProbe = StartupProbeWarn
+	cfg.HealthFailingAfter = -time.Second
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected a negative failing threshold to be rejected")
+	}
+}
@@ -14,11 +14,26 @@ import (
 // This custom type provides type safety and prevents runtime errors from typos.
 type SamplingType string
 
-// Defined sampling types for type safety
+// Defined sampling types for type safety. "probabilistic" is otelkit's name for
+// parentbased_traceidratio; always_on and always_off ignore the parent's decision.
 const (
 	SamplingProbabilistic SamplingType = "probabilistic"
 	SamplingAlwaysOn      SamplingType = "always_on"
 	SamplingAlwaysOff     SamplingType = "always_off"
+	SamplingRateLimited   SamplingType = "rate_limited"
+	SamplingRuleBased     SamplingType = "rule_based"
+)
+
+// Sampling types defined by the OpenTelemetry specification for OTEL_TRACES_SAMPLER.
+// The parentbased_ variants apply the named sampler to root spans only and let
+// child spans follow their parent's decision.
+const (
+	SamplingTraceIDRatio            SamplingType = "traceidratio"
+	SamplingParentBasedAlwaysOn     SamplingType = "parentbased_always_on"
+	SamplingParentBasedAlwaysOff    SamplingType = "parentbased_always_off"
+	SamplingParentBasedTraceIDRatio SamplingType = "parentbased_traceidratio"
+	SamplingJaegerRemote            SamplingType = "jaeger_remote"
+	SamplingParentBasedJaegerRemote SamplingType = "parentbased_jaeger_remote"
 )
 
 // String returns the string representation of the sampling type
@@ -29,13 +44,50 @@ func (s SamplingType) String() string {
 // IsValid checks if the sampling type is one of the defined constants
 func (s SamplingType) IsValid() bool {
 	switch s {
-	case SamplingProbabilistic, SamplingAlwaysOn, SamplingAlwaysOff:
+	case SamplingProbabilistic, SamplingAlwaysOn, SamplingAlwaysOff, SamplingRateLimited, SamplingRuleBased,
+		SamplingTraceIDRatio, SamplingParentBasedAlwaysOn, SamplingParentBasedAlwaysOff, SamplingParentBasedTraceIDRatio,
+		SamplingJaegerRemote, SamplingParentBasedJaegerRemote:
 		return true
 	default:
 		return false
 	}
 }
 
+// Metrics exporters selectable with OTEL_METRICS_EXPORTER.
+const (
+	MetricsExporterOTLP = "otlp"
+	MetricsExporterNone = "none"
+)
+
+// Logs exporters selectable with OTEL_LOGS_EXPORTER.
+const (
+	LogsExporterOTLP = "otlp"
+	LogsExporterNone = "none"
+)
+
+// OTLP/HTTP signal paths appended to the path of the base OTLPExporterEndpoint, as the
+// OpenTelemetry specification defines for OTEL_EXPORTER_OTLP_ENDPOINT.
+const (
+	TracesSignalPath  = "/v1/traces"
+	MetricsSignalPath = "/v1/metrics"
+	LogsSignalPath    = "/v1/logs"
+)
+
+// Startup probe modes selectable with OTEL_EXPORTER_STARTUP_PROBE.
+const (
+	StartupProbeNone = "none" // Do not probe the collector at startup
+	StartupProbeWarn = "warn" // Report an unreachable collector and continue
+	StartupProbeFail = "fail" // Fail provider creation if the collector is unreachable
+)
+
+// Aggregation temporality preferences for OTLP metric exporters, as defined for
+// OTEL_EXPORTER_OTLP_METRICS_TEMPORALITY_PREFERENCE.
+const (
+	TemporalityCumulative = "cumulative"
+	TemporalityDelta      = "delta"
+	TemporalityLowMemory  = "lowmemory"
+)
+
 // Service configuration constants
 const (
 	DefaultServiceName          = "unknown-service"
@@ -44,22 +96,100 @@ const (
 	DefaultOTLPExporterEndpoint = "localhost:4318"
 	DefaultSamplingRatio        = 0.2
 	DefaultSamplingType         = SamplingProbabilistic
-	DefaultOTLPExporterProtocol = "http"
+	DefaultSamplingRate         = 100   // Root traces per second for rate_limited sampling
+	DefaultSpecSamplingRatio    = 1.0   // Ratio for traceidratio types without OTEL_TRACES_SAMPLER_ARG, per the specification
+	DefaultJaegerRemoteRatio    = 0.001 // Initial ratio for jaeger_remote types without initialSamplingRate, per the specification
+	DefaultOTLPExporterProtocol = ProtocolHTTP
 	DefaultBatchTimeout         = 5 * time.Second
 	DefaultExportTimeout        = 30 * time.Second
+	DefaultOTLPTimeout          = 10 * time.Second // Request timeout of the OTLP exporters when OTLPTimeout is zero
 	DefaultMaxExportBatchSize   = 512
 	DefaultMaxQueueSize         = 2048
+	DefaultTLSReloadInterval    = time.Minute
+	DefaultRetryInitialInterval = 5 * time.Second
+	DefaultRetryMaxInterval     = 30 * time.Second
+	DefaultRetryMaxElapsedTime  = time.Minute
+	DefaultFileExporterPath     = "otelkit-traces.jsonl"
+	DefaultFileExporterMaxSize  = 100 * 1024 * 1024
+	DefaultFileExporterMaxAge   = 24 * time.Hour
+	DefaultFileExporterBackups  = 5
+	DefaultZipkinEndpoint       = "http://localhost:9411/api/v2/spans"
+
+	DefaultPersistentQueueMaxSize       = 256 * 1024 * 1024
+	DefaultPersistentQueueRetryInterval = 5 * time.Second
+
+	DefaultJaegerRemoteSamplingEndpoint = "http://localhost:5778/sampling"
+	DefaultJaegerRemoteSamplingInterval = time.Minute
+	DefaultJaegerRemoteSamplingTimeout  = 10 * time.Second
+	DefaultJaegerRemoteMaxFailedPolls   = 3 // Consecutive failed polls after which the configured ratio applies again
+
+	DefaultMetricsExporter       = MetricsExporterNone
+	DefaultMetricsExportInterval = time.Minute
+	DefaultMetricsExportTimeout  = 30 * time.Second
+	DefaultMetricsTemporality    = TemporalityCumulative
+
+	DefaultLogsExporter = LogsExporterNone
+
+	DefaultRuntimeMetricsInterval = 15 * time.Second
+
+	RedactionMask = "[REDACTED]" // Replacement for values masked by redaction rules
+
+	DefaultSpanAttributeCountLimit       = 128
+	DefaultSpanAttributeValueLengthLimit = -1 // unlimited
+	DefaultSpanEventCountLimit           = 128
+	DefaultSpanLinkCountLimit            = 128
+	DefaultEventAttributeCountLimit      = 128
+	DefaultLinkAttributeCountLimit       = 128
+
+	TruncatedValueSuffix = "...[truncated]" // Appended to attribute values cut to the length limit
+
+	DefaultStartupProbe        = StartupProbeNone
+	DefaultStartupProbeTimeout = 5 * time.Second
+	DefaultHealthFailingAfter  = 2 * time.Minute
+
+	DefaultTailSamplingDecisionWait = 10 * time.Second
+	DefaultTailSamplingMaxTraces    = 10000
+	DefaultTailSamplingMaxSpans     = 1000
+)
+
+// Exporter protocol constants. "grpc" and "http" select the OTLP exporters; the
+// console variants write finished spans to stdout for local development,
+// "zipkin" sends Zipkin v2 JSON to a Zipkin-compatible collector and "none" discards
+// spans, like OTEL_TRACES_EXPORTER=none.
+const (
+	ProtocolGRPC       = "grpc"
+	ProtocolHTTP       = "http"
+	ProtocolStdout     = "stdout"
+	ProtocolConsole    = "console"
+	ProtocolStdoutJSON = "stdout_json"
+	ProtocolFile       = "file"
+	ProtocolZipkin     = "zipkin"
+	ProtocolNone       = "none"
+)
+
+// OTLP compression constants
+const (
+	CompressionGzip = "gzip"
+	CompressionNone = "none"
 )
 
 // Valid configuration options
 var (
 	ValidEnvironments  = []string{"development", "staging", "production"}
-	ValidSamplingTypes = []string{"probabilistic", "always_on", "always_off"}
-	ValidHTTPMethods   = []string{
+	ValidSamplingTypes = []string{
+		"probabilistic", "always_on", "always_off", "rate_limited", "rule_based",
+		"traceidratio", "parentbased_always_on", "parentbased_always_off", "parentbased_traceidratio",
+	}
+	ValidHTTPMethods = []string{
 		http.MethodHead, http.MethodPost, http.MethodPut,
 		http.MethodDelete, http.MethodPatch, http.MethodOptions,
 	}
-	ValidOTLPProtocols = []string{"grpc", "http"}
+	ValidCompressions  = []string{"", CompressionGzip, CompressionNone}
+	ValidOTLPProtocols = []string{
+		ProtocolGRPC, ProtocolHTTP,
+		ProtocolStdout, ProtocolConsole, ProtocolStdoutJSON,
+		ProtocolFile, ProtocolZipkin, ProtocolNone,
+	}
 )
 
 // OpenTelemetry semantic convention constants
@@ -77,8 +207,58 @@ const (
 	ErrInvalidEnvironment      = "invalid environment"
 	ErrInvalidSamplingType     = "invalid sampling type"
 	ErrInvalidSamplingRatio    = "sampling ratio must be between 0 and 1"
+	ErrInvalidSamplingRate     = "rate limited sampling requires a positive number of traces per second"
+	ErrInvalidSamplingPattern  = "sampling rule pattern is not a valid glob or regular expression"
+	ErrInvalidSpanKind         = "span kind must be server, client, producer, consumer or internal"
+	ErrSamplingRulesFormat     = "sampling rules file must have a .json, .yaml or .yml extension"
 	ErrInvalidExporterProtocol = "invalid exporter protocol"
 	ErrInvalidExporterEndpoint = "exporter endpoint is required"
+	ErrFileExporterPath        = "file exporter path is required"
+	ErrZipkinEndpointURL       = "zipkin endpoint must be an http or https URL"
+	ErrInvalidFileRotation     = "file exporter size, age and backup limits must not be negative"
+	ErrExporterNameRequired    = "exporter name is required"
+	ErrInvalidHeader           = "invalid header name or value"
+	ErrInvalidCompression      = "compression must be 'gzip' or 'none'"
+	ErrInvalidExporterTimeout  = "exporter timeout must not be negative"
+	ErrIncompleteClientCert    = "client certificate and client key must be set together"
+	ErrInvalidPersistentQueue  = "persistent queue size limit must not be negative"
+	ErrInvalidRetryInterval    = "retry intervals must not be negative and the initial interval must not exceed the maximum"
+	ErrDuplicateExporterName   = "exporter name must be unique"
+	ErrInvalidTailSampling     = "tail sampling decision wait and limits must not be negative"
+	ErrJaegerRemoteEndpointURL = "jaeger remote sampling endpoint must be an http or https URL"
+	ErrInvalidJaegerRemotePoll = "jaeger remote sampling polling interval must not be negative"
+	ErrInvalidMetricsExporter  = "metrics exporter must be 'otlp' or 'none'"
+	ErrInvalidTemporality      = "metrics temporality must be 'cumulative', 'delta' or 'lowmemory'"
+	ErrInvalidMetricsInterval  = "metrics export interval and timeout must not be negative"
+	ErrInvalidLogsExporter     = "logs exporter must be 'otlp' or 'none'"
+	ErrInvalidRuntimeInterval  = "runtime metrics interval must not be negative"
+	ErrRuntimeMetricsDisabled  = "runtime metrics require metrics to be enabled"
+	ErrInvalidRedactionRule    = "redaction rule must select attribute keys, a value pattern or query parameters"
+	ErrInvalidRedactionAction  = "redaction action must be drop, mask or hash"
+	ErrInvalidStartupProbe     = "startup probe must be 'none', 'warn' or 'fail'"
+	ErrInvalidHealthSettings   = "startup probe timeout and health failing threshold must not be negative"
+
+	ErrMalformedEndpoint         = "endpoint URL is malformed"
+	ErrInvalidEndpointScheme     = "endpoint URL scheme must be http or https"
+	ErrEndpointHostRequired      = "endpoint URL host is required"
+	ErrInvalidEndpointPort       = "endpoint port must be a number between 1 and 65535"
+	ErrEndpointQuery             = "endpoint URL must not contain a query or fragment"
+	ErrEndpointPathWithoutScheme = "endpoint with a path must be a URL with an http or https scheme"
+)
+
+// Per-exporter environment variable pattern for the destinations listed in
+// OTEL_EXPORTERS. The placeholder is the upper-cased exporter name with '-' and
+// '.' replaced by '_', e.g. OTEL_EXPORTER_VENDOR_ENDPOINT.
+const (
+	EnvExporterPrefix             = "OTEL_EXPORTER_"
+	EnvExporterSuffixProtocol     = "_PROTOCOL"
+	EnvExporterSuffixEndpoint     = "_ENDPOINT"
+	EnvExporterSuffixInsecure     = "_INSECURE"
+	EnvExporterSuffixHeaders      = "_HEADERS"
+	EnvExporterSuffixBatchTimeout = "_BSP_TIMEOUT"
+	EnvExporterSuffixTimeout      = "_TIMEOUT"
+	EnvExporterSuffixMaxBatchSize = "_BSP_MAX_EXPORT_BATCH_SIZE"
+	EnvExporterSuffixMaxQueueSize = "_BSP_MAX_QUEUE_SIZE"
 )
 
 // Environment variable constants
@@ -87,13 +267,55 @@ const (
 	EnvServiceVersion       = "OTEL_SERVICE_VERSION"
 	EnvEnvironment          = "OTEL_ENVIRONMENT"
 	EnvOTLPExporterEndpoint = "OTEL_EXPORTER_OTLP_ENDPOINT"
+	EnvOTLPTracesEndpoint   = "OTEL_EXPORTER_OTLP_TRACES_ENDPOINT"
 	EnvOTLPExporterInsecure = "OTEL_EXPORTER_OTLP_INSECURE"
 	EnvOTLPExporterProtocol = "OTEL_EXPORTER_OTLP_PROTOCOL"
+	EnvOTLPExporterHeaders  = "OTEL_EXPORTER_OTLP_HEADERS"
+	EnvOTLPCompression      = "OTEL_EXPORTER_OTLP_COMPRESSION"
+	EnvOTLPTimeout          = "OTEL_EXPORTER_OTLP_TIMEOUT"
+	EnvOTLPCertificate      = "OTEL_EXPORTER_OTLP_CERTIFICATE"
+	EnvOTLPClientCert       = "OTEL_EXPORTER_OTLP_CLIENT_CERTIFICATE"
+	EnvOTLPClientKey        = "OTEL_EXPORTER_OTLP_CLIENT_KEY"
+	EnvTLSReloadInterval    = "OTEL_EXPORTER_OTLP_CERTIFICATE_RELOAD_INTERVAL"
+	EnvRetryEnabled         = "OTEL_EXPORTER_OTLP_RETRY_ENABLED"
+	EnvRetryInitialInterval = "OTEL_EXPORTER_OTLP_RETRY_INITIAL_INTERVAL"
+	EnvRetryMaxInterval     = "OTEL_EXPORTER_OTLP_RETRY_MAX_INTERVAL"
+	EnvRetryMaxElapsedTime  = "OTEL_EXPORTER_OTLP_RETRY_MAX_ELAPSED_TIME"
+	EnvTracesExporter       = "OTEL_TRACES_EXPORTER"
 	EnvBatchTimeout         = "OTEL_BSP_TIMEOUT"
 	EnvExportTimeout        = "OTEL_EXPORTER_TIMEOUT"
 	EnvMaxExportBatchSize   = "OTEL_BSP_MAX_EXPORT_BATCH_SIZE"
 	EnvMaxQueueSize         = "OTEL_BSP_MAX_QUEUE_SIZE"
 	EnvSamplingType         = "OTEL_TRACES_SAMPLER"
 	EnvSamplingRatio        = "OTEL_TRACES_SAMPLER_ARG"
+	EnvSamplingRulesFile    = "OTEL_TRACES_SAMPLER_RULES_FILE"
 	EnvInstanceID           = "OTEL_RESOURCE_ATTRIBUTES_SERVICE_INSTANCE_ID"
+	EnvFileExporterPath     = "OTEL_EXPORTER_FILE_PATH"
+	EnvFileExporterMaxSize  = "OTEL_EXPORTER_FILE_MAX_SIZE"
+	EnvFileExporterMaxAge   = "OTEL_EXPORTER_FILE_MAX_AGE"
+	EnvFileExporterBackups  = "OTEL_EXPORTER_FILE_MAX_BACKUPS"
+	EnvZipkinEndpoint       = "OTEL_EXPORTER_ZIPKIN_ENDPOINT"
+	EnvPersistentQueueDir   = "OTEL_EXPORTER_PERSISTENT_QUEUE_DIR"
+	EnvPersistentQueueSize  = "OTEL_EXPORTER_PERSISTENT_QUEUE_MAX_SIZE"
+	EnvExporters            = "OTEL_EXPORTERS"
+	EnvMetricsExporter      = "OTEL_METRICS_EXPORTER"
+	EnvMetricsEndpoint      = "OTEL_EXPORTER_OTLP_METRICS_ENDPOINT"
+	EnvMetricsInterval      = "OTEL_METRIC_EXPORT_INTERVAL"
+	EnvMetricsTimeout       = "OTEL_METRIC_EXPORT_TIMEOUT"
+	EnvMetricsTemporality   = "OTEL_EXPORTER_OTLP_METRICS_TEMPORALITY_PREFERENCE"
+	EnvLogsExporter         = "OTEL_LOGS_EXPORTER"
+	EnvLogsEndpoint         = "OTEL_EXPORTER_OTLP_LOGS_ENDPOINT"
+
+	EnvAttributeCountLimit           = "OTEL_ATTRIBUTE_COUNT_LIMIT"
+	EnvAttributeValueLengthLimit     = "OTEL_ATTRIBUTE_VALUE_LENGTH_LIMIT"
+	EnvSpanAttributeCountLimit       = "OTEL_SPAN_ATTRIBUTE_COUNT_LIMIT"
+	EnvSpanAttributeValueLengthLimit = "OTEL_SPAN_ATTRIBUTE_VALUE_LENGTH_LIMIT"
+	EnvSpanEventCountLimit           = "OTEL_SPAN_EVENT_COUNT_LIMIT"
+	EnvSpanLinkCountLimit            = "OTEL_SPAN_LINK_COUNT_LIMIT"
+	EnvEventAttributeCountLimit      = "OTEL_EVENT_ATTRIBUTE_COUNT_LIMIT"
+	EnvLinkAttributeCountLimit       = "OTEL_LINK_ATTRIBUTE_COUNT_LIMIT"
+
+	EnvStartupProbe        = "OTEL_EXPORTER_STARTUP_PROBE"
+	EnvStartupProbeTimeout = "OTEL_EXPORTER_STARTUP_PROBE_TIMEOUT"
+	EnvHealthFailingAfter  = "OTEL_EXPORTER_HEALTH_FAILING_AFTER"
 )
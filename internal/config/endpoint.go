@@ -0,0 +1,127 @@
+package config
+
+import (
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Endpoint is a collector endpoint split into the parts the OTLP exporters need.
+//
+// Endpoints are accepted in two forms: a bare "host:port" as before, or a full URL
+// such as "https://collector.example.com/custom/v1/traces". For URLs the scheme
+// decides between TLS (https) and plaintext (http). The HTTP exporters send to the
+// path of a signal-specific endpoint as-is and append the signal path to the path of
+// the base endpoint (see SignalEndpoint); gRPC has no notion of a path and ignores it.
+type Endpoint struct {
+	Scheme string // "http" or "https" for URL endpoints, empty for host:port
+	Host   string // host or host:port
+	Path   string // URL path, empty to use the exporter default (/v1/traces)
+}
+
+// IsURL reports whether the endpoint was given as a full URL.
+func (e Endpoint) IsURL() bool {
+	return e.Scheme != ""
+}
+
+// Insecure reports whether the endpoint uses plaintext. The scheme of a URL endpoint
+// takes precedence; host:port endpoints fall back to the given insecure setting.
+func (e Endpoint) Insecure(fallback bool) bool {
+	if e.IsURL() {
+		return e.Scheme == "http"
+	}
+	return fallback
+}
+
+// ParseEndpoint parses a collector endpoint. On failure it returns a *ConfigError
+// for field whose message names the offending part of the endpoint; the endpoint
+// itself is never included, as URLs may carry credentials.
+func ParseEndpoint(field, endpoint string) (Endpoint, error) {
+	if !strings.Contains(endpoint, "://") {
+		if strings.Contains(endpoint, "/") {
+			return Endpoint{}, &ConfigError{Field: field, Message: ErrEndpointPathWithoutScheme}
+		}
+		if err := validateEndpointPort(field, endpoint); err != nil {
+			return Endpoint{}, err
+		}
+		return Endpoint{Host: endpoint}, nil
+	}
+
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return Endpoint{}, &ConfigError{Field: field, Message: ErrMalformedEndpoint}
+	}
+	scheme := strings.ToLower(u.Scheme)
+	if scheme != "http" && scheme != "https" {
+		return Endpoint{}, &ConfigError{Field: field, Message: ErrInvalidEndpointScheme + ": " + strconv.Quote(u.Scheme)}
+	}
+	if u.Hostname() == "" {
+		return Endpoint{}, &ConfigError{Field: field, Message: ErrEndpointHostRequired}
+	}
+	if err := validateEndpointPort(field, u.Host); err != nil {
+		return Endpoint{}, err
+	}
+	if u.RawQuery != "" || u.Fragment != "" {
+		return Endpoint{}, &ConfigError{Field: field, Message: ErrEndpointQuery}
+	}
+
+	path := u.Path
+	if path == "/" {
+		path = ""
+	}
+	return Endpoint{Scheme: scheme, Host: u.Host, Path: path}, nil
+}
+
+// validateEndpointPort checks the port of a host:port pair, if one is present.
+func validateEndpointPort(field, hostport string) error {
+	_, port, err := net.SplitHostPort(hostport)
+	if err != nil {
+		// No port: the exporter applies its default.
+		return nil
+	}
+	if n, err := strconv.Atoi(port); err != nil || n < 1 || n > 65535 {
+		return &ConfigError{Field: field, Message: ErrInvalidEndpointPort + ": " + strconv.Quote(port)}
+	}
+	return nil
+}
+
+// ParsedEndpoint parses OTLPExporterEndpoint. See ParseEndpoint.
+func (c *Config) ParsedEndpoint() (Endpoint, error) {
+	return ParseEndpoint("OTLPExporterEndpoint", c.OTLPExporterEndpoint)
+}
+
+// SignalEndpoint returns the endpoint of a signal: override as-is if it is set,
+// otherwise OTLPExporterEndpoint with signalPath appended to the path of a URL, as
+// the OpenTelemetry specification defines for OTEL_EXPORTER_OTLP_ENDPOINT. A
+// host:port base endpoint keeps an empty path, so the exporter's default applies.
+// field names the override in errors.
+func (c *Config) SignalEndpoint(field, override, signalPath string) (Endpoint, error) {
+	if override != "" {
+		return ParseEndpoint(field, override)
+	}
+	endpoint, err := c.ParsedEndpoint()
+	if err != nil {
+		return Endpoint{}, err
+	}
+	if endpoint.IsURL() {
+		endpoint.Path = strings.TrimSuffix(endpoint.Path, "/") + signalPath
+	}
+	return endpoint, nil
+}
+
+// TracesEndpoint returns the endpoint spans are exported to: OTLPTracesEndpoint, or
+// OTLPExporterEndpoint with /v1/traces appended. See SignalEndpoint.
+func (c *Config) TracesEndpoint() (Endpoint, error) {
+	return c.SignalEndpoint("OTLPTracesEndpoint", c.OTLPTracesEndpoint, TracesSignalPath)
+}
+
+// ExporterInsecure reports whether the OTLP exporter uses plaintext: the scheme of
+// a URL endpoint decides, otherwise OTLPExporterInsecure applies.
+func (c *Config) ExporterInsecure() bool {
+	endpoint, err := c.TracesEndpoint()
+	if err != nil {
+		return c.OTLPExporterInsecure
+	}
+	return endpoint.Insecure(c.OTLPExporterInsecure)
+}
@@ -0,0 +1,207 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ExporterConfig describes an additional export destination that receives the same
+// spans as the primary exporter. Each destination gets its own batch processor, so a
+// slow or failing backend does not stall the others.
+//
+// Zero-valued batch settings inherit the provider's batch settings. Fields that are
+// not part of ExporterConfig (file exporter settings and so on) are inherited from
+// the primary Config.
+type ExporterConfig struct {
+	Name     string  // Unique name used in errors and environment variables
+	Protocol string  // Exporter protocol, same values as OTLPExporterProtocol
+	Endpoint string  // Collector endpoint (host:port or URL, path used as-is), the target path for the file protocol or the spans URL for zipkin
+	Insecure bool    // Disable TLS (URL endpoints use their scheme instead)
+	Headers  Headers // Extra request headers, e.g. API keys
+
+	BatchTimeout       time.Duration // Batch timeout (0 inherits)
+	ExportTimeout      time.Duration // Export timeout (0 inherits)
+	MaxExportBatchSize int           // Maximum batch size (0 inherits)
+	MaxQueueSize       int           // Maximum queue size (0 inherits)
+}
+
+// ForExporter returns a copy of the configuration with the exporter settings of e
+// applied, so that exporter factories can treat every destination uniformly.
+func (c *Config) ForExporter(e ExporterConfig) *Config {
+	derived := *c
+	derived.OTLPExporterProtocol = e.Protocol
+	derived.OTLPExporterEndpoint = e.Endpoint
+	derived.OTLPTracesEndpoint = e.Endpoint
+	derived.OTLPExporterInsecure = e.Insecure
+	derived.OTLPExporterHeaders = e.Headers
+	derived.Exporters = nil
+	if e.Protocol == ProtocolFile && e.Endpoint != "" {
+		derived.FileExporterPath = e.Endpoint
+	}
+	if e.Protocol == ProtocolZipkin && e.Endpoint != "" {
+		derived.ZipkinEndpoint = e.Endpoint
+	}
+	if c.PersistentQueueDir != "" {
+		// Each destination replays independently, so each needs its own queue.
+		derived.PersistentQueueDir = filepath.Join(c.PersistentQueueDir, e.Name)
+	}
+	return &derived
+}
+
+// validateExporters checks every additional exporter for a unique name and a valid
+// protocol and endpoint.
+func (c *Config) validateExporters() error {
+	seen := make(map[string]bool, len(c.Exporters))
+	for i, e := range c.Exporters {
+		if e.Name == "" {
+			return &ConfigError{Field: fmt.Sprintf("Exporters[%d].Name", i), Message: ErrExporterNameRequired}
+		}
+		if seen[e.Name] {
+			return &ConfigError{Field: fmt.Sprintf("Exporters[%s].Name", e.Name), Message: ErrDuplicateExporterName}
+		}
+		seen[e.Name] = true
+
+		if !contains(ValidOTLPProtocols, e.Protocol) {
+			return &ConfigError{Field: fmt.Sprintf("Exporters[%s].Protocol", e.Name), Message: ErrInvalidExporterProtocol}
+		}
+		if derived := c.ForExporter(e); derived.UsesOTLPEndpoint() {
+			field := fmt.Sprintf("Exporters[%s].Endpoint", e.Name)
+			if e.Endpoint == "" {
+				return &ConfigError{Field: field, Message: ErrInvalidExporterEndpoint}
+			}
+			if _, err := ParseEndpoint(field, e.Endpoint); err != nil {
+				return err
+			}
+		}
+		if e.Protocol == ProtocolZipkin && e.Endpoint != "" {
+			field := fmt.Sprintf("Exporters[%s].Endpoint", e.Name)
+			endpoint, err := ParseEndpoint(field, e.Endpoint)
+			if err != nil {
+				return err
+			}
+			if !endpoint.IsURL() {
+				return &ConfigError{Field: field, Message: ErrZipkinEndpointURL}
+			}
+		}
+		if err := e.Headers.validate(fmt.Sprintf("Exporters[%s].Headers", e.Name)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// exportersFromEnv reads the additional exporters listed in OTEL_EXPORTERS, e.g.
+//
+//	OTEL_EXPORTERS=vendor
+//	OTEL_EXPORTER_VENDOR_PROTOCOL=grpc
+//	OTEL_EXPORTER_VENDOR_ENDPOINT=ingest.vendor.example:4317
+//	OTEL_EXPORTER_VENDOR_HEADERS=x-api-key=secret
+func exportersFromEnv() []ExporterConfig {
+	names := os.Getenv(EnvExporters)
+	if names == "" {
+		return nil
+	}
+
+	var exporters []ExporterConfig
+	for _, name := range strings.Split(names, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		prefix := EnvExporterPrefix + envName(name)
+		exporters = append(exporters, ExporterConfig{
+			Name:               name,
+			Protocol:           getEnv(prefix+EnvExporterSuffixProtocol, DefaultOTLPExporterProtocol),
+			Endpoint:           os.Getenv(prefix + EnvExporterSuffixEndpoint),
+			Insecure:           getEnvBool(prefix+EnvExporterSuffixInsecure, false),
+			Headers:            parseHeaders(os.Getenv(prefix + EnvExporterSuffixHeaders)),
+			BatchTimeout:       getEnvDuration(prefix+EnvExporterSuffixBatchTimeout, 0),
+			ExportTimeout:      getEnvDuration(prefix+EnvExporterSuffixTimeout, 0),
+			MaxExportBatchSize: getEnvInt(prefix+EnvExporterSuffixMaxBatchSize, 0),
+			MaxQueueSize:       getEnvInt(prefix+EnvExporterSuffixMaxQueueSize, 0),
+		})
+	}
+	return exporters
+}
+
+// envName converts an exporter name into its environment variable form.
+func envName(name string) string {
+	return strings.NewReplacer("-", "_", ".", "_").Replace(strings.ToUpper(name))
+}
+
+// redactedHeaderValue replaces header values whenever headers are printed.
+const redactedHeaderValue = "[REDACTED]"
+
+// Headers holds exporter request headers such as API keys. Header values are
+// credentials in most deployments, so they are redacted whenever the headers are
+// formatted with the fmt package (and therefore whenever a Config is printed).
+type Headers map[string]string
+
+// String returns the header names with redacted values.
+func (h Headers) String() string {
+	if len(h) == 0 {
+		return "map[]"
+	}
+	keys := make([]string, 0, len(h))
+	for k := range h {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	b.WriteString("map[")
+	for i, k := range keys {
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+		b.WriteString(k + ":" + redactedHeaderValue)
+	}
+	b.WriteByte(']')
+	return b.String()
+}
+
+// GoString implements fmt.GoStringer so that %#v is redacted as well.
+func (h Headers) GoString() string {
+	return "config.Headers" + strings.TrimPrefix(h.String(), "map")
+}
+
+// validate checks header names and values without ever including a value in the
+// returned error.
+func (h Headers) validate(field string) error {
+	for k, v := range h {
+		if k == "" || strings.ContainsAny(k, " \t\r\n:") {
+			return &ConfigError{Field: field + "[" + k + "]", Message: ErrInvalidHeader}
+		}
+		if strings.ContainsAny(v, "\r\n") {
+			return &ConfigError{Field: field + "[" + k + "]", Message: ErrInvalidHeader}
+		}
+	}
+	return nil
+}
+
+// parseHeaders parses a W3C Baggage style header list ("key1=value1,key2=value2")
+// as used by OTEL_EXPORTER_OTLP_HEADERS. Values may be URL-encoded. Malformed
+// entries are skipped.
+func parseHeaders(s string) Headers {
+	if strings.TrimSpace(s) == "" {
+		return nil
+	}
+	headers := make(Headers)
+	for _, pair := range strings.Split(s, ",") {
+		key, value, ok := strings.Cut(pair, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			continue
+		}
+		if decoded, err := url.PathUnescape(strings.TrimSpace(value)); err == nil {
+			value = decoded
+		}
+		headers[key] = strings.TrimSpace(value)
+	}
+	return headers
+}
@@ -0,0 +1,197 @@
+package config
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExportersFromEnv(t *testing.T) {
+	t.Setenv(EnvExporters, "vendor, local-agent")
+	t.Setenv("OTEL_EXPORTER_VENDOR_PROTOCOL", "grpc")
+	t.Setenv("OTEL_EXPORTER_VENDOR_ENDPOINT", "ingest.vendor.example:4317")
+	t.Setenv("OTEL_EXPORTER_VENDOR_HEADERS", "x-api-key=secret,x-team=obs%20team")
+	t.Setenv("OTEL_EXPORTER_VENDOR_BSP_TIMEOUT", "1s")
+	t.Setenv("OTEL_EXPORTER_LOCAL_AGENT_ENDPOINT", "localhost:4318")
+	t.Setenv("OTEL_EXPORTER_LOCAL_AGENT_INSECURE", "true")
+
+	exporters := exportersFromEnv()
+	if len(exporters) != 2 {
+		t.Fatalf("Expected 2 exporters, got %d", len(exporters))
+	}
+
+	vendor := exporters[0]
+	if vendor.Name != "vendor" || vendor.Protocol != "grpc" || vendor.Endpoint != "ingest.vendor.example:4317" {
+		t.Errorf("Unexpected vendor exporter: %+v", vendor)
+	}
+	if vendor.Headers["x-api-key"] != "secret" || vendor.Headers["x-team"] != "obs team" {
+		t.Errorf("Unexpected vendor headers: %v", vendor.Headers)
+	}
+	if vendor.BatchTimeout != time.Second {
+		t.Errorf("Expected BatchTimeout 1s, got %v", vendor.BatchTimeout)
+	}
+
+	agent := exporters[1]
+	if agent.Protocol != DefaultOTLPExporterProtocol || !agent.Insecure || agent.Endpoint != "localhost:4318" {
+		t.Errorf("Unexpected local-agent exporter: %+v", agent)
+	}
+}
+
+func TestConfig_ValidateExporters(t *testing.T) {
+	tests := []struct {
+		name      string
+		exporters []ExporterConfig
+		wantField string
+	}{
+		{
+			name:      "valid",
+			exporters: []ExporterConfig{{Name: "vendor", Protocol: "grpc", Endpoint: "vendor:4317"}},
+		},
+		{
+			name:      "missing name",
+			exporters: []ExporterConfig{{Protocol: "grpc", Endpoint: "vendor:4317"}},
+			wantField: "Exporters[0].Name",
+		},
+		{
+			name: "duplicate name",
+			exporters: []ExporterConfig{
+				{Name: "vendor", Protocol: "grpc", Endpoint: "vendor:4317"},
+				{Name: "vendor", Protocol: "http", Endpoint: "vendor:4318"},
+			},
+			wantField: "Exporters[vendor].Name",
+		},
+		{
+			name:      "invalid protocol",
+			exporters: []ExporterConfig{{Name: "vendor", Protocol: "carrier-pigeon", Endpoint: "vendor:4317"}},
+			wantField: "Exporters[vendor].Protocol",
+		},
+		{
+			name:      "missing endpoint",
+			exporters: []ExporterConfig{{Name: "vendor", Protocol: "grpc"}},
+			wantField: "Exporters[vendor].Endpoint",
+		},
+		{
+			name:      "console needs no endpoint",
+			exporters: []ExporterConfig{{Name: "debug", Protocol: "console"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := NewConfig("test-service", "1.0.0")
+			cfg.Exporters = tt.exporters
+
+			err := cfg.Validate()
+			if tt.wantField == "" {
+				if err != nil {
+					t.Errorf("Validate() unexpected error: %v", err)
+				}
+				return
+			}
+			configErr, ok := err.(*ConfigError)
+			if !ok {
+				t.Fatalf("Expected ConfigError, got %T (%v)", err, err)
+			}
+			if configErr.Field != tt.wantField {
+				t.Errorf("Expected field %s, got %s", tt.wantField, configErr.Field)
+			}
+		})
+	}
+}
+
+func TestConfig_ForExporter(t *testing.T) {
+	cfg := NewConfig("test-service", "1.0.0")
+	cfg.Exporters = []ExporterConfig{{Name: "archive", Protocol: ProtocolFile, Endpoint: "/tmp/archive.jsonl"}}
+
+	derived := cfg.ForExporter(cfg.Exporters[0])
+	if derived == cfg {
+		t.Fatal("ForExporter should return a copy")
+	}
+	if derived.OTLPExporterProtocol != ProtocolFile || derived.FileExporterPath != "/tmp/archive.jsonl" {
+		t.Errorf("Unexpected derived config: protocol=%s path=%s", derived.OTLPExporterProtocol, derived.FileExporterPath)
+	}
+	if derived.Exporters != nil {
+		t.Error("Derived config should not carry additional exporters")
+	}
+	if cfg.OTLPExporterProtocol != DefaultOTLPExporterProtocol {
+		t.Error("ForExporter must not modify the original config")
+	}
+}
+
+func TestHeaders_Redacted(t *testing.T) {
+	cfg := NewConfig("test-service", "1.0.0")
+	cfg.OTLPExporterHeaders = Headers{"x-api-key": "super-secret", "authorization": "Bearer abc"}
+	cfg.Exporters = []ExporterConfig{{Name: "vendor", Protocol: "grpc", Endpoint: "vendor:4317", Headers: Headers{"x-api-key": "vendor-secret"}}}
+
+	for _, format := range []string{"%v", "%+v", "%#v", "%s"} {
+		out := fmt.Sprintf(format, cfg)
+		for _, secret := range []string{"super-secret", "Bearer abc", "vendor-secret"} {
+			if strings.Contains(out, secret) {
+				t.Errorf("Format %s leaked header value %q: %s", format, secret, out)
+			}
+		}
+		if !strings.Contains(out, "x-api-key") {
+			t.Errorf("Format %s should keep header names: %s", format, out)
+		}
+	}
+}
+
+func TestHeaders_ValidateDoesNotLeakValue(t *testing.T) {
+	cfg := NewConfig("test-service", "1.0.0")
+	cfg.OTLPExporterHeaders = Headers{"x-api-key": "secret\r\ninjected"}
+
+	err := cfg.Validate()
+	if err == nil {
+		t.Fatal("Expected error for header value containing CRLF")
+	}
+	if strings.Contains(err.Error(), "secret") {
+		t.Errorf("Error leaked header value: %v", err)
+	}
+	if !strings.Contains(err.Error(), "x-api-key") {
+		t.Errorf("Error should name the header: %v", err)
+	}
+}
+
+func TestNewConfigFromEnv_HeadersCompressionTimeout(t *testing.T) {
+	t.Setenv(EnvOTLPExporterHeaders, "x-api-key=secret,x-team=a%3Db")
+	t.Setenv(EnvOTLPCompression, "GZIP")
+	t.Setenv(EnvOTLPTimeout, "2500")
+
+	cfg, _ := NewConfigFromEnv()
+	if cfg.OTLPExporterHeaders["x-api-key"] != "secret" || cfg.OTLPExporterHeaders["x-team"] != "a=b" {
+		t.Errorf("Unexpected headers: %v", map[string]string(cfg.OTLPExporterHeaders))
+	}
+	if cfg.OTLPCompression != CompressionGzip {
+		t.Errorf("Expected compression gzip, got %q", cfg.OTLPCompression)
+	}
+	if cfg.OTLPTimeout != 2500*time.Millisecond {
+		t.Errorf("Expected timeout 2.5s, got %v", cfg.OTLPTimeout)
+	}
+
+	t.Setenv(EnvOTLPTimeout, "3s")
+	if cfg, _ = NewConfigFromEnv(); cfg.OTLPTimeout != 3*time.Second {
+		t.Errorf("Expected duration syntax to be accepted, got %v", cfg.OTLPTimeout)
+	}
+}
+
+func TestConfig_ValidateCompression(t *testing.T) {
+	cfg := NewConfig("test-service", "1.0.0")
+	cfg.OTLPCompression = "brotli"
+
+	err := cfg.Validate()
+	if configErr, ok := err.(*ConfigError); !ok || configErr.Field != "OTLPCompression" {
+		t.Errorf("Expected OTLPCompression ConfigError, got %v", err)
+	}
+}
+
+func TestForExporter_PersistentQueueSubdirectory(t *testing.T) {
+	cfg := NewConfig("test-service", "1.0.0")
+	cfg.PersistentQueueDir = "/var/lib/otelkit/queue"
+
+	derived := cfg.ForExporter(ExporterConfig{Name: "vendor", Protocol: ProtocolGRPC, Endpoint: "vendor:4317"})
+	if derived.PersistentQueueDir != filepath.Join("/var/lib/otelkit/queue", "vendor") {
+		t.Errorf("Expected per-exporter queue directory, got %s", derived.PersistentQueueDir)
+	}
+}
@@ -0,0 +1,135 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SamplingRule assigns a sampling ratio to the spans it matches. Rules are evaluated
+// in order and the first matching rule decides; spans matching no rule are sampled at
+// Config.SamplingRatio. Empty criteria match everything.
+//
+// Patterns are globs where '*' matches any sequence of characters (including '/')
+// and '?' a single character, or regular expressions when prefixed with "re:".
+type SamplingRule struct {
+	// SpanName is a pattern matched against the span name, e.g. "GET /checkout*".
+	SpanName string `json:"span_name,omitempty" yaml:"span_name,omitempty"`
+
+	// SpanKind restricts the rule to one kind: server, client, producer, consumer or internal.
+	SpanKind string `json:"span_kind,omitempty" yaml:"span_kind,omitempty"`
+
+	// Attributes maps attribute keys to patterns matched against the attribute
+	// values given when the span starts, e.g. {"http.url": "*/healthz"}.
+	Attributes map[string]string `json:"attributes,omitempty" yaml:"attributes,omitempty"`
+
+	// Ratio is the share of matching traces to sample (0.0 - 1.0).
+	Ratio float64 `json:"ratio" yaml:"ratio"`
+}
+
+// samplingRulesFile is the layout of a rules file.
+type samplingRulesFile struct {
+	Rules []SamplingRule `json:"rules" yaml:"rules"`
+}
+
+// ValidSpanKinds lists the span kinds accepted by SamplingRule.SpanKind.
+var ValidSpanKinds = []string{"server", "client", "producer", "consumer", "internal"}
+
+// LoadSamplingRules reads sampling rules from a JSON or YAML file (by extension:
+// .json, or .yaml/.yml) with a top-level "rules" list:
+//
+//	rules:
+//	  - span_name: "GET /checkout*"
+//	    ratio: 1
+//	  - attributes: {http.url: "*/healthz"}
+//	    ratio: 0
+func LoadSamplingRules(path string) ([]SamplingRule, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var file samplingRulesFile
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		err = json.Unmarshal(data, &file)
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &file)
+	default:
+		return nil, &ConfigError{Field: "SamplingRulesFile", Message: ErrSamplingRulesFormat}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parse sampling rules %s: %w", path, err)
+	}
+	return file.Rules, nil
+}
+
+// SamplingRuleSet returns the inline rules followed by the rules loaded from
+// SamplingRulesFile, and validates them.
+func (c *Config) SamplingRuleSet() ([]SamplingRule, error) {
+	rules := append([]SamplingRule(nil), c.SamplingRules...)
+	if c.SamplingRulesFile != "" {
+		fileRules, err := LoadSamplingRules(c.SamplingRulesFile)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, fileRules...)
+	}
+	for i, rule := range rules {
+		if err := rule.validate(i); err != nil {
+			return nil, err
+		}
+	}
+	return rules, nil
+}
+
+func (r SamplingRule) validate(index int) error {
+	field := fmt.Sprintf("SamplingRules[%d]", index)
+	if r.Ratio < 0 || r.Ratio > 1 {
+		return &ConfigError{Field: field + ".Ratio", Message: ErrInvalidSamplingRatio}
+	}
+	if r.SpanKind != "" && !contains(ValidSpanKinds, strings.ToLower(r.SpanKind)) {
+		return &ConfigError{Field: field + ".SpanKind", Message: ErrInvalidSpanKind}
+	}
+	if _, err := CompileSamplingPattern(r.SpanName); err != nil {
+		return &ConfigError{Field: field + ".SpanName", Message: ErrInvalidSamplingPattern}
+	}
+	for key, pattern := range r.Attributes {
+		if _, err := CompileSamplingPattern(pattern); err != nil {
+			return &ConfigError{Field: field + ".Attributes[" + key + "]", Message: ErrInvalidSamplingPattern}
+		}
+	}
+	return nil
+}
+
+// CompileSamplingPattern compiles a sampling rule pattern: a regular expression when
+// prefixed with "re:", otherwise a glob anchored at both ends. An empty pattern
+// compiles to nil, which matches everything.
+func CompileSamplingPattern(pattern string) (*regexp.Regexp, error) {
+	if pattern == "" {
+		return nil, nil
+	}
+	if expr, ok := strings.CutPrefix(pattern, "re:"); ok {
+		return regexp.Compile(expr)
+	}
+
+	var b strings.Builder
+	b.WriteString("^")
+	for _, r := range pattern {
+		switch r {
+		case '*':
+			b.WriteString(".*")
+		case '?':
+			b.WriteString(".")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	b.WriteString("$")
+	return regexp.Compile(b.String())
+}
@@ -0,0 +1,136 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeRulesFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	return path
+}
+
+func TestLoadSamplingRules(t *testing.T) {
+	want := []SamplingRule{
+		{SpanName: "* /checkout*", Ratio: 1},
+		{SpanKind: "server", Attributes: map[string]string{"http.url": "*/healthz"}, Ratio: 0},
+	}
+	files := map[string]string{
+		"rules.yaml": `
+rules:
+  - span_name: "* /checkout*"
+    ratio: 1
+  - span_kind: server
+    attributes:
+      http.url: "*/healthz"
+    ratio: 0
+`,
+		"rules.json": `{"rules": [
+  {"span_name": "* /checkout*", "ratio": 1},
+  {"span_kind": "server", "attributes": {"http.url": "*/healthz"}, "ratio": 0}
+]}`,
+	}
+
+	for name, content := range files {
+		t.Run(name, func(t *testing.T) {
+			rules, err := LoadSamplingRules(writeRulesFile(t, name, content))
+			if err != nil {
+				t.Fatalf("LoadSamplingRules failed: %v", err)
+			}
+			if len(rules) != len(want) {
+				t.Fatalf("Expected %d rules, got %d", len(want), len(rules))
+			}
+			for i := range want {
+				if rules[i].SpanName != want[i].SpanName || rules[i].SpanKind != want[i].SpanKind ||
+					rules[i].Ratio != want[i].Ratio || rules[i].Attributes["http.url"] != want[i].Attributes["http.url"] {
+					t.Errorf("Rule %d = %+v, want %+v", i, rules[i], want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestLoadSamplingRules_UnknownExtension(t *testing.T) {
+	_, err := LoadSamplingRules(writeRulesFile(t, "rules.toml", "rules = []"))
+	if configErr, ok := err.(*ConfigError); !ok || configErr.Field != "SamplingRulesFile" {
+		t.Errorf("Expected SamplingRulesFile ConfigError, got %v", err)
+	}
+}
+
+func TestCompileSamplingPattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		input   string
+		want    bool
+	}{
+		{"GET /checkout*", "GET /checkout/confirm", true},
+		{"GET /checkout*", "POST /checkout", false},
+		{"*/healthz", "http://localhost:8080/healthz", true},
+		{"*/healthz", "http://localhost:8080/healthz?full=1", false},
+		{"/users/?", "/users/1", true},
+		{"/users/?", "/users/12", false},
+		{"a.b", "axb", false},
+		{"re:^/api/v[0-9]+/", "/api/v2/orders", true},
+		{"re:^/api/v[0-9]+/", "/web/api/v2/", false},
+	}
+	for _, tt := range tests {
+		re, err := CompileSamplingPattern(tt.pattern)
+		if err != nil {
+			t.Fatalf("CompileSamplingPattern(%q) failed: %v", tt.pattern, err)
+		}
+		if got := re.MatchString(tt.input); got != tt.want {
+			t.Errorf("Pattern %q on %q = %v, want %v", tt.pattern, tt.input, got, tt.want)
+		}
+	}
+
+	if re, err := CompileSamplingPattern(""); re != nil || err != nil {
+		t.Errorf("Expected empty pattern to compile to nil, got %v, %v", re, err)
+	}
+	if _, err := CompileSamplingPattern("re:("); err == nil {
+		t.Error("Expected invalid regular expression to fail")
+	}
+}
+
+func TestConfig_ValidateSamplingRules(t *testing.T) {
+	tests := []struct {
+		name  string
+		rule  SamplingRule
+		field string
+	}{
+		{"ratio", SamplingRule{Ratio: 2}, "SamplingRules[1].Ratio"},
+		{"kind", SamplingRule{SpanKind: "backend", Ratio: 1}, "SamplingRules[1].SpanKind"},
+		{"span name", SamplingRule{SpanName: "re:[", Ratio: 1}, "SamplingRules[1].SpanName"},
+		{"attribute", SamplingRule{Attributes: map[string]string{"http.url": "re:("}, Ratio: 1}, "SamplingRules[1].Attributes[http.url]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := NewConfig("test-service", "1.0.0").WithSampling(SamplingRuleBased, 0.05)
+			cfg.SamplingRules = []SamplingRule{{SpanName: "*", Ratio: 1}, tt.rule}
+
+			err := cfg.Validate()
+			if configErr, ok := err.(*ConfigError); !ok || configErr.Field != tt.field {
+				t.Errorf("Expected %s ConfigError, got %v", tt.field, err)
+			}
+		})
+	}
+}
+
+func TestNewConfigFromEnv_SamplingRulesFile(t *testing.T) {
+	path := writeRulesFile(t, "rules.yml", "rules:\n  - span_name: \"*\"\n    ratio: 1\n")
+	t.Setenv(EnvSamplingType, "rule_based")
+	t.Setenv(EnvSamplingRulesFile, path)
+
+	cfg, _ := NewConfigFromEnv()
+	if cfg.SamplingType != SamplingRuleBased || cfg.SamplingRulesFile != path {
+		t.Errorf("Unexpected sampling config: %s %s", cfg.SamplingType, cfg.SamplingRulesFile)
+	}
+	rules, err := cfg.SamplingRuleSet()
+	if err != nil || len(rules) != 1 {
+		t.Errorf("Expected 1 rule from file, got %v, %v", rules, err)
+	}
+}
@@ -21,17 +21,19 @@
 //
 // # Advanced Configuration
 //
-// For custom configuration, use NewProviderConfig with NewProvider:
+// For custom configuration, use NewProviderConfig with Setup:
 //
 //	config := otelkit.NewProviderConfig("my-service", "v1.0.0").
 //	    WithOTLPExporter("https://api.honeycomb.io", "http", false).
 //	    WithSampling("probabilistic", 0.05)
-//	provider, err := otelkit.NewProvider(ctx, config)
+//	provider, err := otelkit.Setup(ctx, config)
 //	if err != nil {
 //	    log.Fatal(err)
 //	}
 //	defer provider.Shutdown(ctx)
 //
+//	tracer := provider.Tracer("my-service")
+//
 // # HTTP Middleware
 //
 // For HTTP request tracing:
@@ -42,7 +44,7 @@
 //
 // The package handles:
 // - OTLP exporter configuration (HTTP/gRPC)
-// - Sampling strategies (probabilistic, always_on, always_off)
+// - Sampling strategies (probabilistic, always_on, always_off, rate_limited, rule_based)
 // - Resource management with service metadata
 // - Context propagation for distributed tracing
 // - HTTP and gRPC instrumentation
@@ -54,6 +56,7 @@ package otelkit
 
 import (
 	"context"
+	"log/slog"
 	"net/http"
 	"time"
 
@@ -95,8 +98,8 @@ func NewProviderConfig(serviceName, serviceVersion string) *provider.ProviderCon
 }
 
 // NewProvider creates and configures a new TracerProvider using the provided configuration,
-// then sets it as the global OpenTelemetry provider (only once per application lifecycle).
-// This is the recommended way to initialize tracing when you need custom configuration.
+// then sets it as the global OpenTelemetry provider, replacing any previous one. Use Setup
+// for a Provider handle with Tracer, ForceFlush and Shutdown methods.
 //
 // Example:
 //
@@ -127,8 +130,49 @@ func NewDefaultProvider(ctx context.Context, serviceName string, serviceVersion
 	return provider.NewDefaultProvider(ctx, serviceName, serviceVersion...)
 }
 
+// Setup creates a Provider from cfg and installs it as the global tracer provider,
+// replacing any previous one, unless cfg.WithoutGlobalRegistration was used.
+//
+// Example:
+//
+//	provider, err := otelkit.Setup(ctx, otelkit.NewProviderConfig("payment-service", "v1.2.3"))
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	defer provider.Shutdown(ctx)
+func Setup(ctx context.Context, cfg *provider.ProviderConfig) (*Provider, error) {
+	return provider.New(ctx, cfg)
+}
+
+// ResetGlobal replaces the global tracer, meter and logger providers with no-op
+// providers without shutting down the previous ones, e.g. in test cleanup.
+func ResetGlobal() {
+	provider.ResetGlobal()
+}
+
+// RuntimeMetrics records Go runtime and process metrics until stopped.
+type RuntimeMetrics = provider.RuntimeMetrics
+
+// StartRuntimeMetrics starts recording Go runtime and process metrics (goroutines, heap,
+// GC pauses, scheduling latency, CPU time and open file descriptors) every interval
+// with the global meter provider, e.g. the one set up by SetupTracing or SetupMetrics.
+// Use Provider.StartRuntimeMetrics to stop recording with the provider's Shutdown.
+//
+// Example:
+//
+//	rm, err := otelkit.StartRuntimeMetrics(15 * time.Second)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	defer rm.Stop(ctx)
+func StartRuntimeMetrics(interval time.Duration) (*RuntimeMetrics, error) {
+	return provider.StartRuntimeMetrics(nil, interval)
+}
+
 // SetupTracing initializes OpenTelemetry tracing with sensible defaults.
-// This is the simplest way to get started with tracing.
+// This is the simplest way to get started with tracing. If OTEL_METRICS_EXPORTER or
+// OTEL_LOGS_EXPORTER is "otlp", metrics or logs are set up as well, and the returned
+// shutdown function flushes and stops them together with tracing.
 //
 // Example:
 //
@@ -141,6 +185,21 @@ func SetupTracing(ctx context.Context, serviceName string, serviceVersion ...str
 	return tracer.SetupTracing(ctx, serviceName, serviceVersion...)
 }
 
+// SetupMetrics initializes an OTLP metrics pipeline from the same environment
+// variables as SetupTracing, without setting up tracing, and installs the global
+// meter provider.
+//
+// Example:
+//
+//	shutdown, err := otelkit.SetupMetrics(ctx, "my-service")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	defer shutdown(ctx)
+func SetupMetrics(ctx context.Context, serviceName string, serviceVersion ...string) (func(context.Context) error, error) {
+	return tracer.SetupMetrics(ctx, serviceName, serviceVersion...)
+}
+
 // SetupTracingWithDefaults initializes tracing with hardcoded defaults.
 // This is useful for quick setup without environment variables.
 //
@@ -252,6 +311,117 @@ type HTTPMiddleware = middleware.HTTPMiddleware
 // This provides a cleaner API surface for users of this package.
 type ProviderConfig = provider.ProviderConfig
 
+// Provider is a tracer provider created by otelkit together with its lifecycle.
+// See Setup.
+type Provider = provider.Provider
+
+// ExporterConfig describes an additional export destination for fan-out.
+type ExporterConfig = provider.ExporterConfig
+
+// SamplingRule assigns a sampling ratio to matching spans. See ProviderConfig.WithSamplingRules.
+type SamplingRule = provider.SamplingRule
+
+// DynamicSampler is a sampler adjustable at runtime. See ProviderConfig.WithDynamicSampling.
+type DynamicSampler = provider.DynamicSampler
+
+// SamplingState is the sampling type and ratio currently applied by a DynamicSampler.
+type SamplingState = provider.SamplingState
+
+// TailSamplingConfig configures tail-based sampling. See ProviderConfig.WithTailSampling.
+type TailSamplingConfig = provider.TailSamplingConfig
+
+// TailSamplingPolicy decides whether a buffered trace is kept by the tail sampler.
+type TailSamplingPolicy = provider.TailSamplingPolicy
+
+// KeepErrorTraces keeps traces in which any span has an error status.
+func KeepErrorTraces() TailSamplingPolicy {
+	return provider.KeepErrorTraces()
+}
+
+// KeepSlowTraces keeps traces whose root span took at least threshold.
+func KeepSlowTraces(threshold time.Duration) TailSamplingPolicy {
+	return provider.KeepSlowTraces(threshold)
+}
+
+// KeepTracesWithAttribute keeps traces in which any span has the given attribute value.
+func KeepTracesWithAttribute(kv attribute.KeyValue) TailSamplingPolicy {
+	return provider.KeepTracesWithAttribute(kv)
+}
+
+// ExportStats is a snapshot of the span counters of one export destination. See
+// Provider.Stats.
+type ExportStats = provider.ExportStats
+
+// HealthChecker reports the health of span export. See Provider.HealthChecker.
+type HealthChecker = provider.HealthChecker
+
+// HealthStatus is the health of span export and of each export destination.
+type HealthStatus = provider.HealthStatus
+
+// ExporterHealth is the health of one export destination.
+type ExporterHealth = provider.ExporterHealth
+
+// HealthState is the state of span export reported by a HealthChecker.
+type HealthState = provider.HealthState
+
+// Health states.
+const (
+	HealthHealthy  = provider.HealthHealthy
+	HealthDegraded = provider.HealthDegraded
+	HealthFailing  = provider.HealthFailing
+)
+
+// HealthHandler returns an http.Handler reporting the health of span export of the
+// global otelkit provider, responding 503 Service Unavailable when it is failing or
+// missing. Mount it on a readiness endpoint such as /healthz/tracing.
+func HealthHandler() http.Handler {
+	return provider.HealthHandler()
+}
+
+// RedactionRule selects sensitive span data to redact. See ProviderConfig.WithRedaction.
+type RedactionRule = provider.RedactionRule
+
+// RedactionAction is what a redaction rule does with the data it matches.
+type RedactionAction = provider.RedactionAction
+
+// Redaction actions.
+const (
+	RedactDrop = provider.RedactDrop
+	RedactMask = provider.RedactMask
+	RedactHash = provider.RedactHash
+)
+
+// RedactAttributes redacts the whole value of attributes with the given keys.
+func RedactAttributes(action RedactionAction, keys ...string) RedactionRule {
+	return provider.RedactAttributes(action, keys...)
+}
+
+// RedactEmails redacts email addresses in string values.
+func RedactEmails(action RedactionAction) RedactionRule {
+	return provider.RedactEmails(action)
+}
+
+// RedactCardNumbers redacts payment card numbers that pass the Luhn checksum.
+func RedactCardNumbers(action RedactionAction) RedactionRule {
+	return provider.RedactCardNumbers(action)
+}
+
+// RedactJWTs redacts JSON Web Tokens.
+func RedactJWTs(action RedactionAction) RedactionRule {
+	return provider.RedactJWTs(action)
+}
+
+// RedactQueryParams redacts the values of the named URL query parameters.
+func RedactQueryParams(action RedactionAction, names ...string) RedactionRule {
+	return provider.RedactQueryParams(action, names...)
+}
+
+// DefaultRedactionRules masks email addresses, card numbers, JWTs and common
+// credential query parameters.
+func DefaultRedactionRules() []RedactionRule {
+	return provider.DefaultRedactionRules()
+}
+
 // ConfigError represents a configuration validation error.
 type ConfigError = config.ConfigError
 
@@ -313,6 +483,29 @@ func WithErrorAttributes(attrs ...attribute.KeyValue) ErrorOption {
 	return tracer.WithErrorAttributes(attrs...)
 }
 
+// SlogHandler is a slog.Handler adding the trace_id, span_id and trace_flags of the
+// active span to log records.
+type SlogHandler = tracer.SlogHandler
+
+// SlogOption configures a SlogHandler.
+type SlogOption = tracer.SlogOption
+
+// NewSlogHandler wraps next so that records logged with a context carrying a span,
+// e.g. with slog.InfoContext, include its trace and span IDs.
+//
+// Example:
+//
+//	slog.SetDefault(slog.New(otelkit.NewSlogHandler(slog.NewJSONHandler(os.Stdout, nil),
+//	    otelkit.WithSpanEvents(slog.LevelWarn))))
+func NewSlogHandler(next slog.Handler, opts ...SlogOption) *SlogHandler {
+	return tracer.NewSlogHandler(next, opts...)
+}
+
+// WithSpanEvents mirrors records at or above level as events on the active span.
+func WithSpanEvents(level slog.Level) SlogOption {
+	return tracer.WithSpanEvents(level)
+}
+
 // RecordErrorEnhanced records an error on the span with enhanced classification and options.
 // This function provides smart error recording with automatic classification, optional stack traces,
 // and flexible configuration through options.
@@ -2,6 +2,7 @@ package otelkit
 
 import (
 	"context"
+	"io"
 	"testing"
 	"time"
 
@@ -39,6 +40,25 @@ func TestNewDefaultProvider(t *testing.T) {
 	}
 }
 
+func TestSetup(t *testing.T) {
+	ctx := context.Background()
+	t.Cleanup(ResetGlobal)
+
+	provider, err := Setup(ctx, NewProviderConfig("test-service", "v1.0.0").WithConsoleExporter(io.Discard, true))
+	if err != nil {
+		t.Fatalf("Setup() failed: %v", err)
+	}
+	if !provider.IsGlobal() {
+		t.Error("Setup() should install the provider as global")
+	}
+	if provider.Tracer("test") == nil {
+		t.Error("Provider.Tracer() returned nil")
+	}
+	if err := provider.Shutdown(ctx); err != nil {
+		t.Errorf("Provider.Shutdown() failed: %v", err)
+	}
+}
+
 func TestSetupTracing(t *testing.T) {
 	ctx := context.Background()
 	shutdown, err := SetupTracing(ctx, "test-service")
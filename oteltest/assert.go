@@ -0,0 +1,258 @@
+package oteltest
+
+import (
+	"reflect"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/kernelshard/otelkit/tracer"
+)
+
+// Attribute keys of the exception event recorded by span.RecordError.
+const (
+	exceptionEventName = "exception"
+	exceptionMessage   = attribute.Key("exception.message")
+)
+
+// SpanAssertion checks properties of a recorded span. Every method reports a
+// mismatch with t.Errorf and returns the assertion, so checks can be chained.
+type SpanAssertion struct {
+	t     testing.TB
+	rec   *Recorder
+	span  tracetest.SpanStub
+	found bool
+}
+
+// Stub returns the recorded span for checks not covered by the assertion methods.
+func (a *SpanAssertion) Stub() tracetest.SpanStub {
+	return a.span
+}
+
+// HasKind checks the span kind.
+func (a *SpanAssertion) HasKind(kind trace.SpanKind) *SpanAssertion {
+	a.t.Helper()
+	if a.found && a.span.SpanKind != kind {
+		a.errorf("expected kind %s, got %s", kind, a.span.SpanKind)
+	}
+	return a
+}
+
+// HasAttribute checks that the span has the attribute with the given value.
+func (a *SpanAssertion) HasAttribute(kv attribute.KeyValue) *SpanAssertion {
+	a.t.Helper()
+	if !a.found {
+		return a
+	}
+	got, ok := a.attribute(kv.Key)
+	if !ok {
+		a.errorf("expected attribute %s=%s, attribute not set", kv.Key, kv.Value.Emit())
+		return a
+	}
+	if !reflect.DeepEqual(got.AsInterface(), kv.Value.AsInterface()) {
+		a.errorf("expected attribute %s=%s, got %s", kv.Key, kv.Value.Emit(), got.Emit())
+	}
+	return a
+}
+
+// HasAttributes checks several attributes at once.
+func (a *SpanAssertion) HasAttributes(kvs ...attribute.KeyValue) *SpanAssertion {
+	a.t.Helper()
+	for _, kv := range kvs {
+		a.HasAttribute(kv)
+	}
+	return a
+}
+
+// HasAttributeKey checks that the attribute is set, regardless of its value.
+func (a *SpanAssertion) HasAttributeKey(key string) *SpanAssertion {
+	a.t.Helper()
+	if _, ok := a.attribute(attribute.Key(key)); a.found && !ok {
+		a.errorf("expected attribute %s to be set", key)
+	}
+	return a
+}
+
+// LacksAttribute checks that the attribute is not set.
+func (a *SpanAssertion) LacksAttribute(key string) *SpanAssertion {
+	a.t.Helper()
+	if v, ok := a.attribute(attribute.Key(key)); a.found && ok {
+		a.errorf("expected attribute %s not to be set, got %s", key, v.Emit())
+	}
+	return a
+}
+
+// HasStatus checks the status code.
+func (a *SpanAssertion) HasStatus(code codes.Code) *SpanAssertion {
+	a.t.Helper()
+	if a.found && a.span.Status.Code != code {
+		a.errorf("expected status %s, got %s", code, a.span.Status.Code)
+	}
+	return a
+}
+
+// HasStatusDescription checks the status description set together with codes.Error.
+func (a *SpanAssertion) HasStatusDescription(description string) *SpanAssertion {
+	a.t.Helper()
+	if a.found && a.span.Status.Description != description {
+		a.errorf("expected status description %q, got %q", description, a.span.Status.Description)
+	}
+	return a
+}
+
+// HasEvent checks that an event with the given name was added. If attributes are
+// given, at least one event with that name must carry all of them.
+func (a *SpanAssertion) HasEvent(name string, attrs ...attribute.KeyValue) *SpanAssertion {
+	a.t.Helper()
+	if !a.found {
+		return a
+	}
+	for _, ev := range a.span.Events {
+		if ev.Name == name && containsAll(ev.Attributes, attrs) {
+			return a
+		}
+	}
+	if len(attrs) > 0 {
+		a.errorf("expected event %q with attributes %v", name, attrs)
+	} else {
+		a.errorf("expected event %q", name)
+	}
+	return a
+}
+
+// HasError checks that an error was recorded with span.RecordError (or otelkit's
+// error helpers). If message is not empty, it must match the error message.
+func (a *SpanAssertion) HasError(message string) *SpanAssertion {
+	a.t.Helper()
+	if !a.found {
+		return a
+	}
+	for _, ev := range a.span.Events {
+		if ev.Name != exceptionEventName {
+			continue
+		}
+		if message == "" {
+			return a
+		}
+		for _, kv := range ev.Attributes {
+			if kv.Key == exceptionMessage && kv.Value.AsString() == message {
+				return a
+			}
+		}
+	}
+	if message == "" {
+		a.errorf("expected a recorded error")
+	} else {
+		a.errorf("expected a recorded error %q", message)
+	}
+	return a
+}
+
+// HasNoError checks that no error was recorded and the status is not codes.Error.
+func (a *SpanAssertion) HasNoError() *SpanAssertion {
+	a.t.Helper()
+	if !a.found {
+		return a
+	}
+	for _, ev := range a.span.Events {
+		if ev.Name == exceptionEventName {
+			a.errorf("expected no recorded error, got %v", ev.Attributes)
+			return a
+		}
+	}
+	if a.span.Status.Code == codes.Error {
+		a.errorf("expected no error status, got %q", a.span.Status.Description)
+	}
+	return a
+}
+
+// HasErrorType checks the classification set by tracer.RecordErrorEnhanced.
+func (a *SpanAssertion) HasErrorType(errorType tracer.ErrorType) *SpanAssertion {
+	a.t.Helper()
+	return a.HasAttribute(attribute.String("error.type", string(errorType)))
+}
+
+// IsRoot checks that the span has no parent.
+func (a *SpanAssertion) IsRoot() *SpanAssertion {
+	a.t.Helper()
+	if a.found && a.span.Parent.IsValid() {
+		a.errorf("expected a root span, parent is %s", a.span.Parent.SpanID())
+	}
+	return a
+}
+
+// ChildOf checks that the span's parent is the first recorded span named parent.
+func (a *SpanAssertion) ChildOf(parent string) *SpanAssertion {
+	a.t.Helper()
+	if !a.found {
+		return a
+	}
+	p := a.rec.Span(parent)
+	if !p.found {
+		return a
+	}
+	if a.span.Parent.SpanID() != p.span.SpanContext.SpanID() {
+		a.errorf("expected parent %q", parent)
+	}
+	if a.span.SpanContext.TraceID() != p.span.SpanContext.TraceID() {
+		a.errorf("expected the trace of parent %q", parent)
+	}
+	return a
+}
+
+// Children returns assertions for the recorded spans whose parent is this span.
+func (a *SpanAssertion) Children() []*SpanAssertion {
+	var out []*SpanAssertion
+	if !a.found {
+		return out
+	}
+	for _, s := range a.rec.Spans() {
+		if s.Parent.SpanID() == a.span.SpanContext.SpanID() && s.Parent.TraceID() == a.span.SpanContext.TraceID() {
+			out = append(out, &SpanAssertion{t: a.t, rec: a.rec, span: s, found: true})
+		}
+	}
+	return out
+}
+
+// HasChildCount checks the number of recorded child spans.
+func (a *SpanAssertion) HasChildCount(n int) *SpanAssertion {
+	a.t.Helper()
+	if got := len(a.Children()); a.found && got != n {
+		a.errorf("expected %d child spans, got %d", n, got)
+	}
+	return a
+}
+
+func (a *SpanAssertion) attribute(key attribute.Key) (attribute.Value, bool) {
+	for _, kv := range a.span.Attributes {
+		if kv.Key == key {
+			return kv.Value, true
+		}
+	}
+	return attribute.Value{}, false
+}
+
+func (a *SpanAssertion) errorf(format string, args ...any) {
+	a.t.Helper()
+	a.t.Errorf("oteltest: span %q: "+format, append([]any{a.span.Name}, args...)...)
+}
+
+// containsAll reports whether attrs contains every key/value pair in want.
+func containsAll(attrs, want []attribute.KeyValue) bool {
+	for _, w := range want {
+		found := false
+		for _, kv := range attrs {
+			if kv.Key == w.Key && reflect.DeepEqual(kv.Value.AsInterface(), w.Value.AsInterface()) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
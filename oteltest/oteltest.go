@@ -0,0 +1,149 @@
+// Package oteltest provides an in-memory tracing setup and fluent span assertions for
+// unit tests of code instrumented with otelkit or plain OpenTelemetry.
+//
+// Setup installs a synchronous in-memory tracer provider and the W3C trace context and
+// baggage propagators as the OpenTelemetry globals, and restores the previous globals
+// when the test finishes. Every ended span is immediately available for assertions:
+//
+//	func TestCreateUser(t *testing.T) {
+//	    rec := oteltest.Setup(t)
+//
+//	    svc.CreateUser(ctx, "alice")
+//
+//	    rec.Span("CreateUser").
+//	        HasKind(trace.SpanKindServer).
+//	        HasAttribute(attribute.String("user.name", "alice")).
+//	        HasStatus(codes.Ok)
+//	    rec.Span("db.insert").ChildOf("CreateUser")
+//	}
+//
+// Because the globals are process-wide, tests using Setup must not run in parallel
+// with other tests that record spans.
+package oteltest
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Recorder gives access to the spans recorded by the in-memory provider installed
+// by Setup.
+type Recorder struct {
+	t        testing.TB
+	exporter *tracetest.InMemoryExporter
+	provider *sdktrace.TracerProvider
+}
+
+// Setup installs an in-memory, always-sampling tracer provider and the trace context
+// and baggage propagators as the OpenTelemetry globals. The previous globals are
+// restored in t.Cleanup.
+func Setup(t testing.TB) *Recorder {
+	t.Helper()
+
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithSyncer(exporter),
+		sdktrace.WithSampler(sdktrace.AlwaysSample()),
+	)
+
+	prevProvider := otel.GetTracerProvider()
+	prevPropagator := otel.GetTextMapPropagator()
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	t.Cleanup(func() {
+		_ = provider.Shutdown(context.Background())
+		otel.SetTracerProvider(prevProvider)
+		otel.SetTextMapPropagator(prevPropagator)
+	})
+
+	return &Recorder{t: t, exporter: exporter, provider: provider}
+}
+
+// Provider returns the in-memory tracer provider, for code that takes a provider
+// instead of using the global one.
+func (r *Recorder) Provider() *sdktrace.TracerProvider {
+	return r.provider
+}
+
+// Tracer returns a tracer from the in-memory provider.
+func (r *Recorder) Tracer(name string) trace.Tracer {
+	return r.provider.Tracer(name)
+}
+
+// Spans returns all ended spans in the order they ended.
+func (r *Recorder) Spans() tracetest.SpanStubs {
+	return r.exporter.GetSpans()
+}
+
+// Reset discards all recorded spans.
+func (r *Recorder) Reset() {
+	r.exporter.Reset()
+}
+
+// Span returns an assertion for the first ended span with the given name and fails
+// the test immediately if there is none.
+func (r *Recorder) Span(name string) *SpanAssertion {
+	r.t.Helper()
+	for _, s := range r.Spans() {
+		if s.Name == name {
+			return &SpanAssertion{t: r.t, rec: r, span: s, found: true}
+		}
+	}
+	r.t.Fatalf("oteltest: no span named %q; recorded spans: %s", name, r.spanNames())
+	return &SpanAssertion{t: r.t, rec: r}
+}
+
+// SpansNamed returns assertions for every ended span with the given name.
+func (r *Recorder) SpansNamed(name string) []*SpanAssertion {
+	var out []*SpanAssertion
+	for _, s := range r.Spans() {
+		if s.Name == name {
+			out = append(out, &SpanAssertion{t: r.t, rec: r, span: s, found: true})
+		}
+	}
+	return out
+}
+
+// HasSpanCount fails the test if the number of ended spans differs from n.
+func (r *Recorder) HasSpanCount(n int) *Recorder {
+	r.t.Helper()
+	if got := len(r.Spans()); got != n {
+		r.t.Errorf("oteltest: expected %d spans, got %d: %s", n, got, r.spanNames())
+	}
+	return r
+}
+
+// HasNoSpan fails the test if a span with the given name has ended.
+func (r *Recorder) HasNoSpan(name string) *Recorder {
+	r.t.Helper()
+	if len(r.SpansNamed(name)) > 0 {
+		r.t.Errorf("oteltest: expected no span named %q", name)
+	}
+	return r
+}
+
+// spanNames lists the recorded span names for failure messages.
+func (r *Recorder) spanNames() string {
+	spans := r.Spans()
+	if len(spans) == 0 {
+		return "none"
+	}
+	names := make([]string, 0, len(spans))
+	for _, s := range spans {
+		names = append(names, s.Name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
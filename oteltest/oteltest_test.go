@@ -0,0 +1,179 @@
+package oteltest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/kernelshard/otelkit/tracer"
+)
+
+// recordingT captures assertion failures so that failing checks can be tested.
+type recordingT struct {
+	testing.TB
+	errors []string
+	fatal  bool
+}
+
+func (r *recordingT) Helper() {}
+
+func (r *recordingT) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func (r *recordingT) Fatalf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+	r.fatal = true
+	runtime.Goexit()
+}
+
+// failures runs fn with rec reporting to a recordingT and returns the failures.
+func failures(rec *Recorder, fn func(*Recorder)) *recordingT {
+	rt := &recordingT{TB: rec.t}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		fn(&Recorder{t: rt, exporter: rec.exporter, provider: rec.provider})
+	}()
+	<-done
+	return rt
+}
+
+func TestSetup_RestoresGlobals(t *testing.T) {
+	prevProvider := otel.GetTracerProvider()
+	prevPropagator := otel.GetTextMapPropagator()
+
+	t.Run("inner", func(t *testing.T) {
+		rec := Setup(t)
+		if otel.GetTracerProvider() != rec.Provider() {
+			t.Error("Expected the in-memory provider to be installed globally")
+		}
+		fields := otel.GetTextMapPropagator().Fields()
+		if len(fields) < 2 {
+			t.Errorf("Expected trace context and baggage propagators, got fields %v", fields)
+		}
+	})
+
+	if otel.GetTracerProvider() != prevProvider {
+		t.Error("Expected the previous tracer provider to be restored")
+	}
+	if otel.GetTextMapPropagator() != prevPropagator {
+		t.Error("Expected the previous propagator to be restored")
+	}
+}
+
+func TestRecorder_SpanAssertions(t *testing.T) {
+	rec := Setup(t)
+	tr := tracer.New("oteltest")
+
+	ctx, parent := tr.Start(context.Background(), "parent", trace.WithSpanKind(trace.SpanKindServer))
+	parent.SetAttributes(attribute.String("user.name", "alice"), attribute.Int("items", 3))
+	parent.SetStatus(codes.Ok, "")
+
+	_, child := tr.Start(ctx, "child")
+	child.AddEvent("cache.miss", trace.WithAttributes(attribute.String("key", "user:1")))
+	tracer.RecordErrorEnhanced(child, errors.New("connection refused"), tracer.WithErrorType(tracer.ErrorTypeDatabase), tracer.WithStackTrace(true))
+	child.End()
+	parent.End()
+
+	rec.HasSpanCount(2).HasNoSpan("missing")
+	rec.Span("parent").
+		IsRoot().
+		HasKind(trace.SpanKindServer).
+		HasAttributes(attribute.String("user.name", "alice"), attribute.Int("items", 3)).
+		LacksAttribute("error.type").
+		HasStatus(codes.Ok).
+		HasNoError().
+		HasChildCount(1)
+	rec.Span("child").
+		ChildOf("parent").
+		HasKind(trace.SpanKindInternal).
+		HasEvent("cache.miss", attribute.String("key", "user:1")).
+		HasError("").
+		HasError("connection refused").
+		HasErrorType(tracer.ErrorTypeDatabase).
+		HasAttributeKey("error.stack_trace").
+		HasStatus(codes.Error).
+		HasStatusDescription("connection refused")
+
+	rec.Reset()
+	rec.HasSpanCount(0)
+}
+
+func TestRecorder_ReportsMismatches(t *testing.T) {
+	rec := Setup(t)
+	tr := tracer.New("oteltest")
+
+	ctx, parent := tr.Start(context.Background(), "parent")
+	parent.SetAttributes(attribute.Int("items", 3))
+	_, child := tr.Start(ctx, "child")
+	child.End()
+	parent.End()
+	_, other := tr.Start(context.Background(), "other")
+	other.End()
+
+	rt := failures(rec, func(r *Recorder) {
+		r.HasSpanCount(1)
+		r.Span("parent").
+			HasKind(trace.SpanKindClient).
+			HasAttribute(attribute.Int("items", 4)).
+			HasAttribute(attribute.String("missing", "x")).
+			HasStatus(codes.Error).
+			HasEvent("never").
+			HasError("").
+			HasChildCount(2).
+			ChildOf("other")
+		r.Span("child").IsRoot()
+	})
+	if rt.fatal {
+		t.Fatalf("Unexpected fatal failure: %v", rt.errors)
+	}
+	if len(rt.errors) != 11 {
+		t.Errorf("Expected 11 failures, got %d: %v", len(rt.errors), rt.errors)
+	}
+
+	rt = failures(rec, func(r *Recorder) {
+		r.Span("absent").HasStatus(codes.Ok)
+		t.Error("Expected Span to stop the test when no span matches")
+	})
+	if !rt.fatal || len(rt.errors) != 1 {
+		t.Errorf("Expected a single fatal failure, got %v", rt.errors)
+	}
+}
+
+func TestSetup_PropagatesAcrossHTTP(t *testing.T) {
+	rec := Setup(t)
+	tr := tracer.New("oteltest")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+		_, span := tr.Start(ctx, "server", trace.WithSpanKind(trace.SpanKindServer))
+		span.End()
+	}))
+	defer server.Close()
+
+	ctx, span := tr.Start(context.Background(), "client", trace.WithSpanKind(trace.SpanKindClient))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	if err != nil {
+		t.Fatalf("NewRequest failed: %v", err)
+	}
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	resp.Body.Close()
+	span.End()
+
+	rec.Span("server").ChildOf("client")
+}
@@ -0,0 +1,214 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// consoleExporter writes finished spans to a writer for local development.
+// In tree mode every trace in a batch is rendered as an indented span tree with
+// durations, status and attributes. In JSON mode each span is written as one
+// compact JSON object per line, which makes the output easy to pipe into jq.
+type consoleExporter struct {
+	mu        sync.Mutex
+	w         io.Writer
+	jsonLines bool
+	stopped   bool
+}
+
+// newConsoleExporter creates a console exporter writing to w.
+func newConsoleExporter(w io.Writer, jsonLines bool) *consoleExporter {
+	return &consoleExporter{w: w, jsonLines: jsonLines}
+}
+
+// ExportSpans writes the batch of spans to the underlying writer.
+func (e *consoleExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.stopped || len(spans) == 0 {
+		return nil
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if e.jsonLines {
+		return e.writeJSONLines(spans)
+	}
+	return e.writeTrees(spans)
+}
+
+// Shutdown stops the exporter. Subsequent exports are ignored.
+func (e *consoleExporter) Shutdown(ctx context.Context) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.stopped = true
+	return nil
+}
+
+// writeTrees groups spans by trace and renders each trace as a tree. Spans whose
+// parent is not part of the batch are treated as roots of their trace.
+func (e *consoleExporter) writeTrees(spans []sdktrace.ReadOnlySpan) error {
+	var traceOrder []trace.TraceID
+	byTrace := make(map[trace.TraceID][]sdktrace.ReadOnlySpan)
+	for _, s := range spans {
+		id := s.SpanContext().TraceID()
+		if _, ok := byTrace[id]; !ok {
+			traceOrder = append(traceOrder, id)
+		}
+		byTrace[id] = append(byTrace[id], s)
+	}
+
+	var b strings.Builder
+	for _, id := range traceOrder {
+		traceSpans := byTrace[id]
+		present := make(map[trace.SpanID]bool, len(traceSpans))
+		for _, s := range traceSpans {
+			present[s.SpanContext().SpanID()] = true
+		}
+
+		children := make(map[trace.SpanID][]sdktrace.ReadOnlySpan)
+		var roots []sdktrace.ReadOnlySpan
+		for _, s := range traceSpans {
+			parent := s.Parent().SpanID()
+			if s.Parent().IsValid() && present[parent] {
+				children[parent] = append(children[parent], s)
+			} else {
+				roots = append(roots, s)
+			}
+		}
+
+		fmt.Fprintf(&b, "trace %s\n", id)
+		sortByStart(roots)
+		for _, root := range roots {
+			writeSpanTree(&b, root, children, 1)
+		}
+	}
+
+	_, err := io.WriteString(e.w, b.String())
+	return err
+}
+
+// writeSpanTree renders a span and, recursively, its children at the given depth.
+func writeSpanTree(b *strings.Builder, s sdktrace.ReadOnlySpan, children map[trace.SpanID][]sdktrace.ReadOnlySpan, depth int) {
+	indent := strings.Repeat("  ", depth)
+
+	fmt.Fprintf(b, "%s%s [%s] %s kind=%s span_id=%s\n",
+		indent, s.Name(), s.EndTime().Sub(s.StartTime()), formatStatus(s.Status()), s.SpanKind(), s.SpanContext().SpanID())
+	for _, kv := range s.Attributes() {
+		fmt.Fprintf(b, "%s    %s=%s\n", indent, kv.Key, kv.Value.Emit())
+	}
+	for _, ev := range s.Events() {
+		fmt.Fprintf(b, "%s    event %s at +%s\n", indent, ev.Name, ev.Time.Sub(s.StartTime()))
+		for _, kv := range ev.Attributes {
+			fmt.Fprintf(b, "%s      %s=%s\n", indent, kv.Key, kv.Value.Emit())
+		}
+	}
+
+	kids := children[s.SpanContext().SpanID()]
+	sortByStart(kids)
+	for _, child := range kids {
+		writeSpanTree(b, child, children, depth+1)
+	}
+}
+
+// formatStatus renders a span status as "Ok", "Unset" or "Error(description)".
+func formatStatus(status sdktrace.Status) string {
+	if status.Code == codes.Error && status.Description != "" {
+		return fmt.Sprintf("%s(%s)", status.Code, status.Description)
+	}
+	return status.Code.String()
+}
+
+func sortByStart(spans []sdktrace.ReadOnlySpan) {
+	sort.SliceStable(spans, func(i, j int) bool {
+		return spans[i].StartTime().Before(spans[j].StartTime())
+	})
+}
+
+// consoleSpan is the JSON-lines representation of a finished span.
+type consoleSpan struct {
+	TraceID           string         `json:"trace_id"`
+	SpanID            string         `json:"span_id"`
+	ParentSpanID      string         `json:"parent_span_id,omitempty"`
+	Name              string         `json:"name"`
+	Kind              string         `json:"kind"`
+	Service           string         `json:"service,omitempty"`
+	StartTime         time.Time      `json:"start_time"`
+	EndTime           time.Time      `json:"end_time"`
+	DurationMS        float64        `json:"duration_ms"`
+	StatusCode        string         `json:"status_code"`
+	StatusDescription string         `json:"status_description,omitempty"`
+	Attributes        map[string]any `json:"attributes,omitempty"`
+	Events            []consoleEvent `json:"events,omitempty"`
+}
+
+// consoleEvent is the JSON-lines representation of a span event.
+type consoleEvent struct {
+	Name       string         `json:"name"`
+	Time       time.Time      `json:"time"`
+	Attributes map[string]any `json:"attributes,omitempty"`
+}
+
+// writeJSONLines writes one compact JSON object per span.
+func (e *consoleExporter) writeJSONLines(spans []sdktrace.ReadOnlySpan) error {
+	enc := json.NewEncoder(e.w)
+	for _, s := range spans {
+		out := consoleSpan{
+			TraceID:           s.SpanContext().TraceID().String(),
+			SpanID:            s.SpanContext().SpanID().String(),
+			Name:              s.Name(),
+			Kind:              s.SpanKind().String(),
+			StartTime:         s.StartTime(),
+			EndTime:           s.EndTime(),
+			DurationMS:        float64(s.EndTime().Sub(s.StartTime())) / float64(time.Millisecond),
+			StatusCode:        s.Status().Code.String(),
+			StatusDescription: s.Status().Description,
+			Attributes:        attributeMap(s.Attributes()),
+		}
+		if s.Parent().IsValid() {
+			out.ParentSpanID = s.Parent().SpanID().String()
+		}
+		if res := s.Resource(); res != nil {
+			if v, ok := res.Set().Value(semconv.ServiceNameKey); ok {
+				out.Service = v.AsString()
+			}
+		}
+		for _, ev := range s.Events() {
+			out.Events = append(out.Events, consoleEvent{
+				Name:       ev.Name,
+				Time:       ev.Time,
+				Attributes: attributeMap(ev.Attributes),
+			})
+		}
+		if err := enc.Encode(out); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// attributeMap converts attributes into a map of plain Go values for JSON encoding.
+func attributeMap(attrs []attribute.KeyValue) map[string]any {
+	if len(attrs) == 0 {
+		return nil
+	}
+	m := make(map[string]any, len(attrs))
+	for _, kv := range attrs {
+		m[string(kv.Key)] = kv.Value.AsInterface()
+	}
+	return m
+}
@@ -0,0 +1,152 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+
+	"github.com/kernelshard/otelkit/internal/config"
+)
+
+// recordConsoleSpans creates a parent span with one child, exports them as a single
+// batch through the console exporter and returns everything written to the buffer.
+func recordConsoleSpans(t *testing.T, jsonLines bool) string {
+	t.Helper()
+	ctx := context.Background()
+
+	var buf bytes.Buffer
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(newConsoleExporter(&buf, jsonLines)),
+	)
+	tr := tp.Tracer("console-test")
+
+	ctx, parent := tr.Start(ctx, "parent")
+	parent.SetAttributes(attribute.String("http.method", "GET"))
+	_, child := tr.Start(ctx, "child")
+	child.SetStatus(codes.Error, "boom")
+	child.End()
+	parent.End()
+
+	if err := tp.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown failed: %v", err)
+	}
+	return buf.String()
+}
+
+func TestConsoleExporter_Tree(t *testing.T) {
+	out := recordConsoleSpans(t, false)
+
+	if !strings.Contains(out, "trace ") {
+		t.Errorf("Expected trace header in output, got:\n%s", out)
+	}
+	if !strings.Contains(out, "  parent [") {
+		t.Errorf("Expected parent span at depth 1, got:\n%s", out)
+	}
+	if !strings.Contains(out, "    child [") {
+		t.Errorf("Expected child span nested under parent, got:\n%s", out)
+	}
+	if !strings.Contains(out, "Error(boom)") {
+		t.Errorf("Expected error status in output, got:\n%s", out)
+	}
+	if !strings.Contains(out, "http.method=GET") {
+		t.Errorf("Expected attributes in output, got:\n%s", out)
+	}
+}
+
+func TestConsoleExporter_JSONLines(t *testing.T) {
+	out := recordConsoleSpans(t, true)
+
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 JSON lines, got %d:\n%s", len(lines), out)
+	}
+
+	var first consoleSpan
+	if err := json.Unmarshal([]byte(lines[0]), &first); err != nil {
+		t.Fatalf("Failed to decode JSON line: %v", err)
+	}
+	if first.Name != "child" {
+		t.Errorf("Expected first span child, got %s", first.Name)
+	}
+	if first.ParentSpanID == "" {
+		t.Error("Expected child span to have a parent span ID")
+	}
+	if first.StatusCode != "Error" || first.StatusDescription != "boom" {
+		t.Errorf("Unexpected status %s/%s", first.StatusCode, first.StatusDescription)
+	}
+}
+
+func TestConsoleExporter_ShutdownStopsExport(t *testing.T) {
+	var buf bytes.Buffer
+	exp := newConsoleExporter(&buf, false)
+	if err := exp.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown failed: %v", err)
+	}
+
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exp))
+	_, span := tp.Tracer("test").Start(context.Background(), "ignored")
+	span.End()
+
+	if buf.Len() != 0 {
+		t.Errorf("Expected no output after shutdown, got %q", buf.String())
+	}
+}
+
+func TestCreateExporter_Console(t *testing.T) {
+	ctx := context.Background()
+
+	for _, protocol := range []string{config.ProtocolStdout, config.ProtocolConsole, config.ProtocolStdoutJSON} {
+		t.Run(protocol, func(t *testing.T) {
+			pc := NewProviderConfig("test-service", "1.0.0")
+			pc.Config.OTLPExporterProtocol = protocol
+			pc.Config.OTLPExporterEndpoint = ""
+
+			if err := pc.Config.Validate(); err != nil {
+				t.Fatalf("Validate failed for %s: %v", protocol, err)
+			}
+			exp, err := createExporter(ctx, pc, nil)
+			if err != nil {
+				t.Fatalf("createExporter failed: %v", err)
+			}
+			if _, ok := exp.(*consoleExporter); !ok {
+				t.Errorf("Expected *consoleExporter, got %T", exp)
+			}
+		})
+	}
+}
+
+func TestCreateExporter_None(t *testing.T) {
+	ctx := context.Background()
+	pc := NewProviderConfig("test-service", "1.0.0")
+	pc.Config.OTLPExporterProtocol = config.ProtocolNone
+	pc.Config.OTLPExporterEndpoint = ""
+
+	if err := pc.Config.Validate(); err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+	exp, err := createExporter(ctx, pc, nil)
+	if err != nil {
+		t.Fatalf("createExporter failed: %v", err)
+	}
+	if err := exp.ExportSpans(ctx, testSpans(t)); err != nil {
+		t.Errorf("Expected spans to be discarded, got %v", err)
+	}
+}
+
+func TestProviderConfig_WithConsoleExporter(t *testing.T) {
+	var buf bytes.Buffer
+	pc := NewProviderConfig("test", "1.0.0").WithConsoleExporter(&buf, true)
+
+	if pc.Config.OTLPExporterProtocol != config.ProtocolStdoutJSON {
+		t.Errorf("Expected protocol %s, got %s", config.ProtocolStdoutJSON, pc.Config.OTLPExporterProtocol)
+	}
+	if pc.ConsoleWriter != &buf {
+		t.Error("ConsoleWriter was not set correctly")
+	}
+}
@@ -0,0 +1,227 @@
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/kernelshard/otelkit/internal/config"
+)
+
+// SamplingState is the sampling configuration currently applied by a DynamicSampler.
+type SamplingState struct {
+	Type  config.SamplingType `json:"type"`
+	Ratio float64             `json:"ratio"`
+	Rate  float64             `json:"rate"` // Root traces per second for rate_limited
+}
+
+// DynamicSampler is a sampler whose type, ratio and rate can be changed at runtime without
+// recreating the tracer provider. Updates build a new sampler through the registered
+// SamplerFactory and swap it in atomically, so ShouldSample never blocks. Every
+// change is written to the audit logger.
+type DynamicSampler struct {
+	base   config.Config // Template for samplers; only the sampling fields change
+	logger *slog.Logger
+
+	mu      sync.Mutex // Serializes updates
+	current atomic.Pointer[dynamicSamplerState]
+}
+
+type dynamicSamplerState struct {
+	SamplingState
+	sampler sdktrace.Sampler
+}
+
+// NewDynamicSampler creates a dynamic sampler starting from the sampling settings in
+// cfg. Other settings used by samplers, such as the rules of rule-based sampling, are
+// taken from cfg as well. Changes are logged to logger, or slog.Default() if nil.
+func NewDynamicSampler(cfg *config.Config, logger *slog.Logger) (*DynamicSampler, error) {
+	if err := cfg.ValidateSampling(); err != nil {
+		return nil, err
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+	s := &DynamicSampler{base: *cfg, logger: logger}
+	s.current.Store(s.build(SamplingState{Type: cfg.SamplingType, Ratio: cfg.SamplingRatio, Rate: cfg.SamplingRateLimit}))
+	return s, nil
+}
+
+// ShouldSample delegates to the current sampler.
+func (s *DynamicSampler) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	return s.current.Load().sampler.ShouldSample(p)
+}
+
+// Description returns the description of the current sampler.
+func (s *DynamicSampler) Description() string {
+	return "DynamicSampler{" + s.current.Load().sampler.Description() + "}"
+}
+
+// State returns the sampling configuration currently applied.
+func (s *DynamicSampler) State() SamplingState {
+	return s.current.Load().SamplingState
+}
+
+// SetSamplingRatio changes the ratio and keeps the sampling type.
+func (s *DynamicSampler) SetSamplingRatio(ratio float64) error {
+	_, err := s.update(nil, "api", func(state *SamplingState) { state.Ratio = ratio })
+	return err
+}
+
+// SetSamplingRate changes the root traces per second of rate_limited sampling and
+// keeps the sampling type.
+func (s *DynamicSampler) SetSamplingRate(tracesPerSecond float64) error {
+	_, err := s.update(nil, "api", func(state *SamplingState) { state.Rate = tracesPerSecond })
+	return err
+}
+
+// SetSamplingType changes the sampling type and keeps the ratio and rate.
+func (s *DynamicSampler) SetSamplingType(samplingType config.SamplingType) error {
+	_, err := s.update(nil, "api", func(state *SamplingState) { state.Type = samplingType })
+	return err
+}
+
+// SetSampling changes the sampling type and ratio together, e.g. when switching
+// from always_on to probabilistic sampling.
+func (s *DynamicSampler) SetSampling(samplingType config.SamplingType, ratio float64) error {
+	_, err := s.update(nil, "api", func(state *SamplingState) {
+		state.Type = samplingType
+		state.Ratio = ratio
+	})
+	return err
+}
+
+// update applies change to the current state if the result is valid, swaps in the new
+// sampler and audits the change. span, if recording, receives the audit as an event.
+func (s *DynamicSampler) update(span trace.Span, source string, change func(*SamplingState)) (SamplingState, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	old := s.current.Load().SamplingState
+	next := old
+	change(&next)
+
+	candidate := s.base
+	candidate.SamplingType = next.Type
+	candidate.SamplingRatio = next.Ratio
+	candidate.SamplingRateLimit = next.Rate
+	if err := candidate.ValidateSampling(); err != nil {
+		s.logger.Warn("otelkit: sampling change rejected",
+			slog.String("source", source),
+			slog.String("sampling.type", string(next.Type)),
+			slog.Float64("sampling.ratio", next.Ratio),
+			slog.Float64("sampling.rate", next.Rate),
+			slog.String("error", err.Error()),
+		)
+		return old, err
+	}
+
+	replaced := s.current.Swap(s.build(next))
+	if stoppable, ok := replaced.sampler.(stoppableSampler); ok {
+		stoppable.stop()
+	}
+	s.logger.Info("otelkit: sampling changed",
+		slog.String("source", source),
+		slog.String("sampling.old_type", string(old.Type)),
+		slog.Float64("sampling.old_ratio", old.Ratio),
+		slog.Float64("sampling.old_rate", old.Rate),
+		slog.String("sampling.type", string(next.Type)),
+		slog.Float64("sampling.ratio", next.Ratio),
+		slog.Float64("sampling.rate", next.Rate),
+	)
+	if span != nil && span.IsRecording() {
+		span.AddEvent("sampling.changed", trace.WithAttributes(
+			attribute.String("sampling.old_type", string(old.Type)),
+			attribute.Float64("sampling.old_ratio", old.Ratio),
+			attribute.Float64("sampling.old_rate", old.Rate),
+			attribute.String("sampling.type", string(next.Type)),
+			attribute.Float64("sampling.ratio", next.Ratio),
+			attribute.Float64("sampling.rate", next.Rate),
+		))
+	}
+	return next, nil
+}
+
+// stop stops the background work of the current sampler, e.g. Jaeger remote polling.
+func (s *DynamicSampler) stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if stoppable, ok := s.current.Load().sampler.(stoppableSampler); ok {
+		stoppable.stop()
+	}
+}
+
+func (s *DynamicSampler) build(state SamplingState) *dynamicSamplerState {
+	cfg := s.base
+	cfg.SamplingType = state.Type
+	cfg.SamplingRatio = state.Ratio
+	cfg.SamplingRateLimit = state.Rate
+	return &dynamicSamplerState{SamplingState: state, sampler: createSampler(&cfg)}
+}
+
+// Handler returns an http.Handler to read and update the sampling configuration.
+// GET returns the current state as JSON; PUT or POST with a JSON body such as
+// {"type": "rate_limited", "rate": 50} applies the given fields and returns the new
+// state. Invalid changes are rejected with 400 Bad Request.
+//
+// The handler performs no authentication; mount it on an internal admin listener or
+// behind the application's own access control.
+func (s *DynamicSampler) Handler() http.Handler {
+	return http.HandlerFunc(s.serveHTTP)
+}
+
+// samplingUpdate is the request body of the handler; absent fields are unchanged.
+type samplingUpdate struct {
+	Type  *config.SamplingType `json:"type"`
+	Ratio *float64             `json:"ratio"`
+	Rate  *float64             `json:"rate"`
+}
+
+func (s *DynamicSampler) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+		writeJSON(w, http.StatusOK, s.State())
+	case http.MethodPut, http.MethodPost:
+		var req samplingUpdate
+		dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<16))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&req); err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": fmt.Sprintf("invalid request body: %v", err)})
+			return
+		}
+		state, err := s.update(trace.SpanFromContext(r.Context()), "http "+r.RemoteAddr, func(state *SamplingState) {
+			if req.Type != nil {
+				state.Type = config.SamplingType(strings.ToLower(strings.TrimSpace(string(*req.Type))))
+			}
+			if req.Ratio != nil {
+				state.Ratio = *req.Ratio
+			}
+			if req.Rate != nil {
+				state.Rate = *req.Rate
+			}
+		})
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+			return
+		}
+		writeJSON(w, http.StatusOK, state)
+	default:
+		w.Header().Set("Allow", "GET, HEAD, PUT, POST")
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+	}
+}
+
+// writeJSON writes v as the JSON body of a response with the given status code.
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,208 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/kernelshard/otelkit/internal/config"
+)
+
+func newTestDynamicSampler(t *testing.T, samplingType config.SamplingType, ratio float64) (*DynamicSampler, *bytes.Buffer) {
+	t.Helper()
+	cfg := config.NewConfig("dynamic-service", "1.0.0").WithSampling(samplingType, ratio)
+	var logs bytes.Buffer
+	s, err := NewDynamicSampler(cfg, slog.New(slog.NewJSONHandler(&logs, nil)))
+	if err != nil {
+		t.Fatalf("NewDynamicSampler failed: %v", err)
+	}
+	return s, &logs
+}
+
+func sampledRoot(tp *sdktrace.TracerProvider) bool {
+	_, span := tp.Tracer("dynamic-test").Start(context.Background(), "op")
+	defer span.End()
+	return span.SpanContext().IsSampled()
+}
+
+func TestDynamicSampler_Updates(t *testing.T) {
+	s, logs := newTestDynamicSampler(t, config.SamplingProbabilistic, 0)
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSampler(s))
+	defer tp.Shutdown(context.Background())
+
+	if sampledRoot(tp) {
+		t.Fatal("Expected ratio 0 to drop the root span")
+	}
+	if err := s.SetSamplingRatio(1); err != nil {
+		t.Fatalf("SetSamplingRatio failed: %v", err)
+	}
+	if !sampledRoot(tp) {
+		t.Error("Expected ratio 1 to sample the root span")
+	}
+	if err := s.SetSamplingType(config.SamplingAlwaysOff); err != nil {
+		t.Fatalf("SetSamplingType failed: %v", err)
+	}
+	if sampledRoot(tp) {
+		t.Error("Expected always_off to drop the root span")
+	}
+	if err := s.SetSamplingRate(50); err != nil {
+		t.Fatalf("SetSamplingRate failed: %v", err)
+	}
+	if err := s.SetSamplingType(config.SamplingRateLimited); err != nil {
+		t.Fatalf("SetSamplingType failed: %v", err)
+	}
+	if got := s.State(); got != (SamplingState{Type: config.SamplingRateLimited, Ratio: 1, Rate: 50}) {
+		t.Errorf("State = %+v", got)
+	}
+	if !strings.HasPrefix(s.Description(), "DynamicSampler{ParentBased{") {
+		t.Errorf("Unexpected description %q", s.Description())
+	}
+	if n := strings.Count(logs.String(), "otelkit: sampling changed"); n != 4 {
+		t.Errorf("Expected 4 audit log lines, got %d:\n%s", n, logs.String())
+	}
+}
+
+func TestDynamicSampler_RejectsInvalidChange(t *testing.T) {
+	s, logs := newTestDynamicSampler(t, config.SamplingRateLimited, 0.5)
+	before := s.State()
+
+	for name, err := range map[string]error{
+		"ratio": s.SetSamplingRatio(2),
+		"type":  s.SetSamplingType("sometimes"),
+		"rate":  s.SetSamplingRate(0),
+	} {
+		if _, ok := err.(*config.ConfigError); !ok {
+			t.Errorf("%s: expected ConfigError, got %v", name, err)
+		}
+	}
+	if s.State() != before {
+		t.Errorf("Expected state to be unchanged, got %+v", s.State())
+	}
+	if !strings.Contains(logs.String(), "otelkit: sampling change rejected") {
+		t.Errorf("Expected rejected change to be logged, got:\n%s", logs.String())
+	}
+}
+
+func TestNewDynamicSampler_InvalidConfig(t *testing.T) {
+	cfg := config.NewConfig("dynamic-service", "1.0.0").WithSampling(config.SamplingProbabilistic, -1)
+	if _, err := NewDynamicSampler(cfg, nil); err == nil {
+		t.Error("Expected invalid sampling config to fail")
+	}
+}
+
+func TestDynamicSampler_Handler(t *testing.T) {
+	s, _ := newTestDynamicSampler(t, config.SamplingProbabilistic, 0.1)
+	h := s.Handler()
+
+	serve := func(r *http.Request) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		return w
+	}
+
+	w := serve(httptest.NewRequest(http.MethodGet, "/admin/sampling", nil))
+	var state SamplingState
+	if err := json.NewDecoder(w.Body).Decode(&state); err != nil || w.Code != http.StatusOK {
+		t.Fatalf("GET returned %d: %v", w.Code, err)
+	}
+	if state != (SamplingState{Type: config.SamplingProbabilistic, Ratio: 0.1, Rate: config.DefaultSamplingRate}) {
+		t.Errorf("GET state = %+v", state)
+	}
+
+	// The change is recorded on the request's span.
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	ctx, span := tp.Tracer("admin").Start(context.Background(), "PUT /admin/sampling")
+	req := httptest.NewRequest(http.MethodPut, "/admin/sampling", strings.NewReader(`{"type": "Rate_Limited", "rate": 25}`))
+	w = serve(req.WithContext(ctx))
+	span.End()
+	if w.Code != http.StatusOK {
+		t.Fatalf("PUT returned %d: %s", w.Code, w.Body.String())
+	}
+	if got := s.State(); got != (SamplingState{Type: config.SamplingRateLimited, Ratio: 0.1, Rate: 25}) {
+		t.Errorf("State after PUT = %+v", got)
+	}
+	events := recorder.Ended()[0].Events()
+	if len(events) != 1 || events[0].Name != "sampling.changed" {
+		t.Errorf("Expected sampling.changed event, got %+v", events)
+	}
+
+	for _, body := range []string{`{"ratio": -1}`, `{"type": "sometimes"}`, `{"rate": 0}`, `{"rps": 1}`, `not json`} {
+		w = serve(httptest.NewRequest(http.MethodPost, "/admin/sampling", strings.NewReader(body)))
+		if w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), `"error"`) {
+			t.Errorf("POST %s returned %d: %s", body, w.Code, w.Body.String())
+		}
+	}
+	if got := s.State(); got.Rate != 25 {
+		t.Errorf("Expected rejected requests to leave the state unchanged, got %+v", got)
+	}
+
+	w = serve(httptest.NewRequest(http.MethodDelete, "/admin/sampling", nil))
+	if w.Code != http.StatusMethodNotAllowed || w.Header().Get("Allow") == "" {
+		t.Errorf("DELETE returned %d with Allow %q", w.Code, w.Header().Get("Allow"))
+	}
+}
+
+func TestDynamicSampler_ConcurrentUpdates(t *testing.T) {
+	s, _ := newTestDynamicSampler(t, config.SamplingProbabilistic, 0.5)
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSampler(s))
+	defer tp.Shutdown(context.Background())
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			_ = s.SetSamplingRatio(float64(i) / 10)
+		}(i)
+		go func() {
+			defer wg.Done()
+			sampledRoot(tp)
+		}()
+	}
+	wg.Wait()
+}
+
+func TestNew_DynamicSampling(t *testing.T) {
+	ctx := context.Background()
+	pc := NewProviderConfig("dynamic-service", "1.0.0").
+		WithConsoleExporter(io.Discard, true).
+		WithSampling(config.SamplingAlwaysOff, 0).
+		WithDynamicSampling(slog.New(slog.NewTextHandler(io.Discard, nil))).
+		WithoutGlobalRegistration()
+
+	p, err := New(ctx, pc)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	defer p.Shutdown(ctx)
+	other, err := New(ctx, pc)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	defer other.Shutdown(ctx)
+
+	if sampledRoot(p.TracerProvider()) {
+		t.Error("Expected always_off to drop the root span")
+	}
+	if err := p.DynamicSampler().SetSamplingType(config.SamplingAlwaysOn); err != nil {
+		t.Fatalf("SetSamplingType failed: %v", err)
+	}
+	if !sampledRoot(p.TracerProvider()) {
+		t.Error("Expected the provider to use the updated sampler")
+	}
+	// A second provider from the same config keeps its own sampler.
+	if sampledRoot(other.TracerProvider()) {
+		t.Error("Expected the change not to affect another provider")
+	}
+}
@@ -0,0 +1,264 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+const (
+	exportMeterName    = "github.com/kernelshard/otelkit/export"
+	exportMeterVersion = "1.0.0"
+
+	// primaryExporterName identifies the exporter described by Config in ExportStats.
+	primaryExporterName = "primary"
+)
+
+// ExportStats is a snapshot of the span counters of one export destination.
+type ExportStats struct {
+	Exporter      string    // "primary" or the name of an additional exporter
+	QueueDepth    int64     // Spans waiting in the batch queue or being exported
+	SpansQueued   uint64    // Spans accepted into the batch queue
+	SpansExported uint64    // Spans the exporter sent successfully, including replayed ones
+	SpansDropped  uint64    // Spans discarded because the queue was full
+	SpansFailed   uint64    // Spans in batches the exporter returned an error for or spilled to disk
+	FailedExports uint64    // Export calls that returned an error, not counting replays
+	LastError     error     // Error of the most recent failed export, nil if none
+	LastErrorTime time.Time // When the most recent export failed
+}
+
+// exportTracker counts the spans passing through the batch processor of one export
+// destination. It sits in front of the batch processor, where it counts queued
+// spans and drops spans itself once maxQueue spans are pending, and behind it, where
+// it counts the spans leaving the queue for the exporter. Pending spans never exceed
+// the batch processor's own queue, so that queue never overflows and every dropped
+// span is counted. Exported and failed spans are counted around the destination's
+// exporter, or by the persistent queue if there is one: a spilled batch counts as
+// failed once and a replayed one as exported, while failed replays only update the
+// last error and the failure streak.
+type exportTracker struct {
+	name     string
+	maxQueue int64
+
+	pending       atomic.Int64 // Spans in the batch queue
+	exporting     atomic.Int64 // Spans handed to the exporter
+	queued        atomic.Uint64
+	exported      atomic.Uint64
+	dropped       atomic.Uint64
+	failed        atomic.Uint64
+	failedExports atomic.Uint64
+	lastDrop      atomic.Int64 // Unix nanoseconds of the most recent drop
+
+	mu            sync.Mutex
+	lastError     error
+	lastErrorTime time.Time
+	lastSuccess   time.Time
+	failureStreak uint64    // Failed exports since the last success
+	failingSince  time.Time // First failure of the current streak
+}
+
+// createTrackedBatchProcessor creates the exporter and batch processor of an export
+// destination together with the tracker counting its spans.
+func createTrackedBatchProcessor(ctx context.Context, name string, cfg *ProviderConfig) (sdktrace.SpanProcessor, *exportTracker, error) {
+	t := &exportTracker{name: name}
+	exporter, err := createExporter(ctx, cfg, t)
+	if err != nil {
+		return nil, nil, err
+	}
+	bsp := createBatchProcessor(&dequeuingExporter{SpanExporter: exporter, tracker: t}, cfg)
+	t.maxQueue = int64(cfg.MaxQueueSize)
+	return &trackingProcessor{SpanProcessor: bsp, tracker: t}, t, nil
+}
+
+// stats returns a snapshot of the counters.
+func (t *exportTracker) stats() ExportStats {
+	t.mu.Lock()
+	lastError, lastErrorTime := t.lastError, t.lastErrorTime
+	t.mu.Unlock()
+	return ExportStats{
+		Exporter:      t.name,
+		QueueDepth:    t.pending.Load() + t.exporting.Load(),
+		SpansQueued:   t.queued.Load(),
+		SpansExported: t.exported.Load(),
+		SpansDropped:  t.dropped.Load(),
+		SpansFailed:   t.failed.Load(),
+		FailedExports: t.failedExports.Load(),
+		LastError:     lastError,
+		LastErrorTime: lastErrorTime,
+	}
+}
+
+// trackingProcessor counts the sampled spans handed to a batch processor and drops
+// them when the queue is full.
+type trackingProcessor struct {
+	sdktrace.SpanProcessor
+	tracker *exportTracker
+	stopped atomic.Bool
+}
+
+func (p *trackingProcessor) OnEnd(s sdktrace.ReadOnlySpan) {
+	if !s.SpanContext().IsSampled() || p.stopped.Load() {
+		return
+	}
+	t := p.tracker
+	if t.pending.Add(1) > t.maxQueue {
+		t.pending.Add(-1)
+		t.dropped.Add(1)
+		t.lastDrop.Store(time.Now().UnixNano())
+		return
+	}
+	t.queued.Add(1)
+	p.SpanProcessor.OnEnd(s)
+}
+
+// Shutdown stops the batch processor. Spans that reached it while it was stopping
+// are discarded by the batch processor, so once it has drained its queue, the spans
+// still pending are counted as dropped.
+func (p *trackingProcessor) Shutdown(ctx context.Context) error {
+	p.stopped.Store(true)
+	if err := p.SpanProcessor.Shutdown(ctx); err != nil {
+		return err
+	}
+	if lost := p.tracker.pending.Swap(0); lost > 0 {
+		p.tracker.dropped.Add(uint64(lost))
+		p.tracker.lastDrop.Store(time.Now().UnixNano())
+	}
+	return nil
+}
+
+// dequeuingExporter moves the spans a batch processor hands to its exporter from
+// pending to exporting, and counts them as no longer queued once the export returns,
+// whatever its outcome.
+type dequeuingExporter struct {
+	sdktrace.SpanExporter
+	tracker *exportTracker
+}
+
+func (e *dequeuingExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	n := int64(len(spans))
+	e.tracker.pending.Add(-n)
+	e.tracker.exporting.Add(n)
+	defer e.tracker.exporting.Add(-n)
+	return e.SpanExporter.ExportSpans(ctx, spans)
+}
+
+// trackingExporter counts the outcome of every export of a destination's exporter.
+type trackingExporter struct {
+	sdktrace.SpanExporter
+	tracker *exportTracker
+}
+
+func (e *trackingExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	err := e.SpanExporter.ExportSpans(ctx, spans)
+	if err != nil {
+		e.tracker.recordFailure(len(spans), err)
+		return err
+	}
+	e.tracker.recordSuccess(len(spans))
+	return nil
+}
+
+// recordSuccess counts n exported spans and ends the failure streak. A nil tracker
+// records nothing.
+func (t *exportTracker) recordSuccess(n int) {
+	if t == nil {
+		return
+	}
+	t.exported.Add(uint64(n))
+	t.mu.Lock()
+	t.lastSuccess = time.Now()
+	t.failureStreak = 0
+	t.failingSince = time.Time{}
+	t.mu.Unlock()
+}
+
+// recordFailure counts n failed spans and, if err is not nil, a failed export. A nil
+// tracker records nothing.
+func (t *exportTracker) recordFailure(n int, err error) {
+	if t == nil {
+		return
+	}
+	t.failed.Add(uint64(n))
+	if err != nil {
+		t.failedExports.Add(1)
+		t.recordError(err)
+	}
+}
+
+// recordError records err as the last error and extends the failure streak without
+// counting spans, for exports whose spans have been counted already. A nil tracker
+// records nothing.
+func (t *exportTracker) recordError(err error) {
+	if t == nil {
+		return
+	}
+	now := time.Now()
+	t.mu.Lock()
+	t.lastError, t.lastErrorTime = err, now
+	if t.failureStreak == 0 {
+		t.failingSince = now
+	}
+	t.failureStreak++
+	t.mu.Unlock()
+}
+
+// registerExportMetrics reports the counters of trackers as metrics of mp, with the
+// destination in the exporter attribute:
+//   - otelkit.exporter.queue.size: spans waiting in the queue or being exported
+//   - otelkit.exporter.spans.queued, .exported, .dropped and .failed: span counts
+//   - otelkit.exporter.export.failures: export calls that returned an error
+func registerExportMetrics(mp metric.MeterProvider, trackers []*exportTracker) error {
+	meter := mp.Meter(exportMeterName, metric.WithInstrumentationVersion(exportMeterVersion))
+	var err, e error
+	queueSize, e := meter.Int64ObservableGauge("otelkit.exporter.queue.size",
+		metric.WithDescription("Spans waiting in the batch queue or being exported."), metric.WithUnit("{span}"))
+	err = errors.Join(err, e)
+	queued, e := meter.Int64ObservableCounter("otelkit.exporter.spans.queued",
+		metric.WithDescription("Spans accepted into the batch queue."), metric.WithUnit("{span}"))
+	err = errors.Join(err, e)
+	exported, e := meter.Int64ObservableCounter("otelkit.exporter.spans.exported",
+		metric.WithDescription("Spans exported successfully."), metric.WithUnit("{span}"))
+	err = errors.Join(err, e)
+	dropped, e := meter.Int64ObservableCounter("otelkit.exporter.spans.dropped",
+		metric.WithDescription("Spans discarded because the batch queue was full."), metric.WithUnit("{span}"))
+	err = errors.Join(err, e)
+	failed, e := meter.Int64ObservableCounter("otelkit.exporter.spans.failed",
+		metric.WithDescription("Spans in batches that failed to export."), metric.WithUnit("{span}"))
+	err = errors.Join(err, e)
+	failures, e := meter.Int64ObservableCounter("otelkit.exporter.export.failures",
+		metric.WithDescription("Export calls that returned an error."), metric.WithUnit("{export}"))
+	err = errors.Join(err, e)
+	if err != nil {
+		return &InitializationError{Component: "export metrics", Cause: err}
+	}
+
+	_, err = meter.RegisterCallback(func(_ context.Context, o metric.Observer) error {
+		for _, t := range trackers {
+			s := t.stats()
+			attrs := metric.WithAttributes(attribute.String("exporter", s.Exporter))
+			o.ObserveInt64(queueSize, s.QueueDepth, attrs)
+			o.ObserveInt64(queued, clampInt64(s.SpansQueued), attrs)
+			o.ObserveInt64(exported, clampInt64(s.SpansExported), attrs)
+			o.ObserveInt64(dropped, clampInt64(s.SpansDropped), attrs)
+			o.ObserveInt64(failed, clampInt64(s.SpansFailed), attrs)
+			o.ObserveInt64(failures, clampInt64(s.FailedExports), attrs)
+		}
+		return nil
+	}, queueSize, queued, exported, dropped, failed, failures)
+	if err != nil {
+		return &InitializationError{Component: "export metrics", Cause: err}
+	}
+	return nil
+}
+
+// clampInt64 converts a counter to int64 for observation.
+func clampInt64(v uint64) int64 {
+	return int64(min(v, math.MaxInt64))
+}
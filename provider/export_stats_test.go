@@ -0,0 +1,229 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/kernelshard/otelkit/internal/config"
+)
+
+func TestExportTracker_Counts(t *testing.T) {
+	ctx := context.Background()
+	tracker := &exportTracker{name: "vendor", maxQueue: 3}
+	recorder := tracetest.NewSpanRecorder()
+	sp := &trackingProcessor{SpanProcessor: recorder, tracker: tracker}
+	flaky := &flakyExporter{down: true}
+	exporter := &dequeuingExporter{SpanExporter: &trackingExporter{SpanExporter: flaky, tracker: tracker}, tracker: tracker}
+
+	spans := append(testSpans(t), testSpans(t)...)
+	for _, s := range spans {
+		sp.OnEnd(s)
+	}
+	if got := len(recorder.Ended()); got != 3 {
+		t.Errorf("Expected 3 spans to reach the batch processor, got %d", got)
+	}
+	if stats := tracker.stats(); stats.SpansQueued != 3 || stats.SpansDropped != 1 || stats.QueueDepth != 3 {
+		t.Errorf("Expected 3 queued and 1 dropped span, got %+v", stats)
+	}
+
+	if err := exporter.ExportSpans(ctx, spans[:1]); err == nil {
+		t.Fatal("Expected the export to fail")
+	}
+	flaky.setDown(false)
+	if err := exporter.ExportSpans(ctx, spans[1:3]); err != nil {
+		t.Fatalf("ExportSpans failed: %v", err)
+	}
+
+	stats := tracker.stats()
+	if stats.Exporter != "vendor" || stats.SpansExported != 2 || stats.SpansFailed != 1 ||
+		stats.FailedExports != 1 || stats.QueueDepth != 0 {
+		t.Errorf("Unexpected stats: %+v", stats)
+	}
+	if stats.LastError == nil || stats.LastError.Error() != "collector unavailable" || stats.LastErrorTime.IsZero() {
+		t.Errorf("Expected the last export error, got %v at %v", stats.LastError, stats.LastErrorTime)
+	}
+}
+
+func TestExportTracker_InFlightBatchLeavesQueue(t *testing.T) {
+	ctx := context.Background()
+	tracker := &exportTracker{name: "primary", maxQueue: 2}
+	sp := &trackingProcessor{SpanProcessor: tracetest.NewSpanRecorder(), tracker: tracker}
+	inner := &blockingExporter{release: make(chan struct{})}
+	inner.block.Store(true)
+	exporter := &dequeuingExporter{SpanExporter: inner, tracker: tracker}
+
+	spans := testSpans(t)[:2]
+	for _, s := range spans {
+		sp.OnEnd(s)
+	}
+	done := make(chan error, 1)
+	go func() { done <- exporter.ExportSpans(ctx, spans) }()
+	for inner.blocked.Load() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	// The batch being exported no longer occupies the queue.
+	for _, s := range spans {
+		sp.OnEnd(s)
+	}
+	if stats := tracker.stats(); stats.SpansDropped != 0 || stats.SpansQueued != 4 || stats.QueueDepth != 4 {
+		t.Errorf("Expected a full queue besides the exported batch without drops, got %+v", stats)
+	}
+	close(inner.release)
+	if err := <-done; err != nil {
+		t.Fatalf("ExportSpans failed: %v", err)
+	}
+	if stats := tracker.stats(); stats.QueueDepth != 2 {
+		t.Errorf("Expected the queued spans to remain, got %+v", stats)
+	}
+}
+
+func TestTrackingProcessor_Shutdown(t *testing.T) {
+	ctx := context.Background()
+	tracker := &exportTracker{name: "primary", maxQueue: 10}
+	sp := &trackingProcessor{SpanProcessor: tracetest.NewSpanRecorder(), tracker: tracker}
+
+	spans := testSpans(t)
+	sp.OnEnd(spans[0])
+	if err := sp.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown failed: %v", err)
+	}
+	sp.OnEnd(spans[1])
+
+	// The recorder exports nothing, so the span queued before shutdown is lost.
+	if stats := tracker.stats(); stats.QueueDepth != 0 || stats.SpansQueued != 1 || stats.SpansDropped != 1 {
+		t.Errorf("Expected no pending spans after shutdown, got %+v", stats)
+	}
+}
+
+func TestExportTracker_PersistentQueue(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	tracker := &exportTracker{name: "primary", maxQueue: 10}
+	flaky := &flakyExporter{down: true}
+	queue, err := newPersistentExporter(flaky, dir, 0, 0, 0, tracker)
+	if err != nil {
+		t.Fatalf("newPersistentExporter failed: %v", err)
+	}
+	sp := &trackingProcessor{SpanProcessor: tracetest.NewSpanRecorder(), tracker: tracker}
+	exporter := &dequeuingExporter{SpanExporter: queue, tracker: tracker}
+	h := &HealthChecker{trackers: []*exportTracker{tracker}, failingAfter: time.Minute, now: time.Now}
+
+	batches := [][]sdktrace.ReadOnlySpan{testSpans(t), testSpans(t)}
+	var total int
+	for _, spans := range batches {
+		for _, s := range spans {
+			sp.OnEnd(s)
+		}
+		if err := exporter.ExportSpans(ctx, spans); err != nil {
+			t.Fatalf("Expected the batch to be spilled, got %v", err)
+		}
+		total += len(spans)
+		// Wait for a failed replay, which must not be counted again.
+		for flaky.exportAttempts() < 2 {
+			time.Sleep(time.Millisecond)
+		}
+	}
+	stats := tracker.stats()
+	if stats.SpansFailed != uint64(total) || stats.FailedExports != 1 || stats.SpansExported != 0 ||
+		stats.QueueDepth != 0 || stats.LastError == nil {
+		t.Errorf("Expected each spilled batch to count as failed once, got %+v", stats)
+	}
+	if status := h.Check(); status.State != HealthDegraded {
+		t.Errorf("Expected degraded health while batches are spilled, got %+v", status)
+	}
+
+	flaky.setDown(false)
+	if err := queue.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown failed: %v", err)
+	}
+	stats = tracker.stats()
+	if stats.SpansExported != uint64(total) || stats.SpansFailed != uint64(total) || stats.FailedExports != 1 {
+		t.Errorf("Expected the replayed batches to count as exported once, got %+v", stats)
+	}
+	if status := h.Check(); status.State != HealthHealthy {
+		t.Errorf("Expected healthy after the replay, got %+v", status)
+	}
+}
+
+func TestCreateExporter_TracksInPersistentQueue(t *testing.T) {
+	ctx := context.Background()
+	pc := NewProviderConfig("queue-service", "1.0.0").
+		WithOTLPExporter("localhost:4318", config.ProtocolHTTP, true).
+		WithPersistentQueue(t.TempDir(), 1<<20)
+
+	tracker := &exportTracker{name: "primary"}
+	exporter, err := createExporter(ctx, pc, tracker)
+	if err != nil {
+		t.Fatalf("createExporter failed: %v", err)
+	}
+	defer exporter.Shutdown(ctx)
+	queue, ok := exporter.(*persistentExporter)
+	if !ok {
+		t.Fatalf("Expected a persistent exporter, got %T", exporter)
+	}
+	if queue.tracker != tracker {
+		t.Error("Expected export outcomes to be tracked by the persistent queue")
+	}
+	if _, ok := queue.exporter.(*trackingExporter); ok {
+		t.Error("Expected the exporter inside the persistent queue not to be tracked as well")
+	}
+}
+
+func TestProvider_Stats(t *testing.T) {
+	ctx := context.Background()
+	t.Cleanup(ResetGlobal)
+
+	reader := sdkmetric.NewManualReader()
+	pc := newTestHandleConfig(&bytes.Buffer{}).WithMetricReader(reader)
+	p, err := New(ctx, pc)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	defer p.Shutdown(ctx)
+
+	for i := 0; i < 3; i++ {
+		_, span := p.Tracer("test").Start(ctx, "op")
+		span.End()
+	}
+	if err := p.ForceFlush(ctx); err != nil {
+		t.Fatalf("ForceFlush failed: %v", err)
+	}
+
+	stats := p.Stats()
+	if len(stats) != 1 || stats[0].Exporter != "primary" || stats[0].SpansQueued != 3 ||
+		stats[0].SpansExported != 3 || stats[0].QueueDepth != 0 || stats[0].LastError != nil {
+		t.Fatalf("Unexpected stats: %+v", stats)
+	}
+
+	exported, ok := collectMetricNames(t, reader)["otelkit.exporter.spans.exported"].Data.(metricdata.Sum[int64])
+	if !ok || len(exported.DataPoints) != 1 {
+		t.Fatalf("Expected an otelkit.exporter.spans.exported sum, got %+v", exported)
+	}
+	dp := exported.DataPoints[0]
+	if name, _ := dp.Attributes.Value("exporter"); dp.Value != 3 || name != attribute.StringValue("primary") {
+		t.Errorf("Expected 3 spans exported by primary, got %d with %v", dp.Value, dp.Attributes)
+	}
+
+	// A second provider from the same configuration counts its own spans.
+	pc.MetricReaders = nil
+	other, err := New(ctx, pc)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	defer other.Shutdown(ctx)
+	if stats := other.Stats(); len(stats) != 1 || stats[0].SpansQueued != 0 {
+		t.Errorf("Expected the second provider to start without spans, got %+v", stats)
+	}
+	if stats := p.Stats(); stats[0].SpansQueued != 3 {
+		t.Errorf("Expected the first provider to keep its counters, got %+v", stats)
+	}
+}
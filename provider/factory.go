@@ -9,10 +9,14 @@ sensible defaults and extensible configuration options.
 Key components:
 - InitializationError: Custom error type for initialization failures
 - createResource: Creates or returns an OpenTelemetry resource for service identification
-- createExporter: Factory method for OTLP exporters (HTTP or gRPC)
+- createExporter: Factory method for OTLP, Zipkin, console and file exporters (optionally persistent)
 - createBatchProcessor: Configures batch span processor with performance tuning options
+- createSpanProcessors: Fans out to the primary and any additional exporters, optionally behind tail sampling, truncation and redaction
 - newProvider: Orchestrates creation of the tracer provider from components
+- spanLimits: Applies the configured span limits, marking truncated attribute values
 - createSampler: Strategy pattern for sampler selection based on config
+- newSampler: Validates sampler settings and optionally wraps the sampler for runtime changes
+- newMeterProvider, newLoggerProvider: Create the metrics and logs pipelines sharing the tracing resource
 
 Usage example:
 
@@ -35,12 +39,17 @@ package provider
 
 import (
 	"context"
+	"io"
+	"net/http"
+	"os"
+	"time"
 
 	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
 	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
 	sdkresource "go.opentelemetry.io/otel/sdk/resource"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"google.golang.org/grpc/credentials"
 
 	"github.com/kernelshard/otelkit/internal/config"
 )
@@ -84,24 +93,62 @@ func createResource(ctx context.Context, cfg *ProviderConfig) (*sdkresource.Reso
 	return res, nil
 }
 
-// createExporter creates an OTLP exporter based on the configuration.
-func createExporter(ctx context.Context, cfg *ProviderConfig) (sdktrace.SpanExporter, error) {
+// createExporter creates a span exporter based on the configured protocol. If tracker
+// is not nil, it counts the outcome of every export; with a persistent queue the
+// queue counts them, so that spilled and replayed batches are each counted once.
+func createExporter(ctx context.Context, cfg *ProviderConfig, tracker *exportTracker) (sdktrace.SpanExporter, error) {
 	var exporter sdktrace.SpanExporter
 	var err error
 	switch cfg.Config.OTLPExporterProtocol {
-	case "http":
+	case config.ProtocolHTTP:
 		exporter, err = createHTTPExporter(ctx, cfg.Config)
-	case "grpc":
+	case config.ProtocolGRPC:
 		exporter, err = createGRPCExporter(ctx, cfg.Config)
+	case config.ProtocolStdout, config.ProtocolConsole:
+		exporter = newConsoleExporter(consoleWriter(cfg), false)
+	case config.ProtocolStdoutJSON:
+		exporter = newConsoleExporter(consoleWriter(cfg), true)
+	case config.ProtocolFile:
+		exporter, err = newFileExporter(cfg.Config.FileExporterPath, cfg.Config.FileExporterMaxSize,
+			cfg.Config.FileExporterMaxAge, cfg.Config.FileExporterMaxBackups)
+	case config.ProtocolZipkin:
+		exporter, err = createZipkinExporter(cfg.Config)
+	case config.ProtocolNone:
+		exporter = discardExporter{}
 	default:
 		return nil, &config.ConfigError{Field: "OTLPExporterProtocol", Message: config.ErrInvalidExporterProtocol}
 	}
 	if err != nil {
 		return nil, &InitializationError{Component: "exporter", Cause: err}
 	}
+	if cfg.Config.PersistentQueueDir != "" && cfg.Config.UsesOTLPEndpoint() {
+		queued, err := newPersistentExporter(exporter, cfg.Config.PersistentQueueDir,
+			cfg.Config.PersistentQueueMaxSize, config.DefaultPersistentQueueRetryInterval, cfg.ExportTimeout, tracker)
+		if err != nil {
+			_ = exporter.Shutdown(ctx)
+			return nil, &InitializationError{Component: "persistent queue", Cause: err}
+		}
+		exporter = queued
+	} else if tracker != nil {
+		exporter = &trackingExporter{SpanExporter: exporter, tracker: tracker}
+	}
 	return exporter, nil
 }
 
+// discardExporter drops every span, for the "none" protocol.
+type discardExporter struct{}
+
+func (discardExporter) ExportSpans(context.Context, []sdktrace.ReadOnlySpan) error { return nil }
+func (discardExporter) Shutdown(context.Context) error                             { return nil }
+
+// consoleWriter returns the configured console destination, defaulting to stdout.
+func consoleWriter(cfg *ProviderConfig) io.Writer {
+	if cfg.ConsoleWriter != nil {
+		return cfg.ConsoleWriter
+	}
+	return os.Stdout
+}
+
 // createBatchProcessor creates a batch span processor with the given exporter and configuration.
 func createBatchProcessor(exporter sdktrace.SpanExporter, cfg *ProviderConfig) sdktrace.SpanProcessor {
 	// Set defaults for batch processor options if not provided
@@ -126,85 +173,355 @@ func createBatchProcessor(exporter sdktrace.SpanExporter, cfg *ProviderConfig) s
 	)
 }
 
+// tracerComponents are the parts of a tracer provider that New exposes through the
+// Provider. They are kept apart from the ProviderConfig so that a configuration can
+// be used for several providers.
+type tracerComponents struct {
+	// tailSampler is the processor created for TailSampling, if enabled.
+	tailSampler *TailSamplingProcessor
+
+	// dynamicSampler is the sampler created for DynamicSampling, if enabled.
+	dynamicSampler *DynamicSampler
+
+	// exportTrackers count the spans of each export destination, the primary first.
+	exportTrackers []*exportTracker
+}
+
 // newProvider creates a new tracer provider based on the provided configuration.
 func newProvider(ctx context.Context, cfg *ProviderConfig) (*sdktrace.TracerProvider, error) {
 	res, err := createResource(ctx, cfg)
 	if err != nil {
 		return nil, err
 	}
+	tp, _, err := newTracerProvider(ctx, cfg, res)
+	return tp, err
+}
 
-	exporter, err := createExporter(ctx, cfg)
+// newTracerProvider creates a tracer provider for res, which New shares with the
+// meter provider, and returns the components created for it.
+func newTracerProvider(ctx context.Context, cfg *ProviderConfig, res *sdkresource.Resource) (*sdktrace.TracerProvider, *tracerComponents, error) {
+	sampler, err := newSampler(cfg)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	sampler := createSampler(cfg.Config)
-
-	bsp := createBatchProcessor(exporter, cfg)
+	processors, components, err := createSpanProcessors(ctx, cfg)
+	if err != nil {
+		if s, ok := sampler.(stoppableSampler); ok {
+			s.stop()
+		}
+		return nil, nil, err
+	}
+	if s, ok := sampler.(stoppableSampler); ok {
+		processors = append(processors, samplerStopProcessor{sampler: s})
+	}
+	if ds, ok := sampler.(*DynamicSampler); ok {
+		components.dynamicSampler = ds
+	}
 
-	tp := sdktrace.NewTracerProvider(
+	opts := []sdktrace.TracerProviderOption{
 		sdktrace.WithResource(res),
 		sdktrace.WithSampler(sampler),
-		sdktrace.WithSpanProcessor(bsp),
-	)
+		sdktrace.WithRawSpanLimits(spanLimits(cfg.Config)),
+	}
+	for _, sp := range processors {
+		opts = append(opts, sdktrace.WithSpanProcessor(sp))
+	}
+
+	return sdktrace.NewTracerProvider(opts...), components, nil
+}
 
-	return tp, nil
+// newSampler creates the provider's sampler, wrapped in a DynamicSampler if runtime
+// changes are enabled. Unlike createSampler it reports invalid rule-based and Jaeger
+// remote settings.
+func newSampler(cfg *ProviderConfig) (sdktrace.Sampler, error) {
+	if cfg.DynamicSampling {
+		ds, err := NewDynamicSampler(cfg.Config, cfg.SamplingAuditLogger)
+		if err != nil {
+			return nil, &InitializationError{Component: "sampler", Cause: err}
+		}
+		return ds, nil
+	}
+	switch cfg.Config.SamplingType {
+	case config.SamplingRuleBased, config.SamplingJaegerRemote, config.SamplingParentBasedJaegerRemote:
+		if err := cfg.Config.ValidateSampling(); err != nil {
+			return nil, &InitializationError{Component: "sampler", Cause: err}
+		}
+	}
+	return createSampler(cfg.Config), nil
+}
+
+// createSpanProcessors creates one batch processor per export destination: the primary
+// exporter described by cfg.Config followed by every additional exporter. Separate
+// processors give each destination its own queue and export goroutine, and their
+// spans are counted for Provider.Stats. With tail sampling enabled they are wrapped
+// in a single TailSamplingProcessor. With an attribute value length limit the result
+// is wrapped in a processor cutting and marking long values, and with redaction rules
+// in a RedactionProcessor. As the SDK does not cut values itself (see spanLimits),
+// redaction sees them in full.
+func createSpanProcessors(ctx context.Context, cfg *ProviderConfig) ([]sdktrace.SpanProcessor, *tracerComponents, error) {
+	components := &tracerComponents{}
+	bsp, tracker, err := createTrackedBatchProcessor(ctx, primaryExporterName, cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	processors := []sdktrace.SpanProcessor{bsp}
+	components.exportTrackers = []*exportTracker{tracker}
+
+	for _, e := range cfg.Config.Exporters {
+		exporterCfg := cfg.forExporter(e)
+		bsp, tracker, err := createTrackedBatchProcessor(ctx, e.Name, exporterCfg)
+		if err != nil {
+			shutdownProcessors(ctx, processors)
+			return nil, nil, &InitializationError{Component: "exporter " + e.Name, Cause: err}
+		}
+		processors = append(processors, bsp)
+		components.exportTrackers = append(components.exportTrackers, tracker)
+	}
+
+	if cfg.TailSampling != nil {
+		tsp, err := NewTailSamplingProcessor(*cfg.TailSampling, processors...)
+		if err != nil {
+			shutdownProcessors(ctx, processors)
+			return nil, nil, err
+		}
+		components.tailSampler = tsp
+		processors = []sdktrace.SpanProcessor{tsp}
+	}
+
+	if limit := limitOrDefault(cfg.Config.SpanAttributeValueLengthLimit, config.DefaultSpanAttributeValueLengthLimit); limit > 0 {
+		processors = []sdktrace.SpanProcessor{&truncatingProcessor{next: processors, limit: limit}}
+	}
+
+	if len(cfg.RedactionRules) > 0 {
+		rp, err := NewRedactionProcessor(cfg.RedactionRules, processors...)
+		if err != nil {
+			shutdownProcessors(ctx, processors)
+			return nil, nil, err
+		}
+		processors = []sdktrace.SpanProcessor{rp}
+	}
+	return processors, components, nil
+}
+
+// shutdownProcessors releases processors created before a later initialization step failed.
+func shutdownProcessors(ctx context.Context, processors []sdktrace.SpanProcessor) {
+	for _, sp := range processors {
+		_ = sp.Shutdown(ctx)
+	}
 }
 
 // createGRPCExporter creates an OTLP gRPC exporter configured with the provided settings.
 func createGRPCExporter(ctx context.Context, cfg *config.Config) (sdktrace.SpanExporter, error) {
+	// gRPC has no request path; only the host and the scheme of a URL endpoint apply.
+	endpoint, err := cfg.TracesEndpoint()
+	if err != nil {
+		return nil, err
+	}
 	opts := []otlptracegrpc.Option{
-		otlptracegrpc.WithEndpoint(cfg.OTLPExporterEndpoint),
+		otlptracegrpc.WithEndpoint(endpoint.Host),
 	}
-	if cfg.OTLPExporterInsecure {
+	if endpoint.Insecure(cfg.OTLPExporterInsecure) {
 		opts = append(opts, otlptracegrpc.WithInsecure())
 	}
+	tlsCfg, err := createTLSConfig(cfg, endpoint)
+	if err != nil {
+		return nil, err
+	}
+	if tlsCfg != nil {
+		opts = append(opts, otlptracegrpc.WithTLSCredentials(credentials.NewTLS(tlsCfg)))
+	}
+	if len(cfg.OTLPExporterHeaders) > 0 {
+		opts = append(opts, otlptracegrpc.WithHeaders(cfg.OTLPExporterHeaders))
+	}
+	if cfg.OTLPCompression == config.CompressionGzip {
+		opts = append(opts, otlptracegrpc.WithCompressor(config.CompressionGzip))
+	}
+	if cfg.OTLPTimeout > 0 {
+		opts = append(opts, otlptracegrpc.WithTimeout(cfg.OTLPTimeout))
+	}
+	policy := retryPolicy(cfg)
+	opts = append(opts, otlptracegrpc.WithRetry(otlptracegrpc.RetryConfig{
+		Enabled:         policy.enabled,
+		InitialInterval: policy.initialInterval,
+		MaxInterval:     policy.maxInterval,
+		MaxElapsedTime:  policy.maxElapsedTime,
+	}))
 	return otlptracegrpc.New(ctx, opts...)
 }
 
 // createHTTPExporter creates an OTLP HTTP exporter configured with the provided settings.
 func createHTTPExporter(ctx context.Context, cfg *config.Config) (sdktrace.SpanExporter, error) {
+	endpoint, err := cfg.TracesEndpoint()
+	if err != nil {
+		return nil, err
+	}
 	opts := []otlptracehttp.Option{
-		otlptracehttp.WithEndpoint(cfg.OTLPExporterEndpoint),
+		otlptracehttp.WithEndpoint(endpoint.Host),
+	}
+	if endpoint.Path != "" {
+		opts = append(opts, otlptracehttp.WithURLPath(endpoint.Path))
 	}
-	if cfg.OTLPExporterInsecure {
+	if endpoint.Insecure(cfg.OTLPExporterInsecure) {
 		opts = append(opts, otlptracehttp.WithInsecure())
 	}
+	tlsCfg, err := createTLSConfig(cfg, endpoint)
+	if err != nil {
+		return nil, err
+	}
+	if tlsCfg != nil {
+		opts = append(opts, otlptracehttp.WithTLSClientConfig(tlsCfg))
+	}
+	if cfg.PersistentQueueDir != "" {
+		// The persistent queue tells rejected batches from an unavailable collector by
+		// the response status, which the exporter only reports as error text.
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.TLSClientConfig = tlsCfg
+		timeout := cfg.OTLPTimeout
+		if timeout == 0 {
+			timeout = config.DefaultOTLPTimeout
+		}
+		opts = append(opts, otlptracehttp.WithHTTPClient(&http.Client{
+			Transport: statusRecordingTransport{base: transport},
+			Timeout:   timeout,
+		}))
+	}
+	if len(cfg.OTLPExporterHeaders) > 0 {
+		opts = append(opts, otlptracehttp.WithHeaders(cfg.OTLPExporterHeaders))
+	}
+	if cfg.OTLPCompression == config.CompressionGzip {
+		opts = append(opts, otlptracehttp.WithCompression(otlptracehttp.GzipCompression))
+	}
+	if cfg.OTLPTimeout > 0 {
+		opts = append(opts, otlptracehttp.WithTimeout(cfg.OTLPTimeout))
+	}
+	policy := retryPolicy(cfg)
+	opts = append(opts, otlptracehttp.WithRetry(otlptracehttp.RetryConfig{
+		Enabled:         policy.enabled,
+		InitialInterval: policy.initialInterval,
+		MaxInterval:     policy.maxInterval,
+		MaxElapsedTime:  policy.maxElapsedTime,
+	}))
 	return otlptracehttp.New(ctx, opts...)
 }
 
+// createZipkinExporter creates a Zipkin exporter posting to cfg.ZipkinEndpoint.
+// Headers, the OTLP request timeout and TLS settings apply as for OTLP/HTTP.
+func createZipkinExporter(cfg *config.Config) (sdktrace.SpanExporter, error) {
+	endpoint, err := config.ParseEndpoint("ZipkinEndpoint", cfg.ZipkinEndpoint)
+	if err != nil {
+		return nil, err
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	tlsCfg, err := createTLSConfig(cfg, endpoint)
+	if err != nil {
+		return nil, err
+	}
+	if tlsCfg != nil {
+		transport.TLSClientConfig = tlsCfg
+	}
+	client := &http.Client{Transport: transport, Timeout: cfg.OTLPTimeout}
+	exporter := newZipkinExporter(cfg.ZipkinEndpoint, client, cfg.OTLPExporterHeaders)
+	exporter.ownsClient = true
+	return exporter, nil
+}
+
+// exportRetryPolicy is the retry configuration shared by the HTTP and gRPC exporters,
+// whose RetryConfig types are distinct but identical.
+type exportRetryPolicy struct {
+	enabled         bool
+	initialInterval time.Duration
+	maxInterval     time.Duration
+	maxElapsedTime  time.Duration
+}
+
+// retryPolicy resolves the configured retry policy, substituting the defaults for
+// unset intervals.
+func retryPolicy(cfg *config.Config) exportRetryPolicy {
+	policy := exportRetryPolicy{
+		enabled:         !cfg.RetryDisabled,
+		initialInterval: cfg.RetryInitialInterval,
+		maxInterval:     cfg.RetryMaxInterval,
+		maxElapsedTime:  cfg.RetryMaxElapsedTime,
+	}
+	if policy.initialInterval == 0 {
+		policy.initialInterval = config.DefaultRetryInitialInterval
+	}
+	if policy.maxInterval == 0 {
+		policy.maxInterval = config.DefaultRetryMaxInterval
+	}
+	if policy.maxElapsedTime == 0 {
+		policy.maxElapsedTime = config.DefaultRetryMaxElapsedTime
+	}
+	return policy
+}
+
 // SamplerFactory defines the interface for creating samplers.
 // This allows for extensible sampler creation without modifying existing code.
 type SamplerFactory interface {
 	CreateSampler(cfg *config.Config) sdktrace.Sampler
 }
 
-// ProbabilisticSamplerFactory creates probabilistic samplers
+// ProbabilisticSamplerFactory creates probabilistic samplers. Root spans are sampled by
+// trace ID ratio and child spans follow their parent (parentbased_traceidratio).
 type ProbabilisticSamplerFactory struct{}
 
 func (f *ProbabilisticSamplerFactory) CreateSampler(cfg *config.Config) sdktrace.Sampler {
 	return sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SamplingRatio))
 }
 
-// AlwaysOnSamplerFactory creates always-on samplers
+// TraceIDRatioSamplerFactory creates trace ID ratio samplers that ignore the parent's
+// decision (traceidratio).
+type TraceIDRatioSamplerFactory struct{}
+
+func (f *TraceIDRatioSamplerFactory) CreateSampler(cfg *config.Config) sdktrace.Sampler {
+	return sdktrace.TraceIDRatioBased(cfg.SamplingRatio)
+}
+
+// AlwaysOnSamplerFactory creates always-on samplers that ignore the parent's decision
 type AlwaysOnSamplerFactory struct{}
 
 func (f *AlwaysOnSamplerFactory) CreateSampler(cfg *config.Config) sdktrace.Sampler {
 	return sdktrace.AlwaysSample()
 }
 
-// AlwaysOffSamplerFactory creates always-off samplers
+// AlwaysOffSamplerFactory creates always-off samplers that ignore the parent's decision
 type AlwaysOffSamplerFactory struct{}
 
 func (f *AlwaysOffSamplerFactory) CreateSampler(cfg *config.Config) sdktrace.Sampler {
 	return sdktrace.NeverSample()
 }
 
+// ParentBasedAlwaysOnSamplerFactory samples all root spans; child spans follow their
+// parent (parentbased_always_on, the specification's default).
+type ParentBasedAlwaysOnSamplerFactory struct{}
+
+func (f *ParentBasedAlwaysOnSamplerFactory) CreateSampler(cfg *config.Config) sdktrace.Sampler {
+	return sdktrace.ParentBased(sdktrace.AlwaysSample())
+}
+
+// ParentBasedAlwaysOffSamplerFactory drops all root spans; child spans follow their
+// parent, so traces started upstream are still recorded (parentbased_always_off).
+type ParentBasedAlwaysOffSamplerFactory struct{}
+
+func (f *ParentBasedAlwaysOffSamplerFactory) CreateSampler(cfg *config.Config) sdktrace.Sampler {
+	return sdktrace.ParentBased(sdktrace.NeverSample())
+}
+
 // samplerFactories holds the mapping of sampler types to their factories
 var samplerFactories = map[config.SamplingType]SamplerFactory{
 	config.SamplingProbabilistic: &ProbabilisticSamplerFactory{},
 	config.SamplingAlwaysOn:      &AlwaysOnSamplerFactory{},
 	config.SamplingAlwaysOff:     &AlwaysOffSamplerFactory{},
+	config.SamplingRateLimited:   &RateLimitedSamplerFactory{},
+	config.SamplingRuleBased:     &RuleBasedSamplerFactory{},
+
+	config.SamplingTraceIDRatio:            &TraceIDRatioSamplerFactory{},
+	config.SamplingParentBasedTraceIDRatio: &ProbabilisticSamplerFactory{},
+	config.SamplingParentBasedAlwaysOn:     &ParentBasedAlwaysOnSamplerFactory{},
+	config.SamplingParentBasedAlwaysOff:    &ParentBasedAlwaysOffSamplerFactory{},
+	config.SamplingJaegerRemote:            &JaegerRemoteSamplerFactory{},
+	config.SamplingParentBasedJaegerRemote: &ParentBasedJaegerRemoteSamplerFactory{},
 }
 
 // createSampler creates a sampler instance based on the provided configuration.
@@ -0,0 +1,227 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// rotationTimeFormat is used in the names of rotated files. It sorts lexically in
+// chronological order, which is what backup pruning relies on.
+const rotationTimeFormat = "20060102T150405.000000000"
+
+// fileExporter writes spans to a local file as OTLP/JSON, one export request per
+// line. The file is rotated when it grows beyond maxSize bytes or becomes older
+// than maxAge; at most maxBackups rotated files are kept next to it.
+//
+// The output can be replayed later with the collector's otlpjson-capable receivers,
+// which makes this exporter suitable for sandboxes without network access.
+type fileExporter struct {
+	mu         sync.Mutex
+	path       string
+	maxSize    int64
+	maxAge     time.Duration
+	maxBackups int
+
+	file     *os.File
+	size     int64
+	openedAt time.Time
+	stopped  bool
+
+	now func() time.Time
+}
+
+// newFileExporter creates a file exporter and opens (or creates) the target file. An
+// existing file is appended to and rotated by its own age. A zero maxSize or maxAge
+// disables that rotation trigger; a zero maxBackups keeps every rotated file.
+func newFileExporter(path string, maxSize int64, maxAge time.Duration, maxBackups int) (*fileExporter, error) {
+	if path == "" {
+		return nil, errors.New("file exporter path is required")
+	}
+	e := &fileExporter{
+		path:       path,
+		maxSize:    maxSize,
+		maxAge:     maxAge,
+		maxBackups: maxBackups,
+		now:        time.Now,
+	}
+	if err := e.open(); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// ExportSpans encodes the batch as a single OTLP/JSON line and appends it to the file,
+// rotating first if the line would exceed the size limit or the file is too old.
+func (e *fileExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	if len(spans) == 0 {
+		return nil
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	line, err := json.Marshal(encodeOTLPTraceData(spans))
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.stopped {
+		return nil
+	}
+	if e.file == nil {
+		// A previous rotation failed to open the new file.
+		if err := e.open(); err != nil {
+			return err
+		}
+	}
+	if e.shouldRotate(int64(len(line))) {
+		if err := e.rotate(); err != nil {
+			return err
+		}
+	}
+
+	n, err := e.file.Write(line)
+	e.size += int64(n)
+	return err
+}
+
+// Shutdown flushes the file to stable storage and closes it.
+func (e *fileExporter) Shutdown(ctx context.Context) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.stopped {
+		return nil
+	}
+	e.stopped = true
+	return e.closeFile()
+}
+
+func (e *fileExporter) open() error {
+	if dir := filepath.Dir(e.path); dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return err
+		}
+	}
+	f, err := os.OpenFile(e.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return err
+	}
+	e.file = f
+	e.size = info.Size()
+	e.openedAt = e.now()
+	if e.size > 0 {
+		// Appending to a file from before a restart: its age counts from its creation,
+		// so that frequent restarts do not postpone age rotation indefinitely.
+		e.openedAt = fileCreatedAt(e.path, info)
+	}
+	return nil
+}
+
+func (e *fileExporter) closeFile() error {
+	if e.file == nil {
+		return nil
+	}
+	syncErr := e.file.Sync()
+	closeErr := e.file.Close()
+	e.file = nil
+	return errors.Join(syncErr, closeErr)
+}
+
+func (e *fileExporter) shouldRotate(incoming int64) bool {
+	if e.size == 0 {
+		return false
+	}
+	if e.maxSize > 0 && e.size+incoming > e.maxSize {
+		return true
+	}
+	return e.maxAge > 0 && e.now().Sub(e.openedAt) >= e.maxAge
+}
+
+// rotate renames the current file with a timestamp suffix, opens a fresh file and
+// prunes old backups. If the file cannot be renamed it is reopened, so the exporter
+// keeps appending to it; if the fresh file cannot be opened, the next export retries.
+func (e *fileExporter) rotate() error {
+	closeErr := e.closeFile()
+	if err := os.Rename(e.path, e.backupName(e.now())); err != nil {
+		return errors.Join(closeErr, err, e.open())
+	}
+	if err := e.open(); err != nil {
+		return err
+	}
+	return errors.Join(closeErr, e.pruneBackups())
+}
+
+// backupName returns the rotated file name, e.g. traces-20251006T101500.000000000.jsonl.
+func (e *fileExporter) backupName(t time.Time) string {
+	ext := filepath.Ext(e.path)
+	base := strings.TrimSuffix(e.path, ext)
+	return base + "-" + t.UTC().Format(rotationTimeFormat) + ext
+}
+
+// backups lists rotated files for this exporter, oldest first. Only files named like
+// backupName are considered, so that other files sharing the prefix, such as those of
+// an exporter writing to traces-archive.jsonl next to traces.jsonl, are left alone.
+func (e *fileExporter) backups() ([]string, error) {
+	dir, name := filepath.Split(e.path)
+	ext := filepath.Ext(name)
+	prefix := strings.TrimSuffix(name, ext) + "-"
+	entries, err := os.ReadDir(filepath.Clean(dir))
+	if err != nil {
+		return nil, err
+	}
+
+	var backups []string
+	for _, entry := range entries {
+		stamp, ok := strings.CutPrefix(entry.Name(), prefix)
+		if !ok || !entry.Type().IsRegular() {
+			continue
+		}
+		stamp, ok = strings.CutSuffix(stamp, ext)
+		if !ok {
+			continue
+		}
+		if _, err := time.Parse(rotationTimeFormat, stamp); err != nil {
+			continue
+		}
+		backups = append(backups, filepath.Join(dir, entry.Name()))
+	}
+	sort.Strings(backups)
+	return backups, nil
+}
+
+func (e *fileExporter) pruneBackups() error {
+	if e.maxBackups <= 0 {
+		return nil
+	}
+	files, err := e.backups()
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for len(files) > e.maxBackups {
+		if err := os.Remove(files[0]); err != nil && !os.IsNotExist(err) {
+			errs = append(errs, err)
+		}
+		files = files[1:]
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,23 @@
+//go:build linux
+
+package provider
+
+import (
+	"os"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// fileCreatedAt returns when the file at path was created, or its modification time
+// if that is earlier or the file system does not record creation times.
+func fileCreatedAt(path string, info os.FileInfo) time.Time {
+	var stx unix.Statx_t
+	if err := unix.Statx(unix.AT_FDCWD, path, 0, unix.STATX_BTIME, &stx); err != nil || stx.Mask&unix.STATX_BTIME == 0 {
+		return info.ModTime()
+	}
+	if birth := time.Unix(stx.Btime.Sec, int64(stx.Btime.Nsec)); birth.Before(info.ModTime()) {
+		return birth
+	}
+	return info.ModTime()
+}
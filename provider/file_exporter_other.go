@@ -0,0 +1,14 @@
+//go:build !linux
+
+package provider
+
+import (
+	"os"
+	"time"
+)
+
+// fileCreatedAt returns the modification time of the file, the closest portable
+// approximation of its creation time.
+func fileCreatedAt(_ string, info os.FileInfo) time.Time {
+	return info.ModTime()
+}
@@ -0,0 +1,317 @@
+package provider
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/kernelshard/otelkit/internal/config"
+)
+
+// testSpans records a parent span with an erroring child and returns the snapshots.
+func testSpans(t *testing.T) []sdktrace.ReadOnlySpan {
+	t.Helper()
+	ctx := context.Background()
+
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	tr := tp.Tracer("file-test")
+
+	ctx, parent := tr.Start(ctx, "parent")
+	parent.SetAttributes(attribute.Int("retry.count", 3), attribute.StringSlice("tags", []string{"a", "b"}))
+	_, child := tr.Start(ctx, "child")
+	child.AddEvent("cache.miss", trace.WithAttributes(attribute.String("key", "user:1")))
+	child.SetStatus(codes.Error, "boom")
+	child.End()
+	parent.End()
+
+	return recorder.Ended()
+}
+
+func readLines(t *testing.T, path string) []string {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("Failed to open %s: %v", path, err)
+	}
+	defer f.Close()
+
+	var lines []string
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 1024*1024), 1024*1024)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	return lines
+}
+
+func TestFileExporter_WritesOTLPJSON(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "traces.jsonl")
+
+	exp, err := newFileExporter(path, 0, 0, 0)
+	if err != nil {
+		t.Fatalf("newFileExporter failed: %v", err)
+	}
+	spans := testSpans(t)
+	if err := exp.ExportSpans(ctx, spans); err != nil {
+		t.Fatalf("ExportSpans failed: %v", err)
+	}
+	if err := exp.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown failed: %v", err)
+	}
+
+	lines := readLines(t, path)
+	if len(lines) != 1 {
+		t.Fatalf("Expected 1 line, got %d", len(lines))
+	}
+
+	var data otlpTraceData
+	if err := json.Unmarshal([]byte(lines[0]), &data); err != nil {
+		t.Fatalf("Failed to decode OTLP/JSON: %v", err)
+	}
+	if len(data.ResourceSpans) != 1 || len(data.ResourceSpans[0].ScopeSpans) != 1 {
+		t.Fatalf("Unexpected grouping: %+v", data)
+	}
+	got := data.ResourceSpans[0].ScopeSpans[0].Spans
+	if len(got) != 2 {
+		t.Fatalf("Expected 2 spans, got %d", len(got))
+	}
+
+	child := got[0]
+	if child.TraceID != spans[0].SpanContext().TraceID().String() {
+		t.Errorf("Expected hex trace ID %s, got %s", spans[0].SpanContext().TraceID(), child.TraceID)
+	}
+	if child.ParentSpanID != spans[1].SpanContext().SpanID().String() {
+		t.Errorf("Expected parent span ID %s, got %s", spans[1].SpanContext().SpanID(), child.ParentSpanID)
+	}
+	if child.Status.Code != otlpStatusError || child.Status.Message != "boom" {
+		t.Errorf("Unexpected status %+v", child.Status)
+	}
+	if len(child.Events) != 1 || child.Events[0].Name != "cache.miss" {
+		t.Errorf("Expected cache.miss event, got %+v", child.Events)
+	}
+
+	parent := got[1]
+	if parent.Attributes[0].Value.IntValue == nil || *parent.Attributes[0].Value.IntValue != "3" {
+		t.Errorf("Expected intValue encoded as string \"3\", got %+v", parent.Attributes[0].Value)
+	}
+	if parent.Attributes[1].Value.ArrayValue == nil || len(parent.Attributes[1].Value.ArrayValue.Values) != 2 {
+		t.Errorf("Expected array value with 2 entries, got %+v", parent.Attributes[1].Value)
+	}
+}
+
+func TestFileExporter_RotatesBySize(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	path := filepath.Join(dir, "traces.jsonl")
+
+	exp, err := newFileExporter(path, 1, 0, 2)
+	if err != nil {
+		t.Fatalf("newFileExporter failed: %v", err)
+	}
+	tick := time.Date(2025, 10, 6, 10, 0, 0, 0, time.UTC)
+	exp.now = func() time.Time {
+		tick = tick.Add(time.Second)
+		return tick
+	}
+
+	spans := testSpans(t)
+	for i := 0; i < 5; i++ {
+		if err := exp.ExportSpans(ctx, spans); err != nil {
+			t.Fatalf("ExportSpans failed: %v", err)
+		}
+	}
+	if err := exp.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown failed: %v", err)
+	}
+
+	backups, err := exp.backups()
+	if err != nil {
+		t.Fatalf("backups failed: %v", err)
+	}
+	if len(backups) != 2 {
+		t.Errorf("Expected 2 backups to be kept, got %d: %v", len(backups), backups)
+	}
+	if lines := readLines(t, path); len(lines) != 1 {
+		t.Errorf("Expected current file to hold 1 line, got %d", len(lines))
+	}
+}
+
+func TestFileExporter_RotatesByAge(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "traces.jsonl")
+
+	exp, err := newFileExporter(path, 0, time.Hour, 0)
+	if err != nil {
+		t.Fatalf("newFileExporter failed: %v", err)
+	}
+	now := time.Date(2025, 10, 6, 10, 0, 0, 0, time.UTC)
+	exp.now = func() time.Time { return now }
+	exp.openedAt = now
+
+	spans := testSpans(t)
+	if err := exp.ExportSpans(ctx, spans); err != nil {
+		t.Fatalf("ExportSpans failed: %v", err)
+	}
+	now = now.Add(2 * time.Hour)
+	if err := exp.ExportSpans(ctx, spans); err != nil {
+		t.Fatalf("ExportSpans failed: %v", err)
+	}
+	_ = exp.Shutdown(ctx)
+
+	backups, _ := exp.backups()
+	if len(backups) != 1 {
+		t.Errorf("Expected 1 backup after age rotation, got %d", len(backups))
+	}
+}
+
+func TestFileExporter_PrunesOnlyItsOwnBackups(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	path := filepath.Join(dir, "tr[a]ces.jsonl")
+	others := []string{
+		filepath.Join(dir, "tr[a]ces-archive.jsonl"),
+		filepath.Join(dir, "tr[a]ces-archive-20251006T100000.000000000.jsonl"),
+	}
+	for _, other := range others {
+		if err := os.WriteFile(other, []byte("{}\n"), 0o644); err != nil {
+			t.Fatalf("WriteFile failed: %v", err)
+		}
+	}
+
+	exp, err := newFileExporter(path, 1, 0, 1)
+	if err != nil {
+		t.Fatalf("newFileExporter failed: %v", err)
+	}
+	tick := time.Date(2025, 10, 6, 10, 0, 0, 0, time.UTC)
+	exp.now = func() time.Time {
+		tick = tick.Add(time.Second)
+		return tick
+	}
+	spans := testSpans(t)
+	for i := 0; i < 4; i++ {
+		if err := exp.ExportSpans(ctx, spans); err != nil {
+			t.Fatalf("ExportSpans failed: %v", err)
+		}
+	}
+	_ = exp.Shutdown(ctx)
+
+	if backups, _ := exp.backups(); len(backups) != 1 {
+		t.Errorf("Expected 1 backup of a path with glob characters, got %v", backups)
+	}
+	for _, other := range others {
+		if _, err := os.Stat(other); err != nil {
+			t.Errorf("Expected %s to be left alone, got %v", filepath.Base(other), err)
+		}
+	}
+}
+
+func TestFileExporter_RecoversFromFailedRotation(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "traces.jsonl")
+
+	exp, err := newFileExporter(path, 1, 0, 0)
+	if err != nil {
+		t.Fatalf("newFileExporter failed: %v", err)
+	}
+	now := time.Date(2025, 10, 6, 10, 0, 0, 0, time.UTC)
+	exp.now = func() time.Time { return now }
+
+	spans := testSpans(t)
+	if err := exp.ExportSpans(ctx, spans); err != nil {
+		t.Fatalf("ExportSpans failed: %v", err)
+	}
+
+	// A non-empty directory at the backup name makes the rename fail.
+	blocker := exp.backupName(now)
+	if err := os.MkdirAll(filepath.Join(blocker, "keep"), 0o755); err != nil {
+		t.Fatalf("MkdirAll failed: %v", err)
+	}
+	if err := exp.ExportSpans(ctx, spans); err == nil {
+		t.Fatal("Expected the rotation to fail")
+	}
+	if exp.file == nil {
+		t.Error("Expected the file to be reopened after the failed rename")
+	}
+
+	if err := os.RemoveAll(blocker); err != nil {
+		t.Fatalf("RemoveAll failed: %v", err)
+	}
+	if err := exp.ExportSpans(ctx, spans); err != nil {
+		t.Fatalf("Expected the exporter to recover after a failed rotation, got %v", err)
+	}
+	_ = exp.Shutdown(ctx)
+
+	if lines := readLines(t, path); len(lines) != 1 {
+		t.Errorf("Expected the fresh file to hold 1 line, got %d", len(lines))
+	}
+	if lines := readLines(t, blocker); len(lines) != 1 {
+		t.Errorf("Expected the backup to hold the line written before the failure, got %d", len(lines))
+	}
+}
+
+func TestFileExporter_ExistingFileKeepsItsAge(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "traces.jsonl")
+	if err := os.WriteFile(path, []byte("{}\n"), 0o644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	old := time.Now().Add(-2 * time.Hour)
+	if err := os.Chtimes(path, old, old); err != nil {
+		t.Fatalf("Chtimes failed: %v", err)
+	}
+
+	exp, err := newFileExporter(path, 0, time.Hour, 0)
+	if err != nil {
+		t.Fatalf("newFileExporter failed: %v", err)
+	}
+	if err := exp.ExportSpans(ctx, testSpans(t)); err != nil {
+		t.Fatalf("ExportSpans failed: %v", err)
+	}
+	_ = exp.Shutdown(ctx)
+
+	if backups, _ := exp.backups(); len(backups) != 1 {
+		t.Errorf("Expected the file from before the restart to be rotated by age, got %d backups", len(backups))
+	}
+}
+
+func TestFileExporter_ShutdownFlushesThroughProvider(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "nested", "traces.jsonl")
+
+	pc := NewProviderConfig("file-service", "1.0.0").
+		WithFileExporter(path, 0, 0, 0).
+		WithSampling(config.SamplingAlwaysOn, 1.0)
+
+	tp, err := newProvider(ctx, pc)
+	if err != nil {
+		t.Fatalf("newProvider failed: %v", err)
+	}
+	_, span := tp.Tracer("test").Start(ctx, "work")
+	span.End()
+
+	if err := ShutdownTracerProvider(ctx, tp); err != nil {
+		t.Fatalf("Shutdown failed: %v", err)
+	}
+	if lines := readLines(t, path); len(lines) != 1 {
+		t.Errorf("Expected pending spans to be flushed on shutdown, got %d lines", len(lines))
+	}
+}
+
+func TestConfig_ValidateFileExporter(t *testing.T) {
+	pc := NewProviderConfig("test", "1.0.0").WithFileExporter("", 0, 0, 0)
+	if err := pc.Config.Validate(); err == nil {
+		t.Error("Expected validation error for empty file path")
+	}
+}
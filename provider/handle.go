@@ -0,0 +1,316 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/log"
+	lognoop "go.opentelemetry.io/otel/log/noop"
+	"go.opentelemetry.io/otel/metric"
+	metricnoop "go.opentelemetry.io/otel/metric/noop"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+
+	"github.com/kernelshard/otelkit/internal/config"
+)
+
+// globalMu serializes changes otelkit makes to the OpenTelemetry global tracer, meter
+// and logger providers, so that checking whether a provider is global and replacing it is atomic.
+var globalMu sync.Mutex
+
+// globalProvider is the Provider whose tracer provider is global, for HealthHandler.
+// It is nil if the global tracer provider was not installed from a Provider.
+var globalProvider *Provider
+
+// setGlobal installs tp as the OpenTelemetry global tracer provider and records p,
+// the Provider it belongs to or nil, as globalProvider.
+func setGlobal(tp trace.TracerProvider, p *Provider) {
+	globalMu.Lock()
+	defer globalMu.Unlock()
+	otel.SetTracerProvider(tp)
+	globalProvider = p
+}
+
+// unsetGlobal installs a no-op tracer provider if tp is the global tracer provider,
+// so that spans are not started on a provider that has been shut down.
+func unsetGlobal(tp trace.TracerProvider) {
+	globalMu.Lock()
+	defer globalMu.Unlock()
+	if globalProvider != nil && globalProvider.tp == tp {
+		globalProvider = nil
+	}
+	if otel.GetTracerProvider() == tp {
+		otel.SetTracerProvider(noop.NewTracerProvider())
+	}
+}
+
+// ResetGlobal replaces the global tracer, meter and logger providers with no-op
+// providers. It does not shut down the providers that were global. Tests use it to
+// keep providers created by one test from leaking into the next:
+//
+//	t.Cleanup(provider.ResetGlobal)
+func ResetGlobal() {
+	setGlobal(noop.NewTracerProvider(), nil)
+	setGlobalMeterProvider(metricnoop.NewMeterProvider())
+	setGlobalLoggerProvider(lognoop.NewLoggerProvider())
+}
+
+// Provider is a tracer provider created by otelkit together with its lifecycle and,
+// if metrics or logs are enabled, the meter and logger providers sharing its resource.
+// It replaces handling a raw *sdktrace.TracerProvider and a separate shutdown function.
+type Provider struct {
+	tp  *sdktrace.TracerProvider
+	mp  *sdkmetric.MeterProvider
+	lp  *sdklog.LoggerProvider
+	cfg *ProviderConfig
+
+	tailSampler    *TailSamplingProcessor
+	dynamicSampler *DynamicSampler
+	exportTrackers []*exportTracker
+
+	runtimeMu sync.Mutex
+	runtime   *RuntimeMetrics
+}
+
+// New creates a Provider from cfg and, unless cfg.SkipGlobalRegistration is set,
+// installs it as the OpenTelemetry global tracer provider, replacing any previous
+// one. Creating a second provider therefore makes the second one global. With
+// WithMetrics or WithMetricReader, a meter provider is created as well and installed
+// as the global meter provider; likewise a logger provider with WithLogs or
+// WithLogProcessor. With WithStartupProbe, New checks that the collector is reachable
+// first and, in fail-fast mode, returns an error if it is not.
+//
+// Example:
+//
+//	p, err := provider.New(ctx, provider.NewProviderConfig("payment-service", "v1.2.3"))
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	defer p.Shutdown(ctx)
+//
+//	tracer := p.Tracer("payments")
+func New(ctx context.Context, cfg *ProviderConfig) (*Provider, error) {
+	res, err := createResource(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	tp, components, err := newTracerProvider(ctx, cfg, res)
+	if err != nil {
+		return nil, err
+	}
+	p := &Provider{
+		tp:             tp,
+		cfg:            cfg,
+		tailSampler:    components.tailSampler,
+		dynamicSampler: components.dynamicSampler,
+		exportTrackers: components.exportTrackers,
+	}
+	if cfg.metricsEnabled() {
+		p.mp, err = newMeterProvider(ctx, cfg, res)
+		if err != nil {
+			_ = tp.Shutdown(ctx)
+			return nil, err
+		}
+		if err := registerExportMetrics(p.mp, p.exportTrackers); err != nil {
+			_ = p.shutdown(ctx)
+			return nil, err
+		}
+	}
+	if cfg.logsEnabled() {
+		p.lp, err = newLoggerProvider(ctx, cfg, res)
+		if err != nil {
+			_ = p.shutdown(ctx)
+			return nil, err
+		}
+	}
+	if err := p.runStartupProbe(ctx); err != nil {
+		_ = p.shutdown(ctx)
+		return nil, err
+	}
+	if !cfg.SkipGlobalRegistration {
+		p.SetAsGlobal()
+	}
+	return p, nil
+}
+
+// Tracer returns a named tracer of this provider, regardless of the global provider.
+func (p *Provider) Tracer(name string, opts ...trace.TracerOption) trace.Tracer {
+	return p.tp.Tracer(name, opts...)
+}
+
+// TracerProvider returns the underlying SDK tracer provider, e.g. to pass to
+// instrumentation libraries that accept a trace.TracerProvider.
+func (p *Provider) TracerProvider() *sdktrace.TracerProvider {
+	return p.tp
+}
+
+// Meter returns a named meter of this provider. It returns a no-op meter if metrics
+// are not enabled.
+func (p *Provider) Meter(name string, opts ...metric.MeterOption) metric.Meter {
+	if p.mp == nil {
+		return metricnoop.NewMeterProvider().Meter(name, opts...)
+	}
+	return p.mp.Meter(name, opts...)
+}
+
+// MeterProvider returns the underlying SDK meter provider, or nil if metrics are not
+// enabled.
+func (p *Provider) MeterProvider() *sdkmetric.MeterProvider {
+	return p.mp
+}
+
+// Logger returns a named logger of this provider. It returns a no-op logger if logs
+// are not enabled.
+func (p *Provider) Logger(name string, opts ...log.LoggerOption) log.Logger {
+	if p.lp == nil {
+		return lognoop.NewLoggerProvider().Logger(name, opts...)
+	}
+	return p.lp.Logger(name, opts...)
+}
+
+// LoggerProvider returns the underlying SDK logger provider, or nil if logs are not
+// enabled.
+func (p *Provider) LoggerProvider() *sdklog.LoggerProvider {
+	return p.lp
+}
+
+// StartRuntimeMetrics starts recording Go runtime and process metrics, such as the
+// goroutine count, heap usage, GC pauses, scheduling latency, CPU time and open file
+// descriptors, with the provider's meter provider every interval (15 seconds if zero).
+// Recording stops when the provider is shut down. Calling it again restarts recording
+// with the new interval. It fails if metrics are not enabled.
+//
+// Example:
+//
+//	p, _ := provider.New(ctx, provider.NewProviderConfig("payment-service", "v1.2.3").WithMetrics(0, ""))
+//	if err := p.StartRuntimeMetrics(10 * time.Second); err != nil {
+//	    log.Fatal(err)
+//	}
+func (p *Provider) StartRuntimeMetrics(interval time.Duration) error {
+	if p.mp == nil {
+		return &config.ConfigError{Field: "MetricsExporter", Message: config.ErrRuntimeMetricsDisabled}
+	}
+	rm, err := StartRuntimeMetrics(p.mp, interval)
+	if err != nil {
+		return err
+	}
+	p.runtimeMu.Lock()
+	previous := p.runtime
+	p.runtime = rm
+	p.runtimeMu.Unlock()
+	if previous != nil {
+		return previous.Stop(context.Background())
+	}
+	return nil
+}
+
+// Stats returns a snapshot of the span counters of every export destination, the
+// primary exporter first: the spans queued, exported, dropped because the batch
+// queue was full and failed to export, the current queue depth, and the most recent
+// export error. With metrics enabled, the counters are also reported as the
+// otelkit.exporter.* metrics with an exporter attribute.
+//
+// Example:
+//
+//	for _, s := range p.Stats() {
+//	    if s.SpansDropped > 0 || s.LastError != nil {
+//	        log.Printf("exporter %s: %d dropped, last error %v at %s",
+//	            s.Exporter, s.SpansDropped, s.LastError, s.LastErrorTime)
+//	    }
+//	}
+func (p *Provider) Stats() []ExportStats {
+	stats := make([]ExportStats, len(p.exportTrackers))
+	for i, t := range p.exportTrackers {
+		stats[i] = t.stats()
+	}
+	return stats
+}
+
+// Config returns the configuration the provider was created from.
+func (p *Provider) Config() *ProviderConfig {
+	return p.cfg
+}
+
+// DynamicSampler returns the runtime-adjustable sampler created for the provider. It
+// returns nil if dynamic sampling is not enabled.
+func (p *Provider) DynamicSampler() *DynamicSampler {
+	return p.dynamicSampler
+}
+
+// TailSampler returns the tail sampling processor created for the provider, for
+// reading its Stats. It returns nil if tail sampling is not enabled.
+func (p *Provider) TailSampler() *TailSamplingProcessor {
+	return p.tailSampler
+}
+
+// ForceFlush exports all ended spans, collected metrics and emitted log records that
+// have not been exported yet.
+func (p *Provider) ForceFlush(ctx context.Context) error {
+	err := p.tp.ForceFlush(ctx)
+	if p.mp != nil {
+		err = errors.Join(err, p.mp.ForceFlush(ctx))
+	}
+	if p.lp != nil {
+		err = errors.Join(err, p.lp.ForceFlush(ctx))
+	}
+	return err
+}
+
+// Shutdown flushes and stops the tracer, meter and logger providers. Globals referring
+// to them are replaced with no-op providers. Calling Shutdown more than once is safe.
+func (p *Provider) Shutdown(ctx context.Context) error {
+	unsetGlobal(p.tp)
+	if p.mp != nil {
+		unsetGlobalMeterProvider(p.mp)
+	}
+	if p.lp != nil {
+		unsetGlobalLoggerProvider(p.lp)
+	}
+	return p.shutdown(ctx)
+}
+
+// shutdown stops the providers. Logs are stopped last so that records written while
+// spans and metrics are flushed are still exported.
+func (p *Provider) shutdown(ctx context.Context) error {
+	p.runtimeMu.Lock()
+	rm := p.runtime
+	p.runtimeMu.Unlock()
+	var err error
+	if rm != nil {
+		err = rm.Stop(ctx)
+	}
+	err = errors.Join(err, p.tp.Shutdown(ctx))
+	if p.mp != nil {
+		if mpErr := p.mp.Shutdown(ctx); mpErr != nil && !errors.Is(mpErr, sdkmetric.ErrReaderShutdown) {
+			err = errors.Join(err, mpErr)
+		}
+	}
+	if p.lp != nil {
+		err = errors.Join(err, p.lp.Shutdown(ctx))
+	}
+	return err
+}
+
+// SetAsGlobal installs the provider as the OpenTelemetry global tracer provider and,
+// if enabled, global meter and logger provider, e.g. after creating it with
+// WithoutGlobalRegistration.
+func (p *Provider) SetAsGlobal() {
+	setGlobal(p.tp, p)
+	if p.mp != nil {
+		setGlobalMeterProvider(p.mp)
+	}
+	if p.lp != nil {
+		setGlobalLoggerProvider(p.lp)
+	}
+}
+
+// IsGlobal reports whether the provider is the OpenTelemetry global tracer provider.
+func (p *Provider) IsGlobal() bool {
+	return otel.GetTracerProvider() == p.tp
+}
@@ -0,0 +1,119 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+
+	"github.com/kernelshard/otelkit/internal/config"
+)
+
+func newTestHandleConfig(buf *bytes.Buffer) *ProviderConfig {
+	return NewProviderConfig("handle-service", "1.0.0").
+		WithConsoleExporter(buf, true).
+		WithSampling(config.SamplingAlwaysOn, 1)
+}
+
+func TestNew_ReplacesGlobal(t *testing.T) {
+	ctx := context.Background()
+	t.Cleanup(ResetGlobal)
+
+	first, err := New(ctx, newTestHandleConfig(&bytes.Buffer{}))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	defer first.Shutdown(ctx)
+	if !first.IsGlobal() {
+		t.Fatal("Expected the first provider to become global")
+	}
+
+	second, err := New(ctx, newTestHandleConfig(&bytes.Buffer{}))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	defer second.Shutdown(ctx)
+	if !second.IsGlobal() || first.IsGlobal() {
+		t.Error("Expected the second provider to replace the first as global")
+	}
+
+	first.SetAsGlobal()
+	if !first.IsGlobal() {
+		t.Error("Expected SetAsGlobal to install the first provider again")
+	}
+}
+
+func TestNew_WithoutGlobalRegistration(t *testing.T) {
+	ctx := context.Background()
+	t.Cleanup(ResetGlobal)
+	ResetGlobal()
+	before := otel.GetTracerProvider()
+
+	var buf bytes.Buffer
+	p, err := New(ctx, newTestHandleConfig(&buf).WithoutGlobalRegistration())
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	if p.IsGlobal() || otel.GetTracerProvider() != before {
+		t.Fatal("Expected the global provider to be unchanged")
+	}
+
+	_, span := p.Tracer("handle-test").Start(ctx, "direct-span")
+	span.End()
+	if err := p.ForceFlush(ctx); err != nil {
+		t.Fatalf("ForceFlush failed: %v", err)
+	}
+	if !strings.Contains(buf.String(), "direct-span") {
+		t.Errorf("Expected the span to be exported after ForceFlush, got %q", buf.String())
+	}
+	if err := p.Shutdown(ctx); err != nil {
+		t.Errorf("Shutdown failed: %v", err)
+	}
+}
+
+func TestProvider_ShutdownResetsGlobal(t *testing.T) {
+	ctx := context.Background()
+	t.Cleanup(ResetGlobal)
+
+	p, err := New(ctx, newTestHandleConfig(&bytes.Buffer{}))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	if err := p.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown failed: %v", err)
+	}
+	if p.IsGlobal() {
+		t.Error("Expected a shut down provider to be removed as global")
+	}
+	_, span := otel.Tracer("after-shutdown").Start(ctx, "noop")
+	if span.IsRecording() {
+		t.Error("Expected the global provider to be a no-op after shutdown")
+	}
+	if err := p.Shutdown(ctx); err != nil {
+		t.Errorf("Expected a second Shutdown to succeed, got %v", err)
+	}
+}
+
+func TestResetGlobal(t *testing.T) {
+	ctx := context.Background()
+	tp, err := NewProvider(ctx, newTestHandleConfig(&bytes.Buffer{}))
+	if err != nil {
+		t.Fatalf("NewProvider failed: %v", err)
+	}
+	defer tp.Shutdown(ctx)
+	if otel.GetTracerProvider() != tp {
+		t.Fatal("Expected NewProvider to install the provider as global")
+	}
+
+	ResetGlobal()
+	if otel.GetTracerProvider() == tp {
+		t.Error("Expected ResetGlobal to remove the provider")
+	}
+	_, span := tp.Tracer("still-running").Start(ctx, "span")
+	if !span.IsRecording() {
+		t.Error("Expected ResetGlobal not to shut down the provider")
+	}
+	span.End()
+}
@@ -0,0 +1,270 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel"
+
+	"github.com/kernelshard/otelkit/internal/config"
+)
+
+// HealthState is the state of span export reported by a HealthChecker.
+type HealthState string
+
+const (
+	// HealthHealthy means the last export succeeded, or nothing has been exported yet,
+	// and no spans were dropped recently.
+	HealthHealthy HealthState = "healthy"
+	// HealthDegraded means exports have been failing for less than the failing
+	// threshold, or spans were dropped because the queue was full within it.
+	HealthDegraded HealthState = "degraded"
+	// HealthFailing means every export has failed for at least the failing threshold.
+	HealthFailing HealthState = "failing"
+)
+
+// severity orders the states from healthy to failing.
+func (s HealthState) severity() int {
+	switch s {
+	case HealthFailing:
+		return 2
+	case HealthDegraded:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// ExporterHealth is the health of one export destination.
+type ExporterHealth struct {
+	Exporter            string      `json:"exporter"`
+	State               HealthState `json:"state"`
+	ConsecutiveFailures uint64      `json:"consecutive_failures"`
+	FailingSince        time.Time   `json:"failing_since,omitzero"`
+	LastSuccess         time.Time   `json:"last_success,omitzero"`
+	LastError           string      `json:"last_error,omitempty"`
+}
+
+// HealthStatus is the health of span export: the worst state of all export
+// destinations, and the health of each.
+type HealthStatus struct {
+	State     HealthState      `json:"state"`
+	Exporters []ExporterHealth `json:"exporters"`
+	Message   string           `json:"message,omitempty"`
+}
+
+// HealthChecker reports the health of a provider's span export from the outcome of
+// recent exports. It is created by Provider.HealthChecker.
+type HealthChecker struct {
+	trackers     []*exportTracker
+	failingAfter time.Duration
+	now          func() time.Time
+}
+
+// HealthChecker returns a health checker for the provider's export destinations. An
+// export destination is failing once its exports have failed without a success for
+// Config.HealthFailingAfter (2 minutes by default, OTEL_EXPORTER_HEALTH_FAILING_AFTER).
+//
+// Example:
+//
+//	http.Handle("/healthz/tracing", p.HealthChecker().Handler())
+func (p *Provider) HealthChecker() *HealthChecker {
+	failingAfter := p.cfg.Config.HealthFailingAfter
+	if failingAfter == 0 {
+		failingAfter = config.DefaultHealthFailingAfter
+	}
+	return &HealthChecker{trackers: p.exportTrackers, failingAfter: failingAfter, now: time.Now}
+}
+
+// Check returns the current health of span export.
+func (h *HealthChecker) Check() HealthStatus {
+	now := h.now()
+	status := HealthStatus{State: HealthHealthy, Exporters: make([]ExporterHealth, 0, len(h.trackers))}
+	for _, t := range h.trackers {
+		eh := t.health(now, h.failingAfter)
+		if eh.State.severity() > status.State.severity() {
+			status.State = eh.State
+		}
+		status.Exporters = append(status.Exporters, eh)
+	}
+	return status
+}
+
+// health returns the health of the tracker's export destination at now.
+func (t *exportTracker) health(now time.Time, failingAfter time.Duration) ExporterHealth {
+	t.mu.Lock()
+	eh := ExporterHealth{
+		Exporter:            t.name,
+		State:               HealthHealthy,
+		ConsecutiveFailures: t.failureStreak,
+		FailingSince:        t.failingSince,
+		LastSuccess:         t.lastSuccess,
+	}
+	if t.failureStreak > 0 && t.lastError != nil {
+		eh.LastError = exportErrorMessage(t.lastError)
+	}
+	t.mu.Unlock()
+
+	switch {
+	case eh.ConsecutiveFailures > 0 && now.Sub(eh.FailingSince) >= failingAfter:
+		eh.State = HealthFailing
+	case eh.ConsecutiveFailures > 0:
+		eh.State = HealthDegraded
+	default:
+		if lastDrop := t.lastDrop.Load(); lastDrop != 0 && now.Sub(time.Unix(0, lastDrop)) < failingAfter {
+			eh.State = HealthDegraded
+		}
+	}
+	return eh
+}
+
+// Handler returns an http.Handler for readiness endpoints such as /healthz/tracing.
+// GET and HEAD respond with the HealthStatus as JSON, with 200 OK while healthy or
+// degraded and 503 Service Unavailable when failing.
+func (h *HealthChecker) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		serveHealth(w, r, h.Check())
+	})
+}
+
+// HealthHandler returns an http.Handler like HealthChecker.Handler for the Provider
+// installed as the global tracer provider, e.g. by SetupTracing or otelkit.Setup, at
+// the time of each request. Without one it responds 503 Service Unavailable.
+//
+// Example:
+//
+//	shutdown, err := tracer.SetupTracing(ctx, "payment-service")
+//	// ...
+//	http.Handle("/healthz/tracing", provider.HealthHandler())
+func HealthHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		globalMu.Lock()
+		p := globalProvider
+		globalMu.Unlock()
+		if p == nil || !p.IsGlobal() {
+			serveHealth(w, r, HealthStatus{
+				State:     HealthFailing,
+				Exporters: []ExporterHealth{},
+				Message:   "no otelkit provider is installed as the global tracer provider",
+			})
+			return
+		}
+		serveHealth(w, r, p.HealthChecker().Check())
+	})
+}
+
+func serveHealth(w http.ResponseWriter, r *http.Request, status HealthStatus) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		return
+	}
+	code := http.StatusOK
+	if status.State == HealthFailing {
+		code = http.StatusServiceUnavailable
+	}
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	if r.Method == http.MethodGet {
+		_ = json.NewEncoder(w).Encode(status)
+	}
+}
+
+// Probe checks that the OTLP endpoints of the primary and additional exporters
+// accept TCP connections within Config.StartupProbeTimeout (5 seconds by default)
+// each. Console, file and Zipkin exporters are not probed. A successful probe does
+// not prove that the collector accepts spans, only that it is reachable.
+//
+// New probes automatically with WithStartupProbe or OTEL_EXPORTER_STARTUP_PROBE.
+func (p *Provider) Probe(ctx context.Context) error {
+	timeout := p.cfg.Config.StartupProbeTimeout
+	if timeout == 0 {
+		timeout = config.DefaultStartupProbeTimeout
+	}
+	var err error
+	if p.cfg.Config.UsesOTLPEndpoint() {
+		err = errors.Join(err, probeEndpoint(ctx, primaryExporterName, p.cfg.Config, timeout))
+	}
+	for _, e := range p.cfg.Config.Exporters {
+		cfg := p.cfg.Config.ForExporter(e)
+		if cfg.UsesOTLPEndpoint() {
+			err = errors.Join(err, probeEndpoint(ctx, e.Name, cfg, timeout))
+		}
+	}
+	return err
+}
+
+// exportErrorMessage returns the message of an export error for health reports. The
+// URL of a failed request is left out, as endpoints may carry credentials.
+func exportErrorMessage(err error) string {
+	msg := err.Error()
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		msg = strings.Replace(msg, urlErr.Error(), urlErr.Err.Error(), 1)
+	}
+	return msg
+}
+
+// probeEndpoint connects to the address the OTLP exporter of cfg sends spans to.
+func probeEndpoint(ctx context.Context, name string, cfg *config.Config, timeout time.Duration) error {
+	addr, err := exporterAddress(cfg)
+	if err != nil {
+		return fmt.Errorf("exporter %s: %w", name, err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return fmt.Errorf("exporter %s: collector unreachable: %w", name, err)
+	}
+	return conn.Close()
+}
+
+// exporterAddress returns the host:port the OTLP exporter of cfg connects to. The
+// exporters receive the host of cfg.TracesEndpoint; without a port, the OTLP/HTTP
+// exporter uses the port of its scheme and the gRPC exporter the default port of
+// the gRPC name resolver, 443, whatever the scheme of a URL endpoint.
+func exporterAddress(cfg *config.Config) (string, error) {
+	endpoint, err := cfg.TracesEndpoint()
+	if err != nil {
+		return "", err
+	}
+	if _, _, err := net.SplitHostPort(endpoint.Host); err == nil {
+		return endpoint.Host, nil
+	}
+	port := "443"
+	if cfg.OTLPExporterProtocol == config.ProtocolHTTP && endpoint.Insecure(cfg.OTLPExporterInsecure) {
+		port = "80"
+	}
+	return net.JoinHostPort(strings.Trim(endpoint.Host, "[]"), port), nil
+}
+
+// runStartupProbe probes the collector as configured by cfg.StartupProbe. In warn mode
+// failures are reported to the OpenTelemetry error handler; in fail mode they are
+// returned.
+func (p *Provider) runStartupProbe(ctx context.Context) error {
+	switch p.cfg.Config.StartupProbe {
+	case config.StartupProbeWarn, config.StartupProbeFail:
+	default:
+		return nil
+	}
+	err := p.Probe(ctx)
+	if err == nil {
+		return nil
+	}
+	if p.cfg.Config.StartupProbe == config.StartupProbeFail {
+		return &InitializationError{Component: "startup probe", Cause: err}
+	}
+	otel.Handle(fmt.Errorf("otelkit: startup probe: %w", err))
+	return nil
+}
@@ -0,0 +1,357 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+
+	"github.com/kernelshard/otelkit/internal/config"
+)
+
+// JaegerRemoteSamplerFactory creates samplers that poll a Jaeger-compatible sampling
+// endpoint for the service's strategy (jaeger_remote). The strategy applies to every
+// span regardless of its parent's decision.
+//
+// Until a strategy has been fetched, spans are sampled at cfg.SamplingRatio. When the
+// endpoint becomes unreachable the last fetched strategy stays in effect for a few
+// polls and then gives way to cfg.SamplingRatio again, so that a stale strategy does
+// not apply indefinitely. Polling stops when the tracer provider shuts down.
+type JaegerRemoteSamplerFactory struct{}
+
+func (f *JaegerRemoteSamplerFactory) CreateSampler(cfg *config.Config) sdktrace.Sampler {
+	return startJaegerRemoteSampler(cfg, false)
+}
+
+// ParentBasedJaegerRemoteSamplerFactory creates Jaeger remote samplers for root spans;
+// child spans follow their parent (parentbased_jaeger_remote).
+type ParentBasedJaegerRemoteSamplerFactory struct{}
+
+func (f *ParentBasedJaegerRemoteSamplerFactory) CreateSampler(cfg *config.Config) sdktrace.Sampler {
+	return startJaegerRemoteSampler(cfg, true)
+}
+
+// stoppableSampler is a sampler running background work that must be stopped when the
+// tracer provider shuts down or the sampler is replaced.
+type stoppableSampler interface {
+	sdktrace.Sampler
+	stop()
+}
+
+// samplerStopProcessor stops a sampler's background work on provider shutdown. The
+// SDK has no shutdown hook for samplers, but it shuts down every span processor.
+type samplerStopProcessor struct {
+	sampler stoppableSampler
+}
+
+func (p samplerStopProcessor) OnStart(context.Context, sdktrace.ReadWriteSpan) {}
+func (p samplerStopProcessor) OnEnd(sdktrace.ReadOnlySpan)                     {}
+func (p samplerStopProcessor) ForceFlush(context.Context) error                { return nil }
+
+func (p samplerStopProcessor) Shutdown(context.Context) error {
+	p.sampler.stop()
+	return nil
+}
+
+// jaegerRemoteSampler delegates to the sampler built from the most recently fetched
+// strategy, which a background goroutine refreshes every interval.
+type jaegerRemoteSampler struct {
+	url      string
+	interval time.Duration
+	client   *http.Client
+	sampler  sdktrace.Sampler // root, or root wrapped in ParentBased
+
+	root     jaegerRemoteRoot
+	strategy atomic.Pointer[jaegerStrategySampler]
+	fallback *jaegerStrategySampler // Configured ratio, used without a current strategy
+
+	failedPolls int // Consecutive failed polls; used only by the polling goroutine
+
+	cancel   context.CancelFunc // nil until polling starts
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+// jaegerRemoteRoot samples with the current strategy of its jaegerRemoteSampler.
+type jaegerRemoteRoot struct {
+	s *jaegerRemoteSampler
+}
+
+func (r jaegerRemoteRoot) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	return r.s.strategy.Load().sampler.ShouldSample(p)
+}
+
+func (r jaegerRemoteRoot) Description() string {
+	return "JaegerRemoteSampler{" + r.s.strategy.Load().sampler.Description() + "}"
+}
+
+// jaegerStrategySampler is the sampler for one fetched strategy. The strategy is kept
+// to detect unchanged responses, which must not reset rate limiter state.
+type jaegerStrategySampler struct {
+	strategy *jaegerSamplingStrategy
+	sampler  sdktrace.Sampler
+}
+
+// startJaegerRemoteSampler creates a Jaeger remote sampler and starts polling.
+func startJaegerRemoteSampler(cfg *config.Config, parentBased bool) *jaegerRemoteSampler {
+	s := newJaegerRemoteSampler(cfg, parentBased)
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+	s.done = make(chan struct{})
+	go s.run(ctx)
+	return s
+}
+
+// newJaegerRemoteSampler creates a Jaeger remote sampler using cfg.SamplingRatio
+// until the first poll succeeds. It does not start polling.
+func newJaegerRemoteSampler(cfg *config.Config, parentBased bool) *jaegerRemoteSampler {
+	interval := cfg.JaegerRemoteSamplingInterval
+	if interval <= 0 {
+		interval = config.DefaultJaegerRemoteSamplingInterval
+	}
+	s := &jaegerRemoteSampler{
+		url:      jaegerStrategyURL(cfg.JaegerRemoteSamplingEndpoint, cfg.ServiceName),
+		interval: interval,
+		client:   &http.Client{Timeout: min(interval, config.DefaultJaegerRemoteSamplingTimeout)},
+	}
+	s.root = jaegerRemoteRoot{s: s}
+	s.sampler = s.root
+	if parentBased {
+		s.sampler = sdktrace.ParentBased(s.root)
+	}
+	s.fallback = &jaegerStrategySampler{sampler: sdktrace.TraceIDRatioBased(cfg.SamplingRatio)}
+	s.strategy.Store(s.fallback)
+	return s
+}
+
+// jaegerStrategyURL adds the service query parameter to the strategy endpoint.
+func jaegerStrategyURL(endpoint, serviceName string) string {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		// Rejected by Config.Validate; polling reports the request error.
+		return endpoint
+	}
+	q := u.Query()
+	q.Set("service", serviceName)
+	u.RawQuery = q.Encode()
+	return u.String()
+}
+
+// ShouldSample delegates to the sampler of the current strategy.
+func (s *jaegerRemoteSampler) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	return s.sampler.ShouldSample(p)
+}
+
+// Description returns the description of the current strategy's sampler.
+func (s *jaegerRemoteSampler) Description() string {
+	return s.sampler.Description()
+}
+
+// run polls the endpoint immediately and then every interval until ctx is cancelled.
+func (s *jaegerRemoteSampler) run(ctx context.Context) {
+	defer close(s.done)
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		if err := s.poll(ctx); err != nil && ctx.Err() == nil {
+			otel.Handle(fmt.Errorf("otelkit: fetch jaeger remote sampling strategy: %w", err))
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// stop stops polling and waits for an in-flight request to finish.
+func (s *jaegerRemoteSampler) stop() {
+	s.stopOnce.Do(func() {
+		if s.cancel != nil {
+			s.cancel()
+			<-s.done
+		}
+	})
+}
+
+// poll fetches the strategy and swaps in a new sampler if it changed. On error the
+// current sampler stays in effect until DefaultJaegerRemoteMaxFailedPolls polls in a
+// row have failed, after which the configured ratio applies until a fetch succeeds.
+func (s *jaegerRemoteSampler) poll(ctx context.Context) error {
+	strategy, err := s.fetch(ctx)
+	if err != nil {
+		s.failedPolls++
+		if s.failedPolls >= config.DefaultJaegerRemoteMaxFailedPolls && s.strategy.Load() != s.fallback {
+			s.strategy.Store(s.fallback)
+			return fmt.Errorf("%w; using the configured sampling ratio after %d failed polls", err, s.failedPolls)
+		}
+		return err
+	}
+	s.failedPolls = 0
+	if current := s.strategy.Load().strategy; current != nil && reflect.DeepEqual(current, strategy) {
+		return nil
+	}
+	s.strategy.Store(&jaegerStrategySampler{strategy: strategy, sampler: strategy.sampler()})
+	return nil
+}
+
+func (s *jaegerRemoteSampler) fetch(ctx context.Context) (*jaegerSamplingStrategy, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		// The URL is left out of the error, as endpoints may carry credentials.
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return nil, fmt.Errorf("unexpected response status %s", resp.Status)
+	}
+
+	var strategy jaegerSamplingStrategy
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&strategy); err != nil {
+		return nil, fmt.Errorf("decode strategy: %w", err)
+	}
+	return &strategy, nil
+}
+
+// jaegerSamplingStrategy is the JSON response of the Jaeger sampling endpoint
+// (SamplingStrategyResponse), as served by the Jaeger agent and collector.
+type jaegerSamplingStrategy struct {
+	StrategyType          jaegerStrategyType            `json:"strategyType"`
+	ProbabilisticSampling *jaegerProbabilisticStrategy  `json:"probabilisticSampling"`
+	RateLimitingSampling  *jaegerRateLimitingStrategy   `json:"rateLimitingSampling"`
+	OperationSampling     *jaegerPerOperationStrategies `json:"operationSampling"`
+}
+
+type jaegerProbabilisticStrategy struct {
+	SamplingRate float64 `json:"samplingRate"`
+}
+
+type jaegerRateLimitingStrategy struct {
+	MaxTracesPerSecond float64 `json:"maxTracesPerSecond"`
+}
+
+type jaegerPerOperationStrategies struct {
+	DefaultSamplingProbability       float64                   `json:"defaultSamplingProbability"`
+	DefaultLowerBoundTracesPerSecond float64                   `json:"defaultLowerBoundTracesPerSecond"`
+	PerOperationStrategies           []jaegerOperationStrategy `json:"perOperationStrategies"`
+}
+
+type jaegerOperationStrategy struct {
+	Operation             string                       `json:"operation"`
+	ProbabilisticSampling *jaegerProbabilisticStrategy `json:"probabilisticSampling"`
+}
+
+// jaegerStrategyType is "PROBABILISTIC" or "RATE_LIMITING". Older agents encode it
+// as the enum number, 0 or 1.
+type jaegerStrategyType string
+
+const jaegerRateLimiting jaegerStrategyType = "RATE_LIMITING"
+
+func (t *jaegerStrategyType) UnmarshalJSON(data []byte) error {
+	switch strings.Trim(string(data), `"`) {
+	case "1", string(jaegerRateLimiting):
+		*t = jaegerRateLimiting
+	default:
+		*t = "PROBABILISTIC"
+	}
+	return nil
+}
+
+// sampler builds the sampler for the strategy. Per-operation strategies take
+// precedence, as in the Jaeger clients.
+func (st *jaegerSamplingStrategy) sampler() sdktrace.Sampler {
+	switch {
+	case st.OperationSampling != nil:
+		return newJaegerPerOperationSampler(st.OperationSampling)
+	case st.StrategyType == jaegerRateLimiting && st.RateLimitingSampling != nil:
+		return newRateLimitingSampler(st.RateLimitingSampling.MaxTracesPerSecond)
+	case st.ProbabilisticSampling != nil:
+		return sdktrace.TraceIDRatioBased(st.ProbabilisticSampling.SamplingRate)
+	default:
+		return sdktrace.NeverSample()
+	}
+}
+
+// jaegerPerOperationSampler samples each span name at its operation's probability.
+// Every operation is guaranteed the lower bound of traces per second even if the
+// probability would drop them. Operations without a strategy share the defaults.
+type jaegerPerOperationSampler struct {
+	operations map[string]sdktrace.Sampler
+	fallback   sdktrace.Sampler
+}
+
+func newJaegerPerOperationSampler(st *jaegerPerOperationStrategies) *jaegerPerOperationSampler {
+	s := &jaegerPerOperationSampler{
+		operations: make(map[string]sdktrace.Sampler, len(st.PerOperationStrategies)),
+		fallback:   newGuaranteedThroughputSampler(st.DefaultSamplingProbability, st.DefaultLowerBoundTracesPerSecond),
+	}
+	for _, op := range st.PerOperationStrategies {
+		ratio := st.DefaultSamplingProbability
+		if op.ProbabilisticSampling != nil {
+			ratio = op.ProbabilisticSampling.SamplingRate
+		}
+		s.operations[op.Operation] = newGuaranteedThroughputSampler(ratio, st.DefaultLowerBoundTracesPerSecond)
+	}
+	return s
+}
+
+// ShouldSample delegates to the sampler of the span's operation.
+func (s *jaegerPerOperationSampler) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	if sampler, ok := s.operations[p.Name]; ok {
+		return sampler.ShouldSample(p)
+	}
+	return s.fallback.ShouldSample(p)
+}
+
+// Description returns the sampler name and number of operations.
+func (s *jaegerPerOperationSampler) Description() string {
+	return fmt.Sprintf("PerOperationSampler{operations:%d,default:%s}", len(s.operations), s.fallback.Description())
+}
+
+// guaranteedThroughputSampler samples by trace ID ratio and additionally samples up
+// to lowerBound traces per second that the ratio dropped.
+type guaranteedThroughputSampler struct {
+	ratio      sdktrace.Sampler
+	lowerBound *rateLimitingSampler
+}
+
+func newGuaranteedThroughputSampler(ratio, lowerBound float64) *guaranteedThroughputSampler {
+	return &guaranteedThroughputSampler{
+		ratio:      sdktrace.TraceIDRatioBased(ratio),
+		lowerBound: newRateLimitingSampler(lowerBound),
+	}
+}
+
+// ShouldSample samples if the ratio or the lower bound allows it.
+func (s *guaranteedThroughputSampler) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	result := s.ratio.ShouldSample(p)
+	if result.Decision == sdktrace.RecordAndSample || s.lowerBound.rate == 0 {
+		return result
+	}
+	return s.lowerBound.ShouldSample(p)
+}
+
+// Description returns the sampler's ratio and lower bound.
+func (s *guaranteedThroughputSampler) Description() string {
+	return fmt.Sprintf("GuaranteedThroughputSampler{%s,lowerBound:%g}", s.ratio.Description(), s.lowerBound.rate)
+}
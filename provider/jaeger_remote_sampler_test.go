@@ -0,0 +1,222 @@
+package provider
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+
+	"github.com/kernelshard/otelkit/internal/config"
+)
+
+// strategyServer serves the strategy returned by body for the jaeger-service service.
+func strategyServer(t *testing.T, body func() string) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if got := r.URL.Query().Get("service"); got != "jaeger-service" {
+			http.Error(w, "unknown service "+got, http.StatusBadRequest)
+			return
+		}
+		_, _ = io.WriteString(w, body())
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &requests
+}
+
+func newTestJaegerRemoteSampler(t *testing.T, endpoint string, ratio float64) (*jaegerRemoteSampler, *sdktrace.TracerProvider) {
+	t.Helper()
+	cfg := config.NewConfig("jaeger-service", "1.0.0").WithSampling(config.SamplingJaegerRemote, ratio)
+	cfg.JaegerRemoteSamplingEndpoint = endpoint
+	s := newJaegerRemoteSampler(cfg, false)
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSampler(s))
+	t.Cleanup(func() { _ = tp.Shutdown(context.Background()) })
+	return s, tp
+}
+
+func countSampled(tp *sdktrace.TracerProvider, name string, n int) int {
+	sampled := 0
+	for i := 0; i < n; i++ {
+		_, span := tp.Tracer("jaeger-test").Start(context.Background(), name)
+		if span.SpanContext().IsSampled() {
+			sampled++
+		}
+		span.End()
+	}
+	return sampled
+}
+
+func TestJaegerRemoteSampler_Strategies(t *testing.T) {
+	tests := []struct {
+		name     string
+		strategy string
+		span     string
+		spans    int
+		min, max int
+	}{
+		{"probabilistic", `{"strategyType": "PROBABILISTIC", "probabilisticSampling": {"samplingRate": 1}}`, "op", 10, 10, 10},
+		{"probabilistic zero", `{"strategyType": 0, "probabilisticSampling": {"samplingRate": 0}}`, "op", 10, 0, 0},
+		{"rate limiting", `{"strategyType": "RATE_LIMITING", "rateLimitingSampling": {"maxTracesPerSecond": 2}}`, "op", 10, 2, 3},
+		{"rate limiting enum", `{"strategyType": 1, "rateLimitingSampling": {"maxTracesPerSecond": 2}}`, "op", 10, 2, 3},
+		{"per-operation listed", perOperationStrategy, "checkout", 10, 10, 10},
+		{"per-operation default with lower bound", perOperationStrategy, "health", 10, 1, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, _ := strategyServer(t, func() string { return tt.strategy })
+			s, tp := newTestJaegerRemoteSampler(t, srv.URL, 0.5)
+			if err := s.poll(context.Background()); err != nil {
+				t.Fatalf("poll failed: %v", err)
+			}
+			if got := countSampled(tp, tt.span, tt.spans); got < tt.min || got > tt.max {
+				t.Errorf("Sampled %d of %d spans, want %d-%d (%s)", got, tt.spans, tt.min, tt.max, s.Description())
+			}
+		})
+	}
+}
+
+const perOperationStrategy = `{
+  "strategyType": "PROBABILISTIC",
+  "operationSampling": {
+    "defaultSamplingProbability": 0,
+    "defaultLowerBoundTracesPerSecond": 1,
+    "perOperationStrategies": [
+      {"operation": "checkout", "probabilisticSampling": {"samplingRate": 1}}
+    ]
+  }
+}`
+
+func TestJaegerRemoteSampler_Fallback(t *testing.T) {
+	var healthy atomic.Bool
+	srv, _ := strategyServer(t, func() string {
+		return `{"strategyType": "PROBABILISTIC", "probabilisticSampling": {"samplingRate": 0}}`
+	})
+	down := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !healthy.Load() {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		srv.Config.Handler.ServeHTTP(w, r)
+	}))
+	defer down.Close()
+
+	s, tp := newTestJaegerRemoteSampler(t, down.URL, 1)
+	if err := s.poll(context.Background()); err == nil {
+		t.Fatal("Expected poll of an unavailable endpoint to fail")
+	}
+	if got := countSampled(tp, "op", 5); got != 5 {
+		t.Errorf("Expected the configured ratio before the first strategy, sampled %d of 5", got)
+	}
+
+	healthy.Store(true)
+	if err := s.poll(context.Background()); err != nil {
+		t.Fatalf("poll failed: %v", err)
+	}
+	healthy.Store(false)
+	if err := s.poll(context.Background()); err == nil {
+		t.Fatal("Expected poll of an unavailable endpoint to fail")
+	}
+	if got := countSampled(tp, "op", 5); got != 0 {
+		t.Errorf("Expected the last fetched strategy to stay in effect, sampled %d of 5", got)
+	}
+}
+
+func TestJaegerRemoteSampler_UnreachableAfterFetch(t *testing.T) {
+	var healthy atomic.Bool
+	healthy.Store(true)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !healthy.Load() {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = io.WriteString(w, `{"strategyType": "PROBABILISTIC", "probabilisticSampling": {"samplingRate": 0}}`)
+	}))
+	defer srv.Close()
+
+	s, tp := newTestJaegerRemoteSampler(t, srv.URL, 1)
+	if err := s.poll(context.Background()); err != nil {
+		t.Fatalf("poll failed: %v", err)
+	}
+
+	healthy.Store(false)
+	for i := 1; i <= config.DefaultJaegerRemoteMaxFailedPolls; i++ {
+		if err := s.poll(context.Background()); err == nil {
+			t.Fatal("Expected poll of an unavailable endpoint to fail")
+		}
+		want := 0
+		if i == config.DefaultJaegerRemoteMaxFailedPolls {
+			want = 5
+		}
+		if got := countSampled(tp, "op", 5); got != want {
+			t.Errorf("After %d failed polls sampled %d of 5, want %d", i, got, want)
+		}
+	}
+
+	healthy.Store(true)
+	if err := s.poll(context.Background()); err != nil {
+		t.Fatalf("poll failed: %v", err)
+	}
+	if got := countSampled(tp, "op", 5); got != 0 {
+		t.Errorf("Expected the fetched strategy to apply again after recovery, sampled %d of 5", got)
+	}
+}
+
+func TestJaegerRemoteSampler_Unreachable(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	srv.Close()
+
+	s, tp := newTestJaegerRemoteSampler(t, srv.URL, 0)
+	if err := s.poll(context.Background()); err == nil {
+		t.Fatal("Expected poll of a closed server to fail")
+	}
+	if got := countSampled(tp, "op", 5); got != 0 {
+		t.Errorf("Expected the configured ratio, sampled %d of 5", got)
+	}
+}
+
+func TestNewProvider_JaegerRemoteSampling(t *testing.T) {
+	ctx := context.Background()
+	srv, requests := strategyServer(t, func() string {
+		return `{"strategyType": "PROBABILISTIC", "probabilisticSampling": {"samplingRate": 1}}`
+	})
+	pc := NewProviderConfig("jaeger-service", "1.0.0").
+		WithConsoleExporter(io.Discard, true).
+		WithJaegerRemoteSampling(srv.URL, 10*time.Millisecond, 0)
+
+	tp, err := newProvider(ctx, pc)
+	if err != nil {
+		t.Fatalf("newProvider failed: %v", err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for countSampled(tp, "op", 1) != 1 {
+		if time.Now().After(deadline) {
+			t.Fatal("Expected the polled strategy to be applied")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	if err := tp.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown failed: %v", err)
+	}
+	polled := requests.Load()
+	time.Sleep(50 * time.Millisecond)
+	if requests.Load() != polled {
+		t.Error("Expected polling to stop when the provider shuts down")
+	}
+}
+
+func TestNewProvider_JaegerRemoteSamplingInvalidEndpoint(t *testing.T) {
+	pc := NewProviderConfig("jaeger-service", "1.0.0").
+		WithConsoleExporter(io.Discard, true).
+		WithJaegerRemoteSampling("localhost:5778", time.Minute, 0)
+	if _, err := newProvider(context.Background(), pc); err == nil {
+		t.Error("Expected an endpoint without scheme to fail provider creation")
+	}
+}
@@ -0,0 +1,128 @@
+package provider
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploggrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploghttp"
+	"go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/log/global"
+	lognoop "go.opentelemetry.io/otel/log/noop"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	sdkresource "go.opentelemetry.io/otel/sdk/resource"
+	"google.golang.org/grpc/credentials"
+
+	"github.com/kernelshard/otelkit/internal/config"
+)
+
+// logsEnabled reports whether a logger provider is created for cfg: logs are exported
+// over OTLP or additional processors were added with WithLogProcessor.
+func (pc *ProviderConfig) logsEnabled() bool {
+	return pc.Config.LogsEnabled() || len(pc.LogProcessors) > 0
+}
+
+// newLoggerProvider creates a logger provider with a batch processor for the OTLP
+// exporter, if enabled, and the additional processors of cfg. Records emitted with a
+// context carrying a span get its trace ID, span ID and trace flags, so logs are
+// correlated with the trace that was active when they were written.
+func newLoggerProvider(ctx context.Context, cfg *ProviderConfig, res *sdkresource.Resource) (*sdklog.LoggerProvider, error) {
+	opts := []sdklog.LoggerProviderOption{sdklog.WithResource(res)}
+	if cfg.Config.LogsEnabled() {
+		exporter, err := createLogExporter(ctx, cfg.Config)
+		if err != nil {
+			return nil, &InitializationError{Component: "log exporter", Cause: err}
+		}
+		opts = append(opts, sdklog.WithProcessor(sdklog.NewBatchProcessor(exporter)))
+	}
+	for _, processor := range cfg.LogProcessors {
+		opts = append(opts, sdklog.WithProcessor(processor))
+	}
+	return sdklog.NewLoggerProvider(opts...), nil
+}
+
+// createLogExporter creates an OTLP log exporter: gRPC when the trace protocol is
+// gRPC, HTTP otherwise.
+func createLogExporter(ctx context.Context, cfg *config.Config) (sdklog.Exporter, error) {
+	endpoint, err := cfg.SignalEndpoint("LogsEndpoint", cfg.LogsEndpoint, config.LogsSignalPath)
+	if err != nil {
+		return nil, err
+	}
+	tlsCfg, err := createTLSConfig(cfg, endpoint)
+	if err != nil {
+		return nil, err
+	}
+	policy := retryPolicy(cfg)
+
+	if cfg.OTLPExporterProtocol == config.ProtocolGRPC {
+		opts := []otlploggrpc.Option{
+			otlploggrpc.WithEndpoint(endpoint.Host),
+			otlploggrpc.WithRetry(otlploggrpc.RetryConfig{
+				Enabled:         policy.enabled,
+				InitialInterval: policy.initialInterval,
+				MaxInterval:     policy.maxInterval,
+				MaxElapsedTime:  policy.maxElapsedTime,
+			}),
+		}
+		if endpoint.Insecure(cfg.OTLPExporterInsecure) {
+			opts = append(opts, otlploggrpc.WithInsecure())
+		}
+		if tlsCfg != nil {
+			opts = append(opts, otlploggrpc.WithTLSCredentials(credentials.NewTLS(tlsCfg)))
+		}
+		if len(cfg.OTLPExporterHeaders) > 0 {
+			opts = append(opts, otlploggrpc.WithHeaders(cfg.OTLPExporterHeaders))
+		}
+		if cfg.OTLPCompression == config.CompressionGzip {
+			opts = append(opts, otlploggrpc.WithCompressor(config.CompressionGzip))
+		}
+		if cfg.OTLPTimeout > 0 {
+			opts = append(opts, otlploggrpc.WithTimeout(cfg.OTLPTimeout))
+		}
+		return otlploggrpc.New(ctx, opts...)
+	}
+
+	opts := []otlploghttp.Option{
+		otlploghttp.WithEndpoint(endpoint.Host),
+		otlploghttp.WithRetry(otlploghttp.RetryConfig{
+			Enabled:         policy.enabled,
+			InitialInterval: policy.initialInterval,
+			MaxInterval:     policy.maxInterval,
+			MaxElapsedTime:  policy.maxElapsedTime,
+		}),
+	}
+	if endpoint.Path != "" {
+		opts = append(opts, otlploghttp.WithURLPath(endpoint.Path))
+	}
+	if endpoint.Insecure(cfg.OTLPExporterInsecure) {
+		opts = append(opts, otlploghttp.WithInsecure())
+	}
+	if tlsCfg != nil {
+		opts = append(opts, otlploghttp.WithTLSClientConfig(tlsCfg))
+	}
+	if len(cfg.OTLPExporterHeaders) > 0 {
+		opts = append(opts, otlploghttp.WithHeaders(cfg.OTLPExporterHeaders))
+	}
+	if cfg.OTLPCompression == config.CompressionGzip {
+		opts = append(opts, otlploghttp.WithCompression(otlploghttp.GzipCompression))
+	}
+	if cfg.OTLPTimeout > 0 {
+		opts = append(opts, otlploghttp.WithTimeout(cfg.OTLPTimeout))
+	}
+	return otlploghttp.New(ctx, opts...)
+}
+
+// setGlobalLoggerProvider installs lp as the OpenTelemetry global logger provider.
+func setGlobalLoggerProvider(lp log.LoggerProvider) {
+	globalMu.Lock()
+	defer globalMu.Unlock()
+	global.SetLoggerProvider(lp)
+}
+
+// unsetGlobalLoggerProvider installs a no-op logger provider if lp is the global one.
+func unsetGlobalLoggerProvider(lp log.LoggerProvider) {
+	globalMu.Lock()
+	defer globalMu.Unlock()
+	if global.GetLoggerProvider() == lp {
+		global.SetLoggerProvider(lognoop.NewLoggerProvider())
+	}
+}
@@ -0,0 +1,149 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/log/global"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+
+	"github.com/kernelshard/otelkit/internal/config"
+)
+
+// memoryLogExporter keeps exported log records in memory.
+type memoryLogExporter struct {
+	mu      sync.Mutex
+	records []sdklog.Record
+}
+
+func (e *memoryLogExporter) Export(_ context.Context, records []sdklog.Record) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for _, r := range records {
+		e.records = append(e.records, r.Clone())
+	}
+	return nil
+}
+
+func (e *memoryLogExporter) Shutdown(context.Context) error   { return nil }
+func (e *memoryLogExporter) ForceFlush(context.Context) error { return nil }
+
+func (e *memoryLogExporter) Records() []sdklog.Record {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]sdklog.Record(nil), e.records...)
+}
+
+func TestNew_LogsCorrelatedWithTrace(t *testing.T) {
+	ctx := context.Background()
+	t.Cleanup(ResetGlobal)
+
+	exporter := &memoryLogExporter{}
+	p, err := New(ctx, newTestHandleConfig(&bytes.Buffer{}).
+		WithLogProcessor(sdklog.NewSimpleProcessor(exporter)))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	if p.LoggerProvider() == nil || global.GetLoggerProvider() != p.LoggerProvider() {
+		t.Fatal("Expected the logger provider to be created and installed as global")
+	}
+
+	spanCtx, span := p.Tracer("logs-test").Start(ctx, "operation")
+	var record log.Record
+	record.SetSeverity(log.SeverityInfo)
+	record.SetBody(log.StringValue("inside span"))
+	p.Logger("logs-test").Emit(spanCtx, record)
+	span.End()
+
+	record.SetBody(log.StringValue("outside span"))
+	p.Logger("logs-test").Emit(ctx, record)
+
+	records := exporter.Records()
+	if len(records) != 2 {
+		t.Fatalf("Expected 2 records, got %d", len(records))
+	}
+	sc := span.SpanContext()
+	if records[0].TraceID() != sc.TraceID() || records[0].SpanID() != sc.SpanID() {
+		t.Errorf("Expected the record to carry trace %s span %s, got %s %s",
+			sc.TraceID(), sc.SpanID(), records[0].TraceID(), records[0].SpanID())
+	}
+	if records[1].TraceID().IsValid() {
+		t.Errorf("Expected no trace ID outside a span, got %s", records[1].TraceID())
+	}
+	if v, ok := records[0].Resource().Set().Value(semconv.ServiceNameKey); !ok || v.AsString() != "handle-service" {
+		t.Errorf("Expected the tracing resource on logs, got %v", records[0].Resource())
+	}
+
+	if err := p.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown failed: %v", err)
+	}
+	if global.GetLoggerProvider() == p.LoggerProvider() {
+		t.Error("Expected Shutdown to remove the global logger provider")
+	}
+}
+
+func TestNew_LogsDisabled(t *testing.T) {
+	ctx := context.Background()
+	t.Cleanup(ResetGlobal)
+
+	p, err := New(ctx, newTestHandleConfig(&bytes.Buffer{}))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	defer p.Shutdown(ctx)
+	if p.LoggerProvider() != nil {
+		t.Error("Expected no logger provider without logs enabled")
+	}
+	if p.Logger("noop").Enabled(ctx, log.EnabledParameters{}) {
+		t.Error("Expected a disabled no-op logger")
+	}
+}
+
+func TestNew_ExportsLogsOverHTTP(t *testing.T) {
+	ctx := context.Background()
+	t.Cleanup(ResetGlobal)
+
+	var mu sync.Mutex
+	var paths []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		paths = append(paths, r.Method+" "+r.URL.Path)
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	cfg := NewProviderConfig("logs-service", "1.0.0").
+		WithOTLPExporter(srv.URL+"/otlp", config.ProtocolHTTP, true).
+		WithSampling(config.SamplingAlwaysOn, 1).
+		WithLogs().
+		WithoutRetry()
+	p, err := New(ctx, cfg)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	defer p.Shutdown(ctx)
+
+	var record log.Record
+	record.SetBody(log.StringValue("exported"))
+	p.Logger("logs-test").Emit(ctx, record)
+	if err := p.ForceFlush(ctx); err != nil {
+		t.Fatalf("ForceFlush failed: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	found := false
+	for _, path := range paths {
+		found = found || path == "POST /otlp/v1/logs"
+	}
+	if !found {
+		t.Errorf("Expected a logs export to /otlp/v1/logs, got %v", paths)
+	}
+}
@@ -0,0 +1,207 @@
+package provider
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	"go.opentelemetry.io/otel/metric"
+	metricnoop "go.opentelemetry.io/otel/metric/noop"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdkresource "go.opentelemetry.io/otel/sdk/resource"
+	"google.golang.org/grpc/credentials"
+
+	"github.com/kernelshard/otelkit/internal/config"
+)
+
+// metricsEnabled reports whether a meter provider is created for cfg: metrics are
+// exported over OTLP or additional readers were added with WithMetricReader.
+func (pc *ProviderConfig) metricsEnabled() bool {
+	return pc.Config.MetricsEnabled() || len(pc.MetricReaders) > 0
+}
+
+// NewMeterProvider creates a meter provider exporting over OTLP with the endpoint,
+// headers, TLS and retry settings of cfg, and installs it as the OpenTelemetry global
+// meter provider unless cfg.SkipGlobalRegistration is set. The resource is created as
+// for the tracer provider, so metrics and traces describe the same service.
+//
+// The OTLP exporter is created when cfg.Config.MetricsExporter is "otlp"; readers
+// added with WithMetricReader are attached in any case.
+//
+// Example:
+//
+//	cfg := provider.NewProviderConfig("payment-service", "v1.2.3").
+//	    WithMetrics(15*time.Second, "delta")
+//	mp, err := provider.NewMeterProvider(ctx, cfg)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	defer mp.Shutdown(ctx)
+func NewMeterProvider(ctx context.Context, cfg *ProviderConfig) (*sdkmetric.MeterProvider, error) {
+	res, err := createResource(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	mp, err := newMeterProvider(ctx, cfg, res)
+	if err != nil {
+		return nil, err
+	}
+	if !cfg.SkipGlobalRegistration {
+		setGlobalMeterProvider(mp)
+	}
+	return mp, nil
+}
+
+// newMeterProvider creates a meter provider with a periodic reader for the OTLP
+// exporter, if enabled, and the additional readers of cfg.
+func newMeterProvider(ctx context.Context, cfg *ProviderConfig, res *sdkresource.Resource) (*sdkmetric.MeterProvider, error) {
+	opts := []sdkmetric.Option{sdkmetric.WithResource(res)}
+	if cfg.Config.MetricsEnabled() {
+		exporter, err := createMetricExporter(ctx, cfg.Config)
+		if err != nil {
+			return nil, &InitializationError{Component: "metric exporter", Cause: err}
+		}
+		var readerOpts []sdkmetric.PeriodicReaderOption
+		if cfg.Config.MetricsExportInterval > 0 {
+			readerOpts = append(readerOpts, sdkmetric.WithInterval(cfg.Config.MetricsExportInterval))
+		}
+		if cfg.Config.MetricsExportTimeout > 0 {
+			readerOpts = append(readerOpts, sdkmetric.WithTimeout(cfg.Config.MetricsExportTimeout))
+		}
+		opts = append(opts, sdkmetric.WithReader(sdkmetric.NewPeriodicReader(exporter, readerOpts...)))
+	}
+	for _, reader := range cfg.MetricReaders {
+		opts = append(opts, sdkmetric.WithReader(reader))
+	}
+	return sdkmetric.NewMeterProvider(opts...), nil
+}
+
+// createMetricExporter creates an OTLP metric exporter: gRPC when the trace protocol
+// is gRPC, HTTP otherwise.
+func createMetricExporter(ctx context.Context, cfg *config.Config) (sdkmetric.Exporter, error) {
+	endpoint, err := cfg.SignalEndpoint("MetricsEndpoint", cfg.MetricsEndpoint, config.MetricsSignalPath)
+	if err != nil {
+		return nil, err
+	}
+	tlsCfg, err := createTLSConfig(cfg, endpoint)
+	if err != nil {
+		return nil, err
+	}
+	selector := temporalitySelector(cfg.MetricsTemporality)
+	policy := retryPolicy(cfg)
+
+	if cfg.OTLPExporterProtocol == config.ProtocolGRPC {
+		opts := []otlpmetricgrpc.Option{
+			otlpmetricgrpc.WithEndpoint(endpoint.Host),
+			otlpmetricgrpc.WithTemporalitySelector(selector),
+			otlpmetricgrpc.WithRetry(otlpmetricgrpc.RetryConfig{
+				Enabled:         policy.enabled,
+				InitialInterval: policy.initialInterval,
+				MaxInterval:     policy.maxInterval,
+				MaxElapsedTime:  policy.maxElapsedTime,
+			}),
+		}
+		if endpoint.Insecure(cfg.OTLPExporterInsecure) {
+			opts = append(opts, otlpmetricgrpc.WithInsecure())
+		}
+		if tlsCfg != nil {
+			opts = append(opts, otlpmetricgrpc.WithTLSCredentials(credentials.NewTLS(tlsCfg)))
+		}
+		if len(cfg.OTLPExporterHeaders) > 0 {
+			opts = append(opts, otlpmetricgrpc.WithHeaders(cfg.OTLPExporterHeaders))
+		}
+		if cfg.OTLPCompression == config.CompressionGzip {
+			opts = append(opts, otlpmetricgrpc.WithCompressor(config.CompressionGzip))
+		}
+		if cfg.OTLPTimeout > 0 {
+			opts = append(opts, otlpmetricgrpc.WithTimeout(cfg.OTLPTimeout))
+		}
+		return otlpmetricgrpc.New(ctx, opts...)
+	}
+
+	opts := []otlpmetrichttp.Option{
+		otlpmetrichttp.WithEndpoint(endpoint.Host),
+		otlpmetrichttp.WithTemporalitySelector(selector),
+		otlpmetrichttp.WithRetry(otlpmetrichttp.RetryConfig{
+			Enabled:         policy.enabled,
+			InitialInterval: policy.initialInterval,
+			MaxInterval:     policy.maxInterval,
+			MaxElapsedTime:  policy.maxElapsedTime,
+		}),
+	}
+	if endpoint.Path != "" {
+		opts = append(opts, otlpmetrichttp.WithURLPath(endpoint.Path))
+	}
+	if endpoint.Insecure(cfg.OTLPExporterInsecure) {
+		opts = append(opts, otlpmetrichttp.WithInsecure())
+	}
+	if tlsCfg != nil {
+		opts = append(opts, otlpmetrichttp.WithTLSClientConfig(tlsCfg))
+	}
+	if len(cfg.OTLPExporterHeaders) > 0 {
+		opts = append(opts, otlpmetrichttp.WithHeaders(cfg.OTLPExporterHeaders))
+	}
+	if cfg.OTLPCompression == config.CompressionGzip {
+		opts = append(opts, otlpmetrichttp.WithCompression(otlpmetrichttp.GzipCompression))
+	}
+	if cfg.OTLPTimeout > 0 {
+		opts = append(opts, otlpmetrichttp.WithTimeout(cfg.OTLPTimeout))
+	}
+	return otlpmetrichttp.New(ctx, opts...)
+}
+
+// temporalitySelector maps a temporality preference to a selector as defined for
+// OTEL_EXPORTER_OTLP_METRICS_TEMPORALITY_PREFERENCE. Up-down counters are always
+// cumulative; lowmemory keeps asynchronous counters cumulative as well.
+func temporalitySelector(preference string) sdkmetric.TemporalitySelector {
+	switch preference {
+	case config.TemporalityDelta:
+		return func(kind sdkmetric.InstrumentKind) metricdata.Temporality {
+			switch kind {
+			case sdkmetric.InstrumentKindUpDownCounter, sdkmetric.InstrumentKindObservableUpDownCounter:
+				return metricdata.CumulativeTemporality
+			default:
+				return metricdata.DeltaTemporality
+			}
+		}
+	case config.TemporalityLowMemory:
+		return func(kind sdkmetric.InstrumentKind) metricdata.Temporality {
+			switch kind {
+			case sdkmetric.InstrumentKindCounter, sdkmetric.InstrumentKindHistogram:
+				return metricdata.DeltaTemporality
+			default:
+				return metricdata.CumulativeTemporality
+			}
+		}
+	default:
+		return sdkmetric.DefaultTemporalitySelector
+	}
+}
+
+// setGlobalMeterProvider installs mp as the OpenTelemetry global meter provider.
+func setGlobalMeterProvider(mp metric.MeterProvider) {
+	globalMu.Lock()
+	defer globalMu.Unlock()
+	otel.SetMeterProvider(mp)
+}
+
+// unsetGlobalMeterProvider installs a no-op meter provider if mp is the global one.
+func unsetGlobalMeterProvider(mp metric.MeterProvider) {
+	globalMu.Lock()
+	defer globalMu.Unlock()
+	if otel.GetMeterProvider() == mp {
+		otel.SetMeterProvider(metricnoop.NewMeterProvider())
+	}
+}
+
+// ShutdownMeterProvider flushes and stops mp and, if it is the global meter provider,
+// replaces the global with a no-op provider.
+func ShutdownMeterProvider(ctx context.Context, mp *sdkmetric.MeterProvider) error {
+	if mp == nil {
+		return nil
+	}
+	unsetGlobalMeterProvider(mp)
+	return mp.Shutdown(ctx)
+}
@@ -0,0 +1,151 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+
+	"github.com/kernelshard/otelkit/internal/config"
+)
+
+func TestNew_WithMetricReader(t *testing.T) {
+	ctx := context.Background()
+	t.Cleanup(ResetGlobal)
+
+	reader := sdkmetric.NewManualReader()
+	p, err := New(ctx, newTestHandleConfig(&bytes.Buffer{}).WithMetricReader(reader))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	if p.MeterProvider() == nil || otel.GetMeterProvider() != p.MeterProvider() {
+		t.Fatal("Expected the meter provider to be created and installed as global")
+	}
+
+	counter, err := p.Meter("metrics-test").Int64Counter("requests")
+	if err != nil {
+		t.Fatalf("Int64Counter failed: %v", err)
+	}
+	counter.Add(ctx, 3)
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(ctx, &rm); err != nil {
+		t.Fatalf("Collect failed: %v", err)
+	}
+	if v, ok := rm.Resource.Set().Value(semconv.ServiceNameKey); !ok || v.AsString() != "handle-service" {
+		t.Errorf("Expected the tracing resource on metrics, got %v", rm.Resource)
+	}
+	var scope *metricdata.ScopeMetrics
+	for i := range rm.ScopeMetrics {
+		if rm.ScopeMetrics[i].Scope.Name == "metrics-test" {
+			scope = &rm.ScopeMetrics[i]
+		}
+	}
+	if scope == nil || len(scope.Metrics) != 1 {
+		t.Fatalf("Expected one metric from metrics-test, got %+v", rm.ScopeMetrics)
+	}
+	sum, ok := scope.Metrics[0].Data.(metricdata.Sum[int64])
+	if !ok || len(sum.DataPoints) != 1 || sum.DataPoints[0].Value != 3 {
+		t.Errorf("Expected a sum of 3, got %+v", scope.Metrics[0].Data)
+	}
+
+	if err := p.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown failed: %v", err)
+	}
+	if otel.GetMeterProvider() == p.MeterProvider() {
+		t.Error("Expected Shutdown to remove the global meter provider")
+	}
+	if err := p.Shutdown(ctx); err != nil {
+		t.Errorf("Expected a second Shutdown to succeed, got %v", err)
+	}
+}
+
+func TestNew_MetricsDisabled(t *testing.T) {
+	ctx := context.Background()
+	t.Cleanup(ResetGlobal)
+
+	p, err := New(ctx, newTestHandleConfig(&bytes.Buffer{}))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	defer p.Shutdown(ctx)
+	if p.MeterProvider() != nil {
+		t.Error("Expected no meter provider without metrics enabled")
+	}
+	if _, err := p.Meter("noop").Int64Counter("requests"); err != nil {
+		t.Errorf("Expected a usable no-op meter, got %v", err)
+	}
+}
+
+func TestNew_ExportsMetricsOverHTTP(t *testing.T) {
+	ctx := context.Background()
+	t.Cleanup(ResetGlobal)
+
+	var mu sync.Mutex
+	var paths []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		paths = append(paths, r.Method+" "+r.URL.Path)
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	cfg := NewProviderConfig("metrics-service", "1.0.0").
+		WithOTLPExporter(srv.URL+"/otlp", config.ProtocolHTTP, true).
+		WithSampling(config.SamplingAlwaysOn, 1).
+		WithMetrics(time.Hour, config.TemporalityDelta).
+		WithoutRetry()
+	p, err := New(ctx, cfg)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	defer p.Shutdown(ctx)
+
+	counter, _ := p.Meter("metrics-test").Int64Counter("requests")
+	counter.Add(ctx, 1)
+	if err := p.ForceFlush(ctx); err != nil {
+		t.Fatalf("ForceFlush failed: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	found := false
+	for _, path := range paths {
+		found = found || path == "POST /otlp/v1/metrics"
+	}
+	if !found {
+		t.Errorf("Expected a metrics export to /otlp/v1/metrics, got %v", paths)
+	}
+}
+
+func TestTemporalitySelector(t *testing.T) {
+	delta, cumulative := metricdata.DeltaTemporality, metricdata.CumulativeTemporality
+	tests := []struct {
+		preference string
+		kind       sdkmetric.InstrumentKind
+		want       metricdata.Temporality
+	}{
+		{config.TemporalityCumulative, sdkmetric.InstrumentKindCounter, cumulative},
+		{config.TemporalityDelta, sdkmetric.InstrumentKindCounter, delta},
+		{config.TemporalityDelta, sdkmetric.InstrumentKindObservableCounter, delta},
+		{config.TemporalityDelta, sdkmetric.InstrumentKindHistogram, delta},
+		{config.TemporalityDelta, sdkmetric.InstrumentKindUpDownCounter, cumulative},
+		{config.TemporalityLowMemory, sdkmetric.InstrumentKindCounter, delta},
+		{config.TemporalityLowMemory, sdkmetric.InstrumentKindHistogram, delta},
+		{config.TemporalityLowMemory, sdkmetric.InstrumentKindObservableCounter, cumulative},
+	}
+	for _, tt := range tests {
+		if got := temporalitySelector(tt.preference)(tt.kind); got != tt.want {
+			t.Errorf("%s/%v: expected %v, got %v", tt.preference, tt.kind, tt.want, got)
+		}
+	}
+}
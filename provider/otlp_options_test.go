@@ -0,0 +1,229 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/kernelshard/otelkit/internal/config"
+)
+
+// otlpRequest captures the parts of an OTLP/HTTP request the tests assert on.
+type otlpRequest struct {
+	path            string
+	header          http.Header
+	contentEncoding string
+}
+
+// newOTLPTestServer starts an HTTP server accepting OTLP trace exports and records
+// every request it receives.
+func newOTLPTestServer(t *testing.T) (*httptest.Server, func() []otlpRequest) {
+	t.Helper()
+	var mu sync.Mutex
+	var requests []otlpRequest
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests = append(requests, otlpRequest{
+			path:            r.URL.Path,
+			header:          r.Header.Clone(),
+			contentEncoding: r.Header.Get("Content-Encoding"),
+		})
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/x-protobuf")
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv, func() []otlpRequest {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]otlpRequest(nil), requests...)
+	}
+}
+
+func TestHTTPExporter_HeadersAndCompression(t *testing.T) {
+	ctx := context.Background()
+	srv, requests := newOTLPTestServer(t)
+
+	pc := NewProviderConfig("header-service", "1.0.0").
+		WithOTLPExporter(strings.TrimPrefix(srv.URL, "http://"), config.ProtocolHTTP, true).
+		WithSampling(config.SamplingAlwaysOn, 1.0).
+		WithHeaders(map[string]string{"x-api-key": "secret"}).
+		WithCompression(config.CompressionGzip).
+		WithOTLPTimeout(5 * time.Second)
+
+	tp, err := newProvider(ctx, pc)
+	if err != nil {
+		t.Fatalf("newProvider failed: %v", err)
+	}
+	_, span := tp.Tracer("test").Start(ctx, "with-headers")
+	span.End()
+	if err := ShutdownTracerProvider(ctx, tp); err != nil {
+		t.Fatalf("Shutdown failed: %v", err)
+	}
+
+	got := requests()
+	if len(got) == 0 {
+		t.Fatal("Expected at least one export request")
+	}
+	if got[0].header.Get("x-api-key") != "secret" {
+		t.Errorf("Expected x-api-key header, got %q", got[0].header.Get("x-api-key"))
+	}
+	if got[0].contentEncoding != "gzip" {
+		t.Errorf("Expected gzip Content-Encoding, got %q", got[0].contentEncoding)
+	}
+}
+
+func TestProviderConfig_HeaderCompressionTimeoutBuilders(t *testing.T) {
+	pc := NewProviderConfig("test", "1.0.0").
+		WithHeaders(map[string]string{"authorization": "Bearer token"}).
+		WithCompression("gzip").
+		WithOTLPTimeout(3 * time.Second)
+
+	if pc.Config.OTLPExporterHeaders["authorization"] != "Bearer token" {
+		t.Error("WithHeaders did not set headers")
+	}
+	if pc.Config.OTLPCompression != "gzip" {
+		t.Errorf("Expected compression gzip, got %s", pc.Config.OTLPCompression)
+	}
+	if pc.Config.OTLPTimeout != 3*time.Second {
+		t.Errorf("Expected timeout 3s, got %v", pc.Config.OTLPTimeout)
+	}
+}
+
+func TestHTTPExporter_URLEndpoint(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		base     string
+		traces   string
+		wantPath string
+	}{
+		// The base endpoint's path is extended with /v1/traces as for OTEL_EXPORTER_OTLP_ENDPOINT.
+		{"base path", "/otlp", "", "/otlp/v1/traces"},
+		{"base path with slash", "/otlp/", "", "/otlp/v1/traces"},
+		{"no base path", "", "", "/v1/traces"},
+		// A traces endpoint is sent to as-is.
+		{"traces endpoint", "/otlp", "/custom/v1/traces", "/custom/v1/traces"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, requests := newOTLPTestServer(t)
+
+			// The http:// scheme selects plaintext even though insecure is false.
+			pc := NewProviderConfig("url-service", "1.0.0").
+				WithOTLPExporter(srv.URL+tt.base, config.ProtocolHTTP, false).
+				WithSampling(config.SamplingAlwaysOn, 1.0)
+			if tt.traces != "" {
+				pc.WithTracesEndpoint(srv.URL + tt.traces)
+			}
+
+			exporter, err := createExporter(ctx, pc, nil)
+			if err != nil {
+				t.Fatalf("createExporter failed: %v", err)
+			}
+			defer exporter.Shutdown(ctx)
+
+			if err := exporter.ExportSpans(ctx, testSpans(t)); err != nil {
+				t.Fatalf("ExportSpans failed: %v", err)
+			}
+			got := requests()
+			if len(got) != 1 || got[0].path != tt.wantPath {
+				t.Errorf("Expected one request to %s, got %+v", tt.wantPath, got)
+			}
+		})
+	}
+}
+
+func TestCreateExporter_MalformedURLEndpoint(t *testing.T) {
+	pc := NewProviderConfig("url-service", "1.0.0").
+		WithOTLPExporter("ftp://collector:4318", config.ProtocolHTTP, false)
+
+	_, err := createExporter(context.Background(), pc, nil)
+	var configErr *config.ConfigError
+	if !errors.As(err, &configErr) || configErr.Field != "OTLPExporterEndpoint" {
+		t.Fatalf("Expected OTLPExporterEndpoint ConfigError, got %v", err)
+	}
+}
+
+// newFlakyOTLPServer starts an OTLP/HTTP server that answers 503 to the first
+// failures requests and 200 afterwards. It returns the host:port and a request counter.
+func newFlakyOTLPServer(t *testing.T, failures int32) (string, *atomic.Int32) {
+	t.Helper()
+	var count atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if count.Add(1) <= failures {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/x-protobuf")
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(srv.Close)
+	return strings.TrimPrefix(srv.URL, "http://"), &count
+}
+
+func TestHTTPExporter_Retry(t *testing.T) {
+	ctx := context.Background()
+	endpoint, count := newFlakyOTLPServer(t, 2)
+
+	pc := NewProviderConfig("retry-service", "1.0.0").
+		WithOTLPExporter(endpoint, config.ProtocolHTTP, true).
+		WithRetry(5*time.Millisecond, 10*time.Millisecond, 5*time.Second)
+
+	exporter, err := createExporter(ctx, pc, nil)
+	if err != nil {
+		t.Fatalf("createExporter failed: %v", err)
+	}
+	defer exporter.Shutdown(ctx)
+
+	if err := exporter.ExportSpans(ctx, testSpans(t)); err != nil {
+		t.Fatalf("Expected export to succeed after retries, got %v", err)
+	}
+	if got := count.Load(); got != 3 {
+		t.Errorf("Expected 3 requests, got %d", got)
+	}
+}
+
+func TestHTTPExporter_WithoutRetry(t *testing.T) {
+	ctx := context.Background()
+	endpoint, count := newFlakyOTLPServer(t, 1)
+
+	pc := NewProviderConfig("retry-service", "1.0.0").
+		WithOTLPExporter(endpoint, config.ProtocolHTTP, true).
+		WithoutRetry()
+
+	exporter, err := createExporter(ctx, pc, nil)
+	if err != nil {
+		t.Fatalf("createExporter failed: %v", err)
+	}
+	defer exporter.Shutdown(ctx)
+
+	if err := exporter.ExportSpans(ctx, testSpans(t)); err == nil {
+		t.Error("Expected export to fail without retries")
+	}
+	if got := count.Load(); got != 1 {
+		t.Errorf("Expected a single request, got %d", got)
+	}
+}
+
+func TestRetryPolicy_Defaults(t *testing.T) {
+	policy := retryPolicy(&config.Config{})
+	want := exportRetryPolicy{
+		enabled:         true,
+		initialInterval: config.DefaultRetryInitialInterval,
+		maxInterval:     config.DefaultRetryMaxInterval,
+		maxElapsedTime:  config.DefaultRetryMaxElapsedTime,
+	}
+	if policy != want {
+		t.Errorf("retryPolicy() = %+v, want %+v", policy, want)
+	}
+}
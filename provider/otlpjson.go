@@ -0,0 +1,587 @@
+package provider
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/instrumentation"
+	sdkresource "go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// The types below mirror the OTLP/JSON encoding of ExportTraceServiceRequest as
+// specified by the OTLP protocol: trace and span IDs are lowercase hex strings,
+// 64-bit integers are encoded as decimal strings and enums as integers. Files
+// written with this encoding can be replayed by the collector's otlpjson receivers,
+// and decodeOTLPTraceData turns them back into spans for re-export.
+
+type otlpTraceData struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+type otlpResourceSpans struct {
+	Resource   otlpResource     `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+	SchemaURL  string           `json:"schemaUrl,omitempty"`
+}
+
+type otlpResource struct {
+	Attributes []otlpKeyValue `json:"attributes,omitempty"`
+}
+
+type otlpScopeSpans struct {
+	Scope     otlpScope  `json:"scope"`
+	Spans     []otlpSpan `json:"spans"`
+	SchemaURL string     `json:"schemaUrl,omitempty"`
+}
+
+type otlpScope struct {
+	Name       string         `json:"name,omitempty"`
+	Version    string         `json:"version,omitempty"`
+	Attributes []otlpKeyValue `json:"attributes,omitempty"`
+}
+
+type otlpSpan struct {
+	TraceID                string         `json:"traceId"`
+	SpanID                 string         `json:"spanId"`
+	TraceState             string         `json:"traceState,omitempty"`
+	ParentSpanID           string         `json:"parentSpanId,omitempty"`
+	Flags                  uint32         `json:"flags,omitempty"`
+	Name                   string         `json:"name"`
+	Kind                   int            `json:"kind"`
+	StartTimeUnixNano      string         `json:"startTimeUnixNano"`
+	EndTimeUnixNano        string         `json:"endTimeUnixNano"`
+	Attributes             []otlpKeyValue `json:"attributes,omitempty"`
+	DroppedAttributesCount int            `json:"droppedAttributesCount,omitempty"`
+	Events                 []otlpEvent    `json:"events,omitempty"`
+	DroppedEventsCount     int            `json:"droppedEventsCount,omitempty"`
+	Links                  []otlpLink     `json:"links,omitempty"`
+	DroppedLinksCount      int            `json:"droppedLinksCount,omitempty"`
+	Status                 otlpStatus     `json:"status"`
+}
+
+type otlpEvent struct {
+	TimeUnixNano           string         `json:"timeUnixNano"`
+	Name                   string         `json:"name"`
+	Attributes             []otlpKeyValue `json:"attributes,omitempty"`
+	DroppedAttributesCount int            `json:"droppedAttributesCount,omitempty"`
+}
+
+type otlpLink struct {
+	TraceID                string         `json:"traceId"`
+	SpanID                 string         `json:"spanId"`
+	TraceState             string         `json:"traceState,omitempty"`
+	Attributes             []otlpKeyValue `json:"attributes,omitempty"`
+	DroppedAttributesCount int            `json:"droppedAttributesCount,omitempty"`
+	Flags                  uint32         `json:"flags,omitempty"`
+}
+
+type otlpStatus struct {
+	Message string `json:"message,omitempty"`
+	Code    int    `json:"code,omitempty"`
+}
+
+type otlpKeyValue struct {
+	Key   string       `json:"key"`
+	Value otlpAnyValue `json:"value"`
+}
+
+type otlpAnyValue struct {
+	StringValue *string         `json:"stringValue,omitempty"`
+	BoolValue   *bool           `json:"boolValue,omitempty"`
+	IntValue    *string         `json:"intValue,omitempty"`
+	DoubleValue *otlpDouble     `json:"doubleValue,omitempty"`
+	ArrayValue  *otlpArrayValue `json:"arrayValue,omitempty"`
+}
+
+type otlpArrayValue struct {
+	Values []otlpAnyValue `json:"values"`
+}
+
+// otlpDouble is a double value. JSON numbers cannot hold NaN and infinities, which
+// OTLP/JSON encodes as the strings "NaN", "Infinity" and "-Infinity" instead.
+type otlpDouble float64
+
+func (d otlpDouble) MarshalJSON() ([]byte, error) {
+	switch f := float64(d); {
+	case math.IsNaN(f):
+		return []byte(`"NaN"`), nil
+	case math.IsInf(f, 1):
+		return []byte(`"Infinity"`), nil
+	case math.IsInf(f, -1):
+		return []byte(`"-Infinity"`), nil
+	default:
+		return json.Marshal(f)
+	}
+}
+
+func (d *otlpDouble) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		var f float64
+		if err := json.Unmarshal(data, &f); err != nil {
+			return err
+		}
+		*d = otlpDouble(f)
+		return nil
+	}
+	switch s {
+	case "NaN":
+		*d = otlpDouble(math.NaN())
+	case "Infinity":
+		*d = otlpDouble(math.Inf(1))
+	case "-Infinity":
+		*d = otlpDouble(math.Inf(-1))
+	default:
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return fmt.Errorf("invalid doubleValue %q", s)
+		}
+		*d = otlpDouble(f)
+	}
+	return nil
+}
+
+// OTLP status codes. These differ from the numeric values of codes.Code.
+const (
+	otlpStatusUnset = 0
+	otlpStatusOk    = 1
+	otlpStatusError = 2
+)
+
+// OTLP span flag bits carrying the W3C trace flags and the remote-parent marker.
+const (
+	otlpSpanFlagsTraceFlagsMask     = 0x000000FF
+	otlpSpanFlagsContextHasIsRemote = 0x00000100
+	otlpSpanFlagsContextIsRemote    = 0x00000200
+)
+
+// encodeOTLPTraceData groups spans by resource and instrumentation scope and converts
+// them into the OTLP/JSON request structure.
+func encodeOTLPTraceData(spans []sdktrace.ReadOnlySpan) otlpTraceData {
+	type scopeKey struct {
+		name, version, schemaURL string
+	}
+	type resourceGroup struct {
+		resource   *sdkresource.Resource
+		scopeOrder []scopeKey
+		scopes     map[scopeKey]*otlpScopeSpans
+	}
+
+	var resourceOrder []attribute.Distinct
+	groups := make(map[attribute.Distinct]*resourceGroup)
+
+	for _, s := range spans {
+		res := s.Resource()
+		resKey := res.Equivalent()
+		group, ok := groups[resKey]
+		if !ok {
+			group = &resourceGroup{resource: res, scopes: make(map[scopeKey]*otlpScopeSpans)}
+			groups[resKey] = group
+			resourceOrder = append(resourceOrder, resKey)
+		}
+
+		scope := s.InstrumentationScope()
+		sk := scopeKey{name: scope.Name, version: scope.Version, schemaURL: scope.SchemaURL}
+		ss, ok := group.scopes[sk]
+		if !ok {
+			ss = &otlpScopeSpans{Scope: encodeOTLPScope(scope), SchemaURL: scope.SchemaURL}
+			group.scopes[sk] = ss
+			group.scopeOrder = append(group.scopeOrder, sk)
+		}
+		ss.Spans = append(ss.Spans, encodeOTLPSpan(s))
+	}
+
+	data := otlpTraceData{ResourceSpans: make([]otlpResourceSpans, 0, len(resourceOrder))}
+	for _, key := range resourceOrder {
+		group := groups[key]
+		rs := otlpResourceSpans{}
+		if group.resource != nil {
+			rs.Resource.Attributes = encodeOTLPAttributes(group.resource.Attributes())
+			rs.SchemaURL = group.resource.SchemaURL()
+		}
+		for _, sk := range group.scopeOrder {
+			rs.ScopeSpans = append(rs.ScopeSpans, *group.scopes[sk])
+		}
+		data.ResourceSpans = append(data.ResourceSpans, rs)
+	}
+	return data
+}
+
+func encodeOTLPScope(scope instrumentation.Scope) otlpScope {
+	return otlpScope{
+		Name:       scope.Name,
+		Version:    scope.Version,
+		Attributes: encodeOTLPAttributes(scope.Attributes.ToSlice()),
+	}
+}
+
+func encodeOTLPSpan(s sdktrace.ReadOnlySpan) otlpSpan {
+	sc := s.SpanContext()
+	out := otlpSpan{
+		TraceID:                sc.TraceID().String(),
+		SpanID:                 sc.SpanID().String(),
+		TraceState:             sc.TraceState().String(),
+		Flags:                  uint32(sc.TraceFlags()) | otlpSpanFlagsContextHasIsRemote,
+		Name:                   s.Name(),
+		Kind:                   int(s.SpanKind()),
+		StartTimeUnixNano:      formatUnixNano(s.StartTime().UnixNano()),
+		EndTimeUnixNano:        formatUnixNano(s.EndTime().UnixNano()),
+		Attributes:             encodeOTLPAttributes(s.Attributes()),
+		DroppedAttributesCount: s.DroppedAttributes(),
+		DroppedEventsCount:     s.DroppedEvents(),
+		DroppedLinksCount:      s.DroppedLinks(),
+		Status:                 encodeOTLPStatus(s.Status()),
+	}
+	if parent := s.Parent(); parent.IsValid() {
+		out.ParentSpanID = parent.SpanID().String()
+		if parent.IsRemote() {
+			out.Flags |= otlpSpanFlagsContextIsRemote
+		}
+	}
+	for _, ev := range s.Events() {
+		out.Events = append(out.Events, otlpEvent{
+			TimeUnixNano:           formatUnixNano(ev.Time.UnixNano()),
+			Name:                   ev.Name,
+			Attributes:             encodeOTLPAttributes(ev.Attributes),
+			DroppedAttributesCount: ev.DroppedAttributeCount,
+		})
+	}
+	for _, link := range s.Links() {
+		out.Links = append(out.Links, otlpLink{
+			TraceID:                link.SpanContext.TraceID().String(),
+			SpanID:                 link.SpanContext.SpanID().String(),
+			TraceState:             link.SpanContext.TraceState().String(),
+			Attributes:             encodeOTLPAttributes(link.Attributes),
+			DroppedAttributesCount: link.DroppedAttributeCount,
+			Flags:                  uint32(link.SpanContext.TraceFlags()),
+		})
+	}
+	return out
+}
+
+func encodeOTLPStatus(status sdktrace.Status) otlpStatus {
+	out := otlpStatus{Message: status.Description}
+	switch status.Code {
+	case codes.Ok:
+		out.Code = otlpStatusOk
+	case codes.Error:
+		out.Code = otlpStatusError
+	default:
+		out.Code = otlpStatusUnset
+	}
+	return out
+}
+
+func encodeOTLPAttributes(attrs []attribute.KeyValue) []otlpKeyValue {
+	if len(attrs) == 0 {
+		return nil
+	}
+	out := make([]otlpKeyValue, 0, len(attrs))
+	for _, kv := range attrs {
+		out = append(out, otlpKeyValue{Key: string(kv.Key), Value: encodeOTLPValue(kv.Value)})
+	}
+	return out
+}
+
+func encodeOTLPValue(v attribute.Value) otlpAnyValue {
+	switch v.Type() {
+	case attribute.BOOL:
+		b := v.AsBool()
+		return otlpAnyValue{BoolValue: &b}
+	case attribute.INT64:
+		i := strconv.FormatInt(v.AsInt64(), 10)
+		return otlpAnyValue{IntValue: &i}
+	case attribute.FLOAT64:
+		f := otlpDouble(v.AsFloat64())
+		return otlpAnyValue{DoubleValue: &f}
+	case attribute.STRING:
+		s := v.AsString()
+		return otlpAnyValue{StringValue: &s}
+	case attribute.BOOLSLICE:
+		values := make([]otlpAnyValue, 0)
+		for _, b := range v.AsBoolSlice() {
+			values = append(values, encodeOTLPValue(attribute.BoolValue(b)))
+		}
+		return otlpAnyValue{ArrayValue: &otlpArrayValue{Values: values}}
+	case attribute.INT64SLICE:
+		values := make([]otlpAnyValue, 0)
+		for _, i := range v.AsInt64Slice() {
+			values = append(values, encodeOTLPValue(attribute.Int64Value(i)))
+		}
+		return otlpAnyValue{ArrayValue: &otlpArrayValue{Values: values}}
+	case attribute.FLOAT64SLICE:
+		values := make([]otlpAnyValue, 0)
+		for _, f := range v.AsFloat64Slice() {
+			values = append(values, encodeOTLPValue(attribute.Float64Value(f)))
+		}
+		return otlpAnyValue{ArrayValue: &otlpArrayValue{Values: values}}
+	case attribute.STRINGSLICE:
+		values := make([]otlpAnyValue, 0)
+		for _, s := range v.AsStringSlice() {
+			values = append(values, encodeOTLPValue(attribute.StringValue(s)))
+		}
+		return otlpAnyValue{ArrayValue: &otlpArrayValue{Values: values}}
+	default:
+		s := v.Emit()
+		return otlpAnyValue{StringValue: &s}
+	}
+}
+
+func formatUnixNano(ns int64) string {
+	if ns < 0 {
+		ns = 0
+	}
+	return strconv.FormatInt(ns, 10)
+}
+
+// decodeOTLPTraceData converts OTLP/JSON trace data back into read-only spans. It is
+// the inverse of encodeOTLPTraceData; child span counts are not part of OTLP and are
+// therefore zero.
+func decodeOTLPTraceData(data otlpTraceData) ([]sdktrace.ReadOnlySpan, error) {
+	var spans []sdktrace.ReadOnlySpan
+	for _, rs := range data.ResourceSpans {
+		attrs, err := decodeOTLPAttributes(rs.Resource.Attributes)
+		if err != nil {
+			return nil, err
+		}
+		res := sdkresource.NewWithAttributes(rs.SchemaURL, attrs...)
+
+		for _, ss := range rs.ScopeSpans {
+			scopeAttrs, err := decodeOTLPAttributes(ss.Scope.Attributes)
+			if err != nil {
+				return nil, err
+			}
+			scope := instrumentation.Scope{
+				Name:       ss.Scope.Name,
+				Version:    ss.Scope.Version,
+				SchemaURL:  ss.SchemaURL,
+				Attributes: attribute.NewSet(scopeAttrs...),
+			}
+			for _, s := range ss.Spans {
+				stub, err := decodeOTLPSpan(s)
+				if err != nil {
+					return nil, err
+				}
+				stub.Resource = res
+				stub.InstrumentationScope = scope
+				spans = append(spans, stub.Snapshot())
+			}
+		}
+	}
+	return spans, nil
+}
+
+func decodeOTLPSpan(s otlpSpan) (tracetest.SpanStub, error) {
+	sc, err := decodeOTLPSpanContext(s.TraceID, s.SpanID, s.TraceState, s.Flags)
+	if err != nil {
+		return tracetest.SpanStub{}, err
+	}
+	start, err := parseUnixNano(s.StartTimeUnixNano)
+	if err != nil {
+		return tracetest.SpanStub{}, err
+	}
+	end, err := parseUnixNano(s.EndTimeUnixNano)
+	if err != nil {
+		return tracetest.SpanStub{}, err
+	}
+	attrs, err := decodeOTLPAttributes(s.Attributes)
+	if err != nil {
+		return tracetest.SpanStub{}, err
+	}
+
+	stub := tracetest.SpanStub{
+		Name:              s.Name,
+		SpanContext:       sc,
+		SpanKind:          trace.SpanKind(s.Kind),
+		StartTime:         start,
+		EndTime:           end,
+		Attributes:        attrs,
+		Status:            decodeOTLPStatus(s.Status),
+		DroppedAttributes: s.DroppedAttributesCount,
+		DroppedEvents:     s.DroppedEventsCount,
+		DroppedLinks:      s.DroppedLinksCount,
+	}
+	if s.ParentSpanID != "" {
+		parentID, err := trace.SpanIDFromHex(s.ParentSpanID)
+		if err != nil {
+			return tracetest.SpanStub{}, fmt.Errorf("parentSpanId: %w", err)
+		}
+		stub.Parent = trace.NewSpanContext(trace.SpanContextConfig{
+			TraceID:    sc.TraceID(),
+			SpanID:     parentID,
+			TraceFlags: sc.TraceFlags(),
+			Remote:     s.Flags&otlpSpanFlagsContextIsRemote != 0,
+		})
+	}
+	for _, ev := range s.Events {
+		ts, err := parseUnixNano(ev.TimeUnixNano)
+		if err != nil {
+			return tracetest.SpanStub{}, err
+		}
+		evAttrs, err := decodeOTLPAttributes(ev.Attributes)
+		if err != nil {
+			return tracetest.SpanStub{}, err
+		}
+		stub.Events = append(stub.Events, sdktrace.Event{
+			Name:                  ev.Name,
+			Time:                  ts,
+			Attributes:            evAttrs,
+			DroppedAttributeCount: ev.DroppedAttributesCount,
+		})
+	}
+	for _, link := range s.Links {
+		linkSC, err := decodeOTLPSpanContext(link.TraceID, link.SpanID, link.TraceState, link.Flags)
+		if err != nil {
+			return tracetest.SpanStub{}, err
+		}
+		linkAttrs, err := decodeOTLPAttributes(link.Attributes)
+		if err != nil {
+			return tracetest.SpanStub{}, err
+		}
+		stub.Links = append(stub.Links, sdktrace.Link{
+			SpanContext:           linkSC,
+			Attributes:            linkAttrs,
+			DroppedAttributeCount: link.DroppedAttributesCount,
+		})
+	}
+	return stub, nil
+}
+
+func decodeOTLPSpanContext(traceID, spanID, traceState string, flags uint32) (trace.SpanContext, error) {
+	tid, err := trace.TraceIDFromHex(traceID)
+	if err != nil {
+		return trace.SpanContext{}, fmt.Errorf("traceId: %w", err)
+	}
+	sid, err := trace.SpanIDFromHex(spanID)
+	if err != nil {
+		return trace.SpanContext{}, fmt.Errorf("spanId: %w", err)
+	}
+	ts, err := trace.ParseTraceState(traceState)
+	if err != nil {
+		return trace.SpanContext{}, fmt.Errorf("traceState: %w", err)
+	}
+	return trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    tid,
+		SpanID:     sid,
+		TraceState: ts,
+		TraceFlags: trace.TraceFlags(flags & otlpSpanFlagsTraceFlagsMask),
+	}), nil
+}
+
+func decodeOTLPStatus(status otlpStatus) sdktrace.Status {
+	switch status.Code {
+	case otlpStatusOk:
+		return sdktrace.Status{Code: codes.Ok}
+	case otlpStatusError:
+		return sdktrace.Status{Code: codes.Error, Description: status.Message}
+	default:
+		return sdktrace.Status{Code: codes.Unset}
+	}
+}
+
+func decodeOTLPAttributes(kvs []otlpKeyValue) ([]attribute.KeyValue, error) {
+	if len(kvs) == 0 {
+		return nil, nil
+	}
+	out := make([]attribute.KeyValue, 0, len(kvs))
+	for _, kv := range kvs {
+		v, err := decodeOTLPValue(kv.Value)
+		if err != nil {
+			return nil, fmt.Errorf("attribute %q: %w", kv.Key, err)
+		}
+		out = append(out, attribute.KeyValue{Key: attribute.Key(kv.Key), Value: v})
+	}
+	return out, nil
+}
+
+// decodeOTLPValue converts an OTLP AnyValue into an attribute value. Arrays are
+// typed by their first element, matching the homogeneous slices the encoder emits.
+func decodeOTLPValue(v otlpAnyValue) (attribute.Value, error) {
+	switch {
+	case v.StringValue != nil:
+		return attribute.StringValue(*v.StringValue), nil
+	case v.BoolValue != nil:
+		return attribute.BoolValue(*v.BoolValue), nil
+	case v.IntValue != nil:
+		i, err := strconv.ParseInt(*v.IntValue, 10, 64)
+		if err != nil {
+			return attribute.Value{}, err
+		}
+		return attribute.Int64Value(i), nil
+	case v.DoubleValue != nil:
+		return attribute.Float64Value(float64(*v.DoubleValue)), nil
+	case v.ArrayValue != nil:
+		return decodeOTLPArray(v.ArrayValue.Values)
+	default:
+		return attribute.StringValue(""), nil
+	}
+}
+
+// errMixedArrayTypes is returned for arrays whose elements differ in type, which
+// attribute values cannot represent.
+var errMixedArrayTypes = errors.New("mixed array value types")
+
+func decodeOTLPArray(values []otlpAnyValue) (attribute.Value, error) {
+	if len(values) == 0 {
+		return attribute.StringSliceValue(nil), nil
+	}
+	switch first := values[0]; {
+	case first.BoolValue != nil:
+		out := make([]bool, 0, len(values))
+		for _, v := range values {
+			if v.BoolValue == nil {
+				return attribute.Value{}, errMixedArrayTypes
+			}
+			out = append(out, *v.BoolValue)
+		}
+		return attribute.BoolSliceValue(out), nil
+	case first.IntValue != nil:
+		out := make([]int64, 0, len(values))
+		for _, v := range values {
+			if v.IntValue == nil {
+				return attribute.Value{}, errMixedArrayTypes
+			}
+			i, err := strconv.ParseInt(*v.IntValue, 10, 64)
+			if err != nil {
+				return attribute.Value{}, err
+			}
+			out = append(out, i)
+		}
+		return attribute.Int64SliceValue(out), nil
+	case first.DoubleValue != nil:
+		out := make([]float64, 0, len(values))
+		for _, v := range values {
+			if v.DoubleValue == nil {
+				return attribute.Value{}, errMixedArrayTypes
+			}
+			out = append(out, float64(*v.DoubleValue))
+		}
+		return attribute.Float64SliceValue(out), nil
+	default:
+		out := make([]string, 0, len(values))
+		for _, v := range values {
+			if v.StringValue == nil {
+				return attribute.Value{}, errMixedArrayTypes
+			}
+			out = append(out, *v.StringValue)
+		}
+		return attribute.StringSliceValue(out), nil
+	}
+}
+
+func parseUnixNano(s string) (time.Time, error) {
+	ns, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("timestamp: %w", err)
+	}
+	return time.Unix(0, ns), nil
+}
@@ -0,0 +1,128 @@
+package provider
+
+import (
+	"encoding/json"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+func TestOTLPJSON_RoundTrip(t *testing.T) {
+	spans := testSpans(t)
+
+	data, err := json.Marshal(encodeOTLPTraceData(spans))
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	var td otlpTraceData
+	if err := json.Unmarshal(data, &td); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	decoded, err := decodeOTLPTraceData(td)
+	if err != nil {
+		t.Fatalf("decodeOTLPTraceData failed: %v", err)
+	}
+
+	if len(decoded) != len(spans) {
+		t.Fatalf("Expected %d spans, got %d", len(spans), len(decoded))
+	}
+	for i, want := range spans {
+		got := decoded[i]
+		if got.Name() != want.Name() || got.SpanKind() != want.SpanKind() {
+			t.Errorf("span %d: got %s/%v, want %s/%v", i, got.Name(), got.SpanKind(), want.Name(), want.SpanKind())
+		}
+		if !got.SpanContext().Equal(want.SpanContext()) {
+			t.Errorf("span %d: span context mismatch", i)
+		}
+		if got.Parent().SpanID() != want.Parent().SpanID() {
+			t.Errorf("span %d: parent mismatch", i)
+		}
+		if !got.StartTime().Equal(want.StartTime()) || !got.EndTime().Equal(want.EndTime()) {
+			t.Errorf("span %d: timestamps mismatch", i)
+		}
+		if !reflect.DeepEqual(got.Attributes(), want.Attributes()) {
+			t.Errorf("span %d: attributes %v, want %v", i, got.Attributes(), want.Attributes())
+		}
+		if got.Status() != want.Status() {
+			t.Errorf("span %d: status %v, want %v", i, got.Status(), want.Status())
+		}
+		if len(got.Events()) != len(want.Events()) {
+			t.Errorf("span %d: expected %d events, got %d", i, len(want.Events()), len(got.Events()))
+		}
+		if got.Resource().Equivalent() != want.Resource().Equivalent() {
+			t.Errorf("span %d: resource mismatch", i)
+		}
+		if got.InstrumentationScope().Name != want.InstrumentationScope().Name {
+			t.Errorf("span %d: scope mismatch", i)
+		}
+	}
+}
+
+func TestDecodeOTLPValue_Arrays(t *testing.T) {
+	values := []attribute.Value{
+		attribute.BoolSliceValue([]bool{true, false}),
+		attribute.Int64SliceValue([]int64{1, 9007199254740993}),
+		attribute.Float64SliceValue([]float64{0.5}),
+		attribute.StringSliceValue([]string{"a", "b"}),
+	}
+	for _, v := range values {
+		got, err := decodeOTLPValue(encodeOTLPValue(v))
+		if err != nil {
+			t.Fatalf("decodeOTLPValue(%v) failed: %v", v.Emit(), err)
+		}
+		if got != v && !reflect.DeepEqual(got.AsInterface(), v.AsInterface()) {
+			t.Errorf("round trip of %s: got %s", v.Emit(), got.Emit())
+		}
+	}
+}
+
+func TestOTLPDouble_NonFinite(t *testing.T) {
+	attrs := []attribute.KeyValue{
+		attribute.Float64("nan", math.NaN()),
+		attribute.Float64("inf", math.Inf(1)),
+		attribute.Float64Slice("bounds", []float64{math.Inf(-1), 1.5}),
+	}
+	data, err := json.Marshal(encodeOTLPAttributes(attrs))
+	if err != nil {
+		t.Fatalf("Expected non-finite values to be encodable, got %v", err)
+	}
+	for _, want := range []string{`"doubleValue":"NaN"`, `"doubleValue":"Infinity"`, `"doubleValue":"-Infinity"`, `"doubleValue":1.5`} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("Expected %s in %s", want, data)
+		}
+	}
+
+	var decoded []otlpKeyValue
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	nan, _ := decodeOTLPValue(decoded[0].Value)
+	inf, _ := decodeOTLPValue(decoded[1].Value)
+	bounds, _ := decodeOTLPValue(decoded[2].Value)
+	if !math.IsNaN(nan.AsFloat64()) || !math.IsInf(inf.AsFloat64(), 1) ||
+		!reflect.DeepEqual(bounds.AsFloat64Slice(), []float64{math.Inf(-1), 1.5}) {
+		t.Errorf("Unexpected round trip: %v %v %v", nan.Emit(), inf.Emit(), bounds.Emit())
+	}
+}
+
+func TestDecodeOTLPTraceData_InvalidTraceID(t *testing.T) {
+	td := otlpTraceData{ResourceSpans: []otlpResourceSpans{{
+		ScopeSpans: []otlpScopeSpans{{Spans: []otlpSpan{{TraceID: "xyz", SpanID: "0102030405060708"}}}},
+	}}}
+	if _, err := decodeOTLPTraceData(td); err == nil {
+		t.Error("Expected error for invalid trace ID")
+	}
+}
+
+func TestDecodeOTLPStatus(t *testing.T) {
+	if got := decodeOTLPStatus(otlpStatus{Code: otlpStatusError, Message: "boom"}); got.Code != codes.Error || got.Description != "boom" {
+		t.Errorf("Unexpected status %+v", got)
+	}
+	if got := decodeOTLPStatus(otlpStatus{Code: otlpStatusOk}); got.Code != codes.Ok {
+		t.Errorf("Unexpected status %+v", got)
+	}
+}
@@ -0,0 +1,409 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/kernelshard/otelkit/internal/config"
+)
+
+// persistentQueueExt is the file extension of spilled batches. Files are named by a
+// zero-padded sequence number so that lexical order is replay order.
+const persistentQueueExt = ".otlp.json"
+
+// errBatchTooLarge is returned when a single batch exceeds the queue's disk budget.
+var errBatchTooLarge = errors.New("batch exceeds persistent queue size limit")
+
+// persistentExporter wraps an exporter with a disk-backed queue. Batches that fail
+// to export with a retryable error are written to dir as OTLP/JSON files instead of
+// being dropped, and a background goroutine replays them in their original order
+// once the endpoint accepts exports again, woken by every spill and periodically.
+// New batches are spilled behind pending ones while the queue is non-empty, so
+// ordering is preserved and live exports never wait for the backlog.
+//
+// Batches the collector rejects, such as malformed or oversized ones, are not
+// queued, and a queued batch it rejects on replay is discarded, so that a single
+// poison batch cannot block the queue.
+//
+// The queue survives restarts because its state is the directory contents. When
+// spilling a batch would exceed maxBytes, the oldest batches are discarded first.
+//
+// Export outcomes are counted by tracker: a spilled batch counts as failed once,
+// when it is spilled, and as exported once it has been replayed.
+type persistentExporter struct {
+	exporter      sdktrace.SpanExporter
+	tracker       *exportTracker // nil if outcomes are not counted
+	dir           string
+	maxBytes      int64
+	exportTimeout time.Duration // Bounds each replayed export
+
+	mu      sync.Mutex
+	pending []queuedBatch // Oldest first
+	size    int64
+	nextSeq uint64
+	stopped bool
+
+	wake   chan struct{} // Signals the replay goroutine that batches were spilled
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// queuedBatch is a spilled batch on disk.
+type queuedBatch struct {
+	name string
+	size int64
+}
+
+// newPersistentExporter creates the queue directory if needed and picks up batches
+// left behind by a previous process. A zero maxBytes disables the size limit and a
+// non-positive retryInterval disables periodic replay; batches are still replayed
+// whenever a new batch is spilled. Replayed exports are bounded by exportTimeout, the
+// export timeout of the destination's batch processor (zero keeps the default).
+// tracker may be nil.
+func newPersistentExporter(exporter sdktrace.SpanExporter, dir string, maxBytes int64, retryInterval, exportTimeout time.Duration, tracker *exportTracker) (*persistentExporter, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+	if exportTimeout <= 0 {
+		exportTimeout = config.DefaultExportTimeout
+	}
+	e := &persistentExporter{
+		exporter:      exporter,
+		tracker:       tracker,
+		dir:           dir,
+		maxBytes:      maxBytes,
+		exportTimeout: exportTimeout,
+		wake:          make(chan struct{}, 1),
+		done:          make(chan struct{}),
+	}
+	if err := e.scan(); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	e.cancel = cancel
+	go e.replayLoop(ctx, retryInterval)
+	if len(e.pending) > 0 {
+		e.signal()
+	}
+	return e, nil
+}
+
+// scan loads the pending batches from disk and removes partially written files.
+func (e *persistentExporter) scan() error {
+	entries, err := os.ReadDir(e.dir)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		if !entry.Type().IsRegular() {
+			continue
+		}
+		if strings.HasSuffix(name, ".tmp") {
+			_ = os.Remove(filepath.Join(e.dir, name))
+			continue
+		}
+		seq, err := strconv.ParseUint(strings.TrimSuffix(name, persistentQueueExt), 10, 64)
+		if err != nil || !strings.HasSuffix(name, persistentQueueExt) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		e.pending = append(e.pending, queuedBatch{name: name, size: info.Size()})
+		e.size += info.Size()
+		if seq >= e.nextSeq {
+			e.nextSeq = seq + 1
+		}
+	}
+	sort.Slice(e.pending, func(i, j int) bool { return e.pending[i].name < e.pending[j].name })
+	return nil
+}
+
+// ExportSpans exports spans, or spills them behind the pending batches if there are
+// any. If the endpoint is unavailable the batch is spilled to disk and nil is
+// returned; the export error is reported through the OpenTelemetry error handler
+// instead. Errors of batches the collector rejects are returned.
+func (e *persistentExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	if len(spans) == 0 {
+		return nil
+	}
+
+	e.mu.Lock()
+	if e.stopped {
+		e.mu.Unlock()
+		return nil
+	}
+	if len(e.pending) > 0 {
+		err := e.spill(spans)
+		e.mu.Unlock()
+		e.tracker.recordFailure(len(spans), err)
+		e.signal()
+		return err
+	}
+	e.mu.Unlock()
+
+	rejected, err := e.export(ctx, spans)
+	if err == nil {
+		e.tracker.recordSuccess(len(spans))
+		return nil
+	}
+	e.tracker.recordFailure(len(spans), err)
+	if rejected {
+		return err
+	}
+	otel.Handle(fmt.Errorf("otelkit: persistent queue: export failed, spilling batch to disk: %w", err))
+	e.mu.Lock()
+	err = e.spill(spans)
+	e.mu.Unlock()
+	e.signal()
+	return err
+}
+
+// Shutdown stops background replay, makes a final replay attempt bounded by ctx and
+// shuts down the wrapped exporter. Batches that could not be delivered stay on disk
+// for the next process.
+func (e *persistentExporter) Shutdown(ctx context.Context) error {
+	e.mu.Lock()
+	if e.stopped {
+		e.mu.Unlock()
+		return nil
+	}
+	e.stopped = true
+	e.mu.Unlock()
+
+	e.cancel()
+	<-e.done
+	_ = e.replay(ctx)
+
+	return e.exporter.Shutdown(ctx)
+}
+
+// signal wakes the replay goroutine without blocking.
+func (e *persistentExporter) signal() {
+	select {
+	case e.wake <- struct{}{}:
+	default:
+	}
+}
+
+// replay exports pending batches oldest first and stops at the first batch that
+// fails with a retryable error. Batches that cannot be read back or that the
+// collector rejects are discarded. e.mu is not held while exporting, so batches can
+// be spilled meanwhile; only the replay goroutine and Shutdown, after stopping it,
+// call replay.
+func (e *persistentExporter) replay(ctx context.Context) error {
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		e.mu.Lock()
+		if len(e.pending) == 0 {
+			e.mu.Unlock()
+			return nil
+		}
+		batch := e.pending[0]
+		e.mu.Unlock()
+
+		path := filepath.Join(e.dir, batch.name)
+		spans, err := readQueuedBatch(path)
+		switch {
+		case errors.Is(err, os.ErrNotExist):
+			// Evicted by a concurrent spill.
+		case err != nil:
+			otel.Handle(fmt.Errorf("otelkit: persistent queue: discarding unreadable batch %s: %w", batch.name, err))
+		default:
+			exportCtx, cancel := context.WithTimeout(ctx, e.exportTimeout)
+			var rejected bool
+			rejected, err = e.export(exportCtx, spans)
+			cancel()
+			if err == nil {
+				e.tracker.recordSuccess(len(spans))
+			} else {
+				e.tracker.recordError(err)
+			}
+			if err != nil && !rejected {
+				return err
+			}
+			if err != nil {
+				otel.Handle(fmt.Errorf("otelkit: persistent queue: discarding batch %s rejected by the collector: %w", batch.name, err))
+			}
+		}
+
+		e.mu.Lock()
+		err = e.remove(batch)
+		e.mu.Unlock()
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// remove deletes batch from the head of the queue unless it has been evicted
+// already. The caller must hold e.mu.
+func (e *persistentExporter) remove(batch queuedBatch) error {
+	if len(e.pending) == 0 || e.pending[0].name != batch.name {
+		return nil
+	}
+	if err := os.Remove(filepath.Join(e.dir, batch.name)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	e.pending = e.pending[1:]
+	e.size -= batch.size
+	return nil
+}
+
+// spill writes spans to a new queue file, evicting the oldest batches if the size
+// limit would be exceeded. The caller must hold e.mu.
+func (e *persistentExporter) spill(spans []sdktrace.ReadOnlySpan) error {
+	data, err := json.Marshal(encodeOTLPTraceData(spans))
+	if err != nil {
+		return err
+	}
+	size := int64(len(data))
+	if e.maxBytes > 0 && size > e.maxBytes {
+		return errBatchTooLarge
+	}
+
+	for e.maxBytes > 0 && e.size+size > e.maxBytes && len(e.pending) > 0 {
+		oldest := e.pending[0]
+		if err := e.remove(oldest); err != nil {
+			return err
+		}
+		otel.Handle(fmt.Errorf("otelkit: persistent queue: size limit reached, dropped batch %s", oldest.name))
+	}
+
+	// Write to a temporary file first so a crash never leaves a truncated batch.
+	name := fmt.Sprintf("%020d%s", e.nextSeq, persistentQueueExt)
+	path := filepath.Join(e.dir, name)
+	if err := os.WriteFile(path+".tmp", data, 0o600); err != nil {
+		return err
+	}
+	if err := os.Rename(path+".tmp", path); err != nil {
+		return err
+	}
+	e.nextSeq++
+	e.pending = append(e.pending, queuedBatch{name: name, size: size})
+	e.size += size
+	return nil
+}
+
+// replayLoop replays pending batches whenever a batch is spilled and every interval,
+// so the queue drains after an outage even when no new spans are produced. It
+// returns when ctx is cancelled.
+func (e *persistentExporter) replayLoop(ctx context.Context, interval time.Duration) {
+	defer close(e.done)
+	var tick <-chan time.Time
+	if interval > 0 {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-e.wake:
+		case <-tick:
+		}
+		_ = e.replay(ctx)
+	}
+}
+
+// export exports spans with the wrapped exporter and reports whether a failure means
+// that the collector rejected the batch.
+func (e *persistentExporter) export(ctx context.Context, spans []sdktrace.ReadOnlySpan) (bool, error) {
+	var recorded exportStatus
+	err := e.exporter.ExportSpans(context.WithValue(ctx, exportStatusKey{}, &recorded), spans)
+	return err != nil && exportRejected(err, recorded.code), err
+}
+
+// exportStatusKey is the context key of the exportStatus of an export.
+type exportStatusKey struct{}
+
+// exportStatus holds the HTTP status of the last response to an export, recorded by
+// statusRecordingTransport. It stays zero for other exporters and when the last
+// request got no response.
+type exportStatus struct {
+	code int
+}
+
+// statusRecordingTransport records the response status of OTLP/HTTP requests in the
+// exportStatus of their context. The OTLP/HTTP exporter reports rejections only in
+// its error text, so the status is taken from the response instead.
+type statusRecordingTransport struct {
+	base http.RoundTripper
+}
+
+func (t statusRecordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	if s, ok := req.Context().Value(exportStatusKey{}).(*exportStatus); ok {
+		s.code = 0
+		if err == nil {
+			s.code = resp.StatusCode
+		}
+	}
+	return resp, err
+}
+
+// exportRejected reports whether err means that the collector rejected the batch
+// itself, so that sending it again cannot succeed: a gRPC status the OTLP exporter
+// does not retry, or an HTTP error status other than 429, 502, 503 and 504 in
+// httpStatus, the status recorded by statusRecordingTransport. Connection failures
+// and timeouts are retryable.
+func exportRejected(err error, httpStatus int) bool {
+	if s, ok := status.FromError(err); ok {
+		switch s.Code() {
+		case codes.Canceled, codes.DeadlineExceeded, codes.Aborted, codes.OutOfRange,
+			codes.Unavailable, codes.DataLoss:
+			return false
+		case codes.ResourceExhausted:
+			// Throttling carries a retry delay; without one the message was too large.
+			for _, detail := range s.Details() {
+				if _, ok := detail.(*errdetails.RetryInfo); ok {
+					return false
+				}
+			}
+			return true
+		default:
+			return true
+		}
+	}
+	switch httpStatus {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return false
+	}
+	return httpStatus >= http.StatusBadRequest
+}
+
+// readQueuedBatch decodes a spilled batch.
+func readQueuedBatch(path string) ([]sdktrace.ReadOnlySpan, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var td otlpTraceData
+	if err := json.Unmarshal(data, &td); err != nil {
+		return nil, err
+	}
+	return decodeOTLPTraceData(td)
+}
@@ -0,0 +1,424 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/kernelshard/otelkit/internal/config"
+)
+
+// flakyExporter records the trace ID of every exported batch and fails while down.
+type flakyExporter struct {
+	mu       sync.Mutex
+	down     bool
+	batches  []trace.TraceID
+	attempts int
+}
+
+func (e *flakyExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.attempts++
+	if e.down {
+		return errors.New("collector unavailable")
+	}
+	e.batches = append(e.batches, spans[0].SpanContext().TraceID())
+	return nil
+}
+
+func (e *flakyExporter) Shutdown(ctx context.Context) error { return nil }
+
+func (e *flakyExporter) setDown(down bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.down = down
+}
+
+func (e *flakyExporter) exportAttempts() int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.attempts
+}
+
+func (e *flakyExporter) exported() []trace.TraceID {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]trace.TraceID(nil), e.batches...)
+}
+
+// rejectingExporter rejects the batches of the given traces once the collector is up
+// and records the others.
+type rejectingExporter struct {
+	flakyExporter
+	rejected map[trace.TraceID]bool
+	err      error
+}
+
+func (e *rejectingExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	e.mu.Lock()
+	down := e.down
+	e.mu.Unlock()
+	if !down && e.rejected[spans[0].SpanContext().TraceID()] {
+		return e.err
+	}
+	return e.flakyExporter.ExportSpans(ctx, spans)
+}
+
+// waitExported waits until the exporter has exported n batches.
+func waitExported(t *testing.T, e *flakyExporter, n int) []trace.TraceID {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for len(e.exported()) < n && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	return e.exported()
+}
+
+func queuedFiles(t *testing.T, dir string) []string {
+	t.Helper()
+	matches, err := filepath.Glob(filepath.Join(dir, "*"+persistentQueueExt))
+	if err != nil {
+		t.Fatalf("Glob failed: %v", err)
+	}
+	return matches
+}
+
+func TestPersistentExporter_SpillAndReplayInOrder(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	inner := &flakyExporter{down: true}
+
+	e, err := newPersistentExporter(inner, dir, 0, 0, 0, nil)
+	if err != nil {
+		t.Fatalf("newPersistentExporter failed: %v", err)
+	}
+	defer e.Shutdown(ctx)
+
+	var want []trace.TraceID
+	for i := 0; i < 2; i++ {
+		batch := testSpans(t)
+		want = append(want, batch[0].SpanContext().TraceID())
+		if err := e.ExportSpans(ctx, batch); err != nil {
+			t.Fatalf("Expected failed export to be spilled, got %v", err)
+		}
+	}
+	if got := len(queuedFiles(t, dir)); got != 2 {
+		t.Fatalf("Expected 2 spilled batches, got %d", got)
+	}
+
+	// The new batch is queued behind the pending ones, which the replay goroutine
+	// then delivers in order.
+	inner.setDown(false)
+	batch := testSpans(t)
+	want = append(want, batch[0].SpanContext().TraceID())
+	if err := e.ExportSpans(ctx, batch); err != nil {
+		t.Fatalf("ExportSpans failed: %v", err)
+	}
+
+	got := waitExported(t, inner, len(want))
+	if len(got) != len(want) {
+		t.Fatalf("Expected %d exported batches, got %d", len(want), len(got))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Batch %d replayed out of order", i)
+		}
+	}
+	_ = e.Shutdown(ctx)
+	if files := queuedFiles(t, dir); len(files) != 0 {
+		t.Errorf("Expected queue to be drained, %d files left", len(files))
+	}
+}
+
+func TestPersistentExporter_SurvivesRestart(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	first, err := newPersistentExporter(&flakyExporter{down: true}, dir, 0, 0, 0, nil)
+	if err != nil {
+		t.Fatalf("newPersistentExporter failed: %v", err)
+	}
+	batch := testSpans(t)
+	if err := first.ExportSpans(ctx, batch); err != nil {
+		t.Fatalf("ExportSpans failed: %v", err)
+	}
+	if err := first.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown failed: %v", err)
+	}
+
+	// A partially written batch from a crash must be ignored and cleaned up.
+	partial := filepath.Join(dir, "00000000000000000099"+persistentQueueExt+".tmp")
+	if err := os.WriteFile(partial, []byte("{"), 0o600); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+
+	inner := &flakyExporter{}
+	second, err := newPersistentExporter(inner, dir, 0, 10*time.Millisecond, 0, nil)
+	if err != nil {
+		t.Fatalf("newPersistentExporter failed: %v", err)
+	}
+	defer second.Shutdown(ctx)
+
+	// Background replay delivers the batch without any new spans being exported.
+	got := waitExported(t, inner, 1)
+	if len(got) != 1 || got[0] != batch[0].SpanContext().TraceID() {
+		t.Fatalf("Expected the spilled batch to be replayed after restart, got %v", got)
+	}
+	if _, err := os.Stat(partial); !os.IsNotExist(err) {
+		t.Error("Expected partial batch file to be removed")
+	}
+}
+
+func TestPersistentExporter_SizeLimitDropsOldest(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	inner := &flakyExporter{down: true}
+
+	// Measure one batch to size the budget for exactly two of them.
+	probe, err := newPersistentExporter(inner, filepath.Join(dir, "probe"), 0, 0, 0, nil)
+	if err != nil {
+		t.Fatalf("newPersistentExporter failed: %v", err)
+	}
+	_ = probe.ExportSpans(ctx, testSpans(t))
+	_ = probe.Shutdown(ctx)
+	limit := probe.size*2 + probe.size/2
+
+	e, err := newPersistentExporter(inner, filepath.Join(dir, "queue"), limit, 0, 0, nil)
+	if err != nil {
+		t.Fatalf("newPersistentExporter failed: %v", err)
+	}
+	var traceIDs []trace.TraceID
+	for i := 0; i < 3; i++ {
+		batch := testSpans(t)
+		traceIDs = append(traceIDs, batch[0].SpanContext().TraceID())
+		if err := e.ExportSpans(ctx, batch); err != nil {
+			t.Fatalf("ExportSpans failed: %v", err)
+		}
+	}
+	if got := len(queuedFiles(t, e.dir)); got != 2 {
+		t.Fatalf("Expected 2 batches within the size limit, got %d", got)
+	}
+
+	inner.setDown(false)
+	if err := e.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown failed: %v", err)
+	}
+	got := inner.exported()
+	if len(got) != 2 || got[0] != traceIDs[1] || got[1] != traceIDs[2] {
+		t.Errorf("Expected the two newest batches to be replayed on shutdown, got %v", got)
+	}
+}
+
+func TestPersistentExporter_RejectedBatches(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	poison, live := testSpans(t), testSpans(t)
+	inner := &rejectingExporter{
+		flakyExporter: flakyExporter{down: true},
+		rejected:      map[trace.TraceID]bool{poison[0].SpanContext().TraceID(): true},
+		err:           status.Error(codes.InvalidArgument, "invalid span"),
+	}
+	e, err := newPersistentExporter(inner, dir, 0, 0, 0, nil)
+	if err != nil {
+		t.Fatalf("newPersistentExporter failed: %v", err)
+	}
+
+	// While the collector is down the poison batch is queued like any other.
+	if err := e.ExportSpans(ctx, poison); err != nil {
+		t.Fatalf("ExportSpans failed: %v", err)
+	}
+	if err := e.ExportSpans(ctx, live); err != nil {
+		t.Fatalf("ExportSpans failed: %v", err)
+	}
+
+	// Once it is up, the rejected batch is discarded instead of blocking the queue.
+	inner.setDown(false)
+	if err := e.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown failed: %v", err)
+	}
+	if got := inner.exported(); len(got) != 1 || got[0] != live[0].SpanContext().TraceID() {
+		t.Errorf("Expected the batch behind the rejected one to be delivered, got %v", got)
+	}
+	if files := queuedFiles(t, dir); len(files) != 0 {
+		t.Errorf("Expected queue to be drained, %d files left", len(files))
+	}
+
+	// A batch rejected on the first attempt is not spilled, and the error is returned.
+	e, err = newPersistentExporter(inner, dir, 0, 0, 0, nil)
+	if err != nil {
+		t.Fatalf("newPersistentExporter failed: %v", err)
+	}
+	defer e.Shutdown(ctx)
+	if err := e.ExportSpans(ctx, poison); err == nil {
+		t.Error("Expected the rejection to be returned")
+	}
+	if files := queuedFiles(t, dir); len(files) != 0 {
+		t.Errorf("Expected the rejected batch not to be spilled, %d files queued", len(files))
+	}
+}
+
+func TestPersistentExporter_LiveExportsDoNotWaitForReplay(t *testing.T) {
+	ctx := context.Background()
+	inner := &blockingExporter{release: make(chan struct{})}
+	e, err := newPersistentExporter(inner, t.TempDir(), 0, 0, 0, nil)
+	if err != nil {
+		t.Fatalf("newPersistentExporter failed: %v", err)
+	}
+	defer func() {
+		close(inner.release)
+		_ = e.Shutdown(ctx)
+	}()
+
+	// Queue a batch and let the replay goroutine get stuck exporting it.
+	inner.fail.Store(true)
+	if err := e.ExportSpans(ctx, testSpans(t)); err != nil {
+		t.Fatalf("ExportSpans failed: %v", err)
+	}
+	inner.fail.Store(false)
+	inner.block.Store(true)
+	e.signal()
+	deadline := time.Now().Add(2 * time.Second)
+	for inner.blocked.Load() == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- e.ExportSpans(ctx, testSpans(t)) }()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("ExportSpans failed: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected a live export to be queued without waiting for the replay")
+	}
+}
+
+// blockingExporter fails while fail is set and blocks until release is closed while
+// block is set.
+type blockingExporter struct {
+	fail    atomic.Bool
+	block   atomic.Bool
+	blocked atomic.Int32
+	release chan struct{}
+}
+
+func (e *blockingExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	if e.fail.Load() {
+		return errors.New("collector unavailable")
+	}
+	if e.block.Load() {
+		e.blocked.Add(1)
+		<-e.release
+	}
+	return nil
+}
+
+func (e *blockingExporter) Shutdown(ctx context.Context) error { return nil }
+
+func TestExportRejected(t *testing.T) {
+	httpErr := errors.New("traces export: failed to send to http://collector:4318/v1/traces")
+	tests := []struct {
+		name       string
+		err        error
+		httpStatus int
+		want       bool
+	}{
+		{"connection refused", errors.New("traces export: Post \"http://collector:4318/v1/traces\": dial tcp: connection refused"), 0, false},
+		{"retryable http status", httpErr, http.StatusServiceUnavailable, false},
+		{"http throttled", httpErr, http.StatusTooManyRequests, false},
+		{"http bad request", httpErr, http.StatusBadRequest, true},
+		{"http payload too large", httpErr, http.StatusRequestEntityTooLarge, true},
+		{"grpc unavailable", fmt.Errorf("traces export: %w", status.Error(codes.Unavailable, "connection refused")), 0, false},
+		{"grpc invalid argument", fmt.Errorf("traces export: %w", status.Error(codes.InvalidArgument, "bad span")), 0, true},
+		{"grpc message too large", status.Error(codes.ResourceExhausted, "received message larger than max"), 0, true},
+		{"deadline", context.DeadlineExceeded, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := exportRejected(tt.err, tt.httpStatus); got != tt.want {
+				t.Errorf("exportRejected(%v, %d) = %v, want %v", tt.err, tt.httpStatus, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPersistentQueue_HTTPStatusFromCollector(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	var code atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(int(code.Load()))
+	}))
+	defer srv.Close()
+
+	pc := NewProviderConfig("queue-service", "1.0.0").
+		WithOTLPExporter(srv.URL, config.ProtocolHTTP, true).
+		WithPersistentQueue(dir, 0).
+		WithoutRetry()
+	exporter, err := createExporter(ctx, pc, nil)
+	if err != nil {
+		t.Fatalf("createExporter failed: %v", err)
+	}
+	defer exporter.Shutdown(ctx)
+
+	code.Store(http.StatusBadRequest)
+	if err := exporter.ExportSpans(ctx, testSpans(t)); err == nil {
+		t.Error("Expected a 400 response to be returned as a rejection")
+	}
+	if files := queuedFiles(t, dir); len(files) != 0 {
+		t.Errorf("Expected the rejected batch not to be spilled, %d files queued", len(files))
+	}
+
+	code.Store(http.StatusServiceUnavailable)
+	if err := exporter.ExportSpans(ctx, testSpans(t)); err != nil {
+		t.Errorf("Expected a 503 response to spill the batch, got %v", err)
+	}
+	if files := queuedFiles(t, dir); len(files) != 1 {
+		t.Errorf("Expected the batch to be spilled, %d files queued", len(files))
+	}
+}
+
+func TestCreateExporter_PersistentQueue(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	pc := NewProviderConfig("queue-service", "1.0.0").
+		WithOTLPExporter("localhost:4318", config.ProtocolHTTP, true).
+		WithPersistentQueue(dir, 1<<20)
+	pc.ExportTimeout = 2 * time.Second
+
+	exporter, err := createExporter(ctx, pc, nil)
+	if err != nil {
+		t.Fatalf("createExporter failed: %v", err)
+	}
+	defer exporter.Shutdown(ctx)
+	if queue, ok := exporter.(*persistentExporter); !ok {
+		t.Errorf("Expected a persistent exporter, got %T", exporter)
+	} else if queue.exportTimeout != 2*time.Second {
+		t.Errorf("Expected replays to use the export timeout, got %v", queue.exportTimeout)
+	}
+
+	pc.WithConsoleExporter(nil, false)
+	console, err := createExporter(ctx, pc, nil)
+	if err != nil {
+		t.Fatalf("createExporter failed: %v", err)
+	}
+	if _, ok := console.(*persistentExporter); ok {
+		t.Error("Expected local exporters not to be wrapped in a persistent queue")
+	}
+}
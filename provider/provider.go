@@ -5,19 +5,21 @@ package provider
 
 import (
 	"context"
-	"sync"
+	"io"
+	"log/slog"
 	"time"
 
-	"go.opentelemetry.io/otel"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
 	sdkresource "go.opentelemetry.io/otel/sdk/resource"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 
 	"github.com/kernelshard/otelkit/internal/config"
 )
 
-// setOnce ensures the global tracer provider is set only once across all
-// provider creation calls, preventing conflicts in multi-initialization scenarios.
-var setOnce sync.Once
+// ExporterConfig describes an additional export destination for fan-out.
+// See ProviderConfig.WithAdditionalExporter.
+type ExporterConfig = config.ExporterConfig
 
 // ProviderConfig holds comprehensive configuration for creating a TracerProvider.
 // It combines basic tracing configuration with advanced options for batch processing,
@@ -56,6 +58,40 @@ type ProviderConfig struct {
 	// MaxQueueSize is the maximum number of spans that can be queued for export.
 	// When the queue is full, new spans will be dropped. Default: 2048.
 	MaxQueueSize int
+
+	// ConsoleWriter is the destination for the console exporter protocols
+	// ("stdout", "console" and "stdout_json"). If nil, os.Stdout is used.
+	ConsoleWriter io.Writer
+
+	// TailSampling enables tail-based sampling in front of the exporters when set.
+	// See WithTailSampling.
+	TailSampling *TailSamplingConfig
+
+	// RedactionRules are applied to every span before export when set.
+	// See WithRedaction.
+	RedactionRules []RedactionRule
+
+	// DynamicSampling makes the sampling type and ratio adjustable at runtime.
+	// See WithDynamicSampling.
+	DynamicSampling bool
+
+	// SamplingAuditLogger receives a log line for every runtime sampling change.
+	// If nil, slog.Default() is used.
+	SamplingAuditLogger *slog.Logger
+
+	// SkipGlobalRegistration keeps New and NewProvider from installing the provider
+	// as the OpenTelemetry global tracer provider, and New and NewMeterProvider from
+	// installing the global meter and logger providers. See WithoutGlobalRegistration.
+	SkipGlobalRegistration bool
+
+	// MetricReaders are attached to the meter provider in addition to the periodic
+	// OTLP reader, e.g. a ManualReader in tests. See WithMetricReader.
+	MetricReaders []sdkmetric.Reader
+
+	// LogProcessors are attached to the logger provider in addition to the batch OTLP
+	// processor, e.g. a simple processor with an in-memory exporter in tests. See
+	// WithLogProcessor.
+	LogProcessors []sdklog.Processor
 }
 
 // NewProviderConfig creates a new ProviderConfig with sensible defaults for advanced configuration.
@@ -91,12 +127,22 @@ func NewProviderConfig(serviceName, serviceVersion string) *ProviderConfig {
 //
 // Parameters:
 //   - endpoint: The URL or address of the OTLP collector (e.g., "localhost:4317", "https://api.honeycomb.io")
-//   - protocol: Either "grpc" for gRPC transport or "http" for HTTP transport
+//   - protocol: Either "grpc" for gRPC transport or "http" for HTTP transport. The
+//     console protocols "stdout", "console" and "stdout_json" and the "file" protocol
+//     are also accepted; they ignore endpoint and insecure (see WithConsoleExporter
+//     and WithFileExporter)
 //   - insecure: true to disable TLS (for development), false to use TLS (for production)
 //
+// The endpoint may be a bare "host:port" or a full URL. For URLs the scheme decides
+// between TLS (https) and plaintext (http) regardless of insecure. The endpoint is
+// the base endpoint of all signals like OTEL_EXPORTER_OTLP_ENDPOINT: OTLP/HTTP sends
+// spans to its path followed by /v1/traces, and metrics and logs to /v1/metrics and
+// /v1/logs. Use WithTracesEndpoint for a traces URL whose path is used as-is.
+//
 // Example:
 //
 //	config.WithOTLPExporter("https://api.honeycomb.io", "http", false)
+//	config.WithOTLPExporter("https://gateway.example.com/otlp", "http", false) // spans to /otlp/v1/traces
 //	config.WithOTLPExporter("localhost:4317", "grpc", true)  // Development
 func (pc *ProviderConfig) WithOTLPExporter(endpoint, protocol string, insecure bool) *ProviderConfig {
 	pc.Config.OTLPExporterEndpoint = endpoint
@@ -105,15 +151,241 @@ func (pc *ProviderConfig) WithOTLPExporter(endpoint, protocol string, insecure b
 	return pc
 }
 
+// WithTracesEndpoint sets the endpoint spans are exported to, overriding the endpoint
+// of WithOTLPExporter for traces like OTEL_EXPORTER_OTLP_TRACES_ENDPOINT. The path of
+// a URL is used as-is; metrics and logs still use the base endpoint.
+//
+// Example:
+//
+//	config.WithOTLPExporter("https://collector.example.com", "http", false).
+//	    WithTracesEndpoint("https://collector.example.com/custom/v1/traces")
+func (pc *ProviderConfig) WithTracesEndpoint(endpoint string) *ProviderConfig {
+	pc.Config.OTLPTracesEndpoint = endpoint
+	return pc
+}
+
+// WithHeaders sets extra headers sent with every OTLP export request, typically the
+// API key required by a SaaS backend. Header values are redacted whenever the
+// configuration is printed or reported in an error.
+//
+// Example:
+//
+//	config.WithHeaders(map[string]string{"x-honeycomb-team": apiKey})
+func (pc *ProviderConfig) WithHeaders(headers map[string]string) *ProviderConfig {
+	pc.Config.OTLPExporterHeaders = headers
+	return pc
+}
+
+// WithCompression sets the OTLP payload compression, either "gzip" or "none".
+// Gzip considerably reduces egress for large batches at a small CPU cost.
+//
+// Example:
+//
+//	config.WithCompression("gzip")
+func (pc *ProviderConfig) WithCompression(compression string) *ProviderConfig {
+	pc.Config.OTLPCompression = compression
+	return pc
+}
+
+// WithOTLPTimeout sets the timeout for a single OTLP export request, including
+// retries. Unlike the batch ExportTimeout it is enforced by the exporter itself.
+//
+// Example:
+//
+//	config.WithOTLPTimeout(5 * time.Second)
+func (pc *ProviderConfig) WithOTLPTimeout(timeout time.Duration) *ProviderConfig {
+	pc.Config.OTLPTimeout = timeout
+	return pc
+}
+
+// WithRetry sets the retry policy of the OTLP exporters. Failed exports caused by
+// retryable errors (unavailable collector, throttling) are retried with exponential
+// backoff starting at initialInterval and capped at maxInterval; a batch is dropped
+// once maxElapsed has passed since its first attempt. Zero values keep the defaults
+// of 5s, 30s and 1m.
+//
+// Note that retries hold a batch processor's export goroutine, so maxElapsed should
+// stay below ExportTimeout for retries to take effect.
+//
+// Example:
+//
+//	config.WithRetry(500*time.Millisecond, 5*time.Second, 20*time.Second)
+func (pc *ProviderConfig) WithRetry(initialInterval, maxInterval, maxElapsed time.Duration) *ProviderConfig {
+	pc.Config.RetryDisabled = false
+	pc.Config.RetryInitialInterval = initialInterval
+	pc.Config.RetryMaxInterval = maxInterval
+	pc.Config.RetryMaxElapsedTime = maxElapsed
+	return pc
+}
+
+// WithoutRetry disables export retries: each batch is sent once and dropped if the
+// export fails. Useful for latency-sensitive sidecars that prefer losing spans over
+// queueing them behind a slow collector.
+//
+// Example:
+//
+//	config.WithoutRetry()
+func (pc *ProviderConfig) WithoutRetry() *ProviderConfig {
+	pc.Config.RetryDisabled = true
+	return pc
+}
+
+// WithTLS configures TLS for the OTLP exporters, for collectors that use a private CA
+// or require mutual TLS. Any argument may be empty: caFile alone verifies the
+// collector against a custom CA bundle, certFile and keyFile together enable mTLS.
+// The files are checked for changes once per Config.TLSReloadInterval (default: 1
+// minute) so rotated certificates are picked up without a restart.
+//
+// TLS settings are ignored when the exporter is configured as insecure.
+//
+// Example:
+//
+//	config.WithOTLPExporter("collector.internal:4317", "grpc", false).
+//	    WithTLS("/etc/otel/ca.pem", "/etc/otel/client.pem", "/etc/otel/client-key.pem")
+func (pc *ProviderConfig) WithTLS(caFile, certFile, keyFile string) *ProviderConfig {
+	pc.Config.TLSCAFile = caFile
+	pc.Config.TLSClientCertFile = certFile
+	pc.Config.TLSClientKeyFile = keyFile
+	return pc
+}
+
+// WithPersistentQueue enables a disk-backed queue for the OTLP exporters. Batches
+// that fail with a retryable error, for example while the collector restarts, are
+// written to dir instead of being dropped and are replayed in order in the
+// background, including after a process restart. Batches the collector rejects
+// outright are never queued, and a queued batch that is rejected on replay is
+// discarded so it cannot block the ones behind it. When the queue would grow beyond
+// maxSize bytes the oldest batches are discarded; zero means no limit.
+//
+// Provider.Stats and the health checker see the exports behind the queue: a spilled
+// batch counts as a failed export and a replayed one as exported.
+//
+// Additional exporters use a subdirectory named after the exporter. The directory
+// must not be shared between processes.
+//
+// Example:
+//
+//	config.WithPersistentQueue("/var/lib/myservice/otel-queue", 256<<20)
+func (pc *ProviderConfig) WithPersistentQueue(dir string, maxSize int64) *ProviderConfig {
+	pc.Config.PersistentQueueDir = dir
+	pc.Config.PersistentQueueMaxSize = maxSize
+	return pc
+}
+
+// WithConsoleExporter configures a console exporter for local development, so spans
+// can be inspected without running a collector. The default format pretty-prints each
+// trace as an indented span tree with durations, status and attributes; set jsonLines
+// to write one compact JSON object per span instead, which is convenient for jq.
+//
+// If w is nil, spans are written to os.Stdout.
+//
+// Example:
+//
+//	config.WithConsoleExporter(nil, false)        // Pretty trees on stdout
+//	config.WithConsoleExporter(os.Stderr, true)   // JSON lines on stderr
+func (pc *ProviderConfig) WithConsoleExporter(w io.Writer, jsonLines bool) *ProviderConfig {
+	pc.Config.OTLPExporterProtocol = config.ProtocolConsole
+	if jsonLines {
+		pc.Config.OTLPExporterProtocol = config.ProtocolStdoutJSON
+	}
+	pc.ConsoleWriter = w
+	return pc
+}
+
+// WithFileExporter configures a file exporter that writes spans as OTLP/JSON lines,
+// for environments without network access. The resulting files can be replayed into
+// a collector later using an otlpjson-capable receiver. Pending spans are flushed and
+// the file is closed by ShutdownTracerProvider.
+//
+// Parameters:
+//   - path: Target file; rotated files are written next to it with a timestamp suffix
+//   - maxSize: Rotate once the file would grow beyond this many bytes (0 disables)
+//   - maxAge: Rotate once the file is older than this duration (0 disables)
+//   - maxBackups: Number of rotated files to keep (0 keeps all)
+//
+// Example:
+//
+//	config.WithFileExporter("/var/spool/otel/traces.jsonl", 50<<20, time.Hour, 10)
+func (pc *ProviderConfig) WithFileExporter(path string, maxSize int64, maxAge time.Duration, maxBackups int) *ProviderConfig {
+	pc.Config.OTLPExporterProtocol = config.ProtocolFile
+	pc.Config.FileExporterPath = path
+	pc.Config.FileExporterMaxSize = maxSize
+	pc.Config.FileExporterMaxAge = maxAge
+	pc.Config.FileExporterMaxBackups = maxBackups
+	return pc
+}
+
+// WithZipkinExporter configures the Zipkin exporter for backends that only accept
+// the Zipkin v2 JSON format. The service name becomes the span's local endpoint;
+// the remaining resource attributes, such as the service instance ID, are sent as
+// tags, and span kinds map to the Zipkin CLIENT, SERVER, PRODUCER and CONSUMER
+// kinds. Headers configured with WithHeaders are sent with every request, and
+// WithTLS applies to https endpoints.
+//
+// If endpoint is empty, http://localhost:9411/api/v2/spans is used.
+//
+// Example:
+//
+//	config.WithZipkinExporter("http://zipkin.internal:9411/api/v2/spans")
+func (pc *ProviderConfig) WithZipkinExporter(endpoint string) *ProviderConfig {
+	pc.Config.OTLPExporterProtocol = config.ProtocolZipkin
+	if endpoint == "" {
+		endpoint = config.DefaultZipkinEndpoint
+	}
+	pc.Config.ZipkinEndpoint = endpoint
+	return pc
+}
+
+// WithAdditionalExporter adds an export destination that receives the same spans as
+// the primary exporter, e.g. a vendor endpoint during a migration. Each destination
+// has its own protocol, endpoint, headers and batch processor, so a slow or failing
+// backend does not stall the others. Zero-valued batch settings inherit the values
+// configured with WithBatchOptions.
+//
+// Example:
+//
+//	config.WithAdditionalExporter(tracer.ExporterConfig{
+//	    Name:     "vendor",
+//	    Protocol: "grpc",
+//	    Endpoint: "ingest.vendor.example:4317",
+//	    Headers:  map[string]string{"x-api-key": apiKey},
+//	})
+func (pc *ProviderConfig) WithAdditionalExporter(e ExporterConfig) *ProviderConfig {
+	pc.Config.Exporters = append(pc.Config.Exporters, e)
+	return pc
+}
+
+// forExporter returns a copy of the provider configuration targeting the additional
+// exporter e, with its batch settings applied on top of the inherited ones.
+func (pc *ProviderConfig) forExporter(e ExporterConfig) *ProviderConfig {
+	derived := *pc
+	derived.Config = pc.Config.ForExporter(e)
+	if e.BatchTimeout > 0 {
+		derived.BatchTimeout = e.BatchTimeout
+	}
+	if e.ExportTimeout > 0 {
+		derived.ExportTimeout = e.ExportTimeout
+	}
+	if e.MaxExportBatchSize > 0 {
+		derived.MaxExportBatchSize = e.MaxExportBatchSize
+	}
+	if e.MaxQueueSize > 0 {
+		derived.MaxQueueSize = e.MaxQueueSize
+	}
+	return &derived
+}
+
 // WithSampling configures the sampling strategy and ratio for trace collection.
 // Sampling controls what percentage of traces are collected and exported, which is crucial
 // for managing overhead in high-traffic applications.
 //
 // Parameters:
-//   - samplingType: SamplingProbabilistic (ratio-based), SamplingAlwaysOn (100%), or SamplingAlwaysOff (0%)
-//   - ratio: For probabilistic sampling, the ratio of traces to sample (0.0 to 1.0)
+//   - samplingType: SamplingProbabilistic (ratio-based), SamplingAlwaysOn (100%), SamplingAlwaysOff (0%)
+//   - ratio: For probabilistic sampling, the ratio of traces to sample (0.0 to 1.0).
 //     Ignored for "always_on" and "always_off" strategies
 //
+// For rate-limited sampling use WithRateLimitedSampling.
+//
 // Example:
 //
 //	config.WithSampling(config.SamplingProbabilistic, 0.01)  // 1% sampling for production
@@ -125,6 +397,312 @@ func (pc *ProviderConfig) WithSampling(samplingType config.SamplingType, ratio f
 	return pc
 }
 
+// WithRateLimitedSampling samples at most tracesPerSecond root traces per second.
+// Rate-limited sampling keeps the trace volume flat during traffic spikes while still
+// sampling every trace of low-traffic services. Like probabilistic sampling it is
+// parent-based: spans with a parent follow the parent's decision.
+//
+// Example:
+//
+//	config.WithRateLimitedSampling(50) // At most 50 traces per second
+func (pc *ProviderConfig) WithRateLimitedSampling(tracesPerSecond float64) *ProviderConfig {
+	pc.Config.SamplingType = config.SamplingRateLimited
+	pc.Config.SamplingRateLimit = tracesPerSecond
+	return pc
+}
+
+// WithSamplingRules switches to rule-based sampling with the given ordered rules.
+// The first rule matching a root span's name, kind and start attributes decides the
+// ratio at which its trace is sampled; spans matching no rule are sampled at ratio.
+// Child spans follow their parent's decision. Rules from a file configured with
+// WithSamplingRulesFile are evaluated after these.
+//
+// Patterns are globs ('*' also matches '/') or regular expressions prefixed with "re:".
+//
+// Example:
+//
+//	config.WithSamplingRules(0.05,
+//	    provider.SamplingRule{SpanName: "* /checkout*", Ratio: 1},
+//	    provider.SamplingRule{Attributes: map[string]string{"http.url": "*/healthz"}, Ratio: 0},
+//	    provider.SamplingRule{SpanKind: "client", Attributes: map[string]string{"http.method": "re:^(POST|PUT)$"}, Ratio: 0.5},
+//	)
+func (pc *ProviderConfig) WithSamplingRules(ratio float64, rules ...SamplingRule) *ProviderConfig {
+	pc.Config.SamplingType = config.SamplingRuleBased
+	pc.Config.SamplingRatio = ratio
+	pc.Config.SamplingRules = append(pc.Config.SamplingRules, rules...)
+	return pc
+}
+
+// WithSamplingRulesFile switches to rule-based sampling with rules loaded from a JSON
+// or YAML file (see WithSamplingRules). The file has a top-level "rules" list whose
+// entries use the keys span_name, span_kind, attributes and ratio:
+//
+//	rules:
+//	  - span_name: "* /checkout*"
+//	    ratio: 1
+//	  - attributes: {http.url: "*/healthz"}
+//	    ratio: 0
+//
+// The file is read when the provider is created; invalid rules make NewProvider fail.
+// Spans matching no rule are sampled at the configured sampling ratio.
+//
+// Example:
+//
+//	config.WithSamplingRulesFile("/etc/otel/sampling.yaml")
+func (pc *ProviderConfig) WithSamplingRulesFile(path string) *ProviderConfig {
+	pc.Config.SamplingType = config.SamplingRuleBased
+	pc.Config.SamplingRulesFile = path
+	return pc
+}
+
+// WithJaegerRemoteSampling samples with strategies polled from a Jaeger-compatible
+// sampling endpoint (parentbased_jaeger_remote), so sampling is managed centrally per
+// service and operation. The endpoint is queried as endpoint?service=<service name>
+// every pollingInterval and may return a probabilistic, rate-limiting or per-operation
+// strategy. Root spans are sampled by the strategy and child spans follow their parent.
+//
+// Until the first strategy is fetched, e.g. while the endpoint is unreachable at
+// startup, root spans are sampled at fallbackRatio. If the endpoint becomes unreachable
+// later, the last fetched strategy stays in effect for three polls and then root spans
+// are sampled at fallbackRatio again until a fetch succeeds. Fetch errors are reported
+// through the OpenTelemetry error handler.
+//
+// If endpoint is empty, http://localhost:5778/sampling (the Jaeger agent) is used; a
+// zero pollingInterval polls every minute.
+//
+// Example:
+//
+//	config.WithJaegerRemoteSampling("http://jaeger-collector:14268/api/sampling", time.Minute, 0.01)
+func (pc *ProviderConfig) WithJaegerRemoteSampling(endpoint string, pollingInterval time.Duration, fallbackRatio float64) *ProviderConfig {
+	if endpoint == "" {
+		endpoint = config.DefaultJaegerRemoteSamplingEndpoint
+	}
+	if pollingInterval == 0 {
+		pollingInterval = config.DefaultJaegerRemoteSamplingInterval
+	}
+	pc.Config.SamplingType = config.SamplingParentBasedJaegerRemote
+	pc.Config.SamplingRatio = fallbackRatio
+	pc.Config.JaegerRemoteSamplingEndpoint = endpoint
+	pc.Config.JaegerRemoteSamplingInterval = pollingInterval
+	return pc
+}
+
+// WithDynamicSampling makes the sampling configuration adjustable while the service
+// runs, so the ratio can be raised during an incident without a redeploy.
+// Provider.DynamicSampler returns the sampler created by New; use its
+// SetSamplingRatio, SetSamplingRate, SetSamplingType and SetSampling methods or
+// mount its Handler on an admin endpoint.
+// Every change is logged to logger (slog.Default() if nil) and, for changes made
+// through the handler, added as an event to the request's span.
+//
+// Example:
+//
+//	config.WithSampling(config.SamplingProbabilistic, 0.05).WithDynamicSampling(nil)
+//	p, _ := provider.New(ctx, config)
+//	adminMux.Handle("/admin/sampling", p.DynamicSampler().Handler())
+//
+//	p.DynamicSampler().SetSamplingRatio(0.5)
+func (pc *ProviderConfig) WithDynamicSampling(logger *slog.Logger) *ProviderConfig {
+	pc.DynamicSampling = true
+	pc.SamplingAuditLogger = logger
+	return pc
+}
+
+// WithTailSampling enables tail-based sampling: the spans of each trace are buffered
+// for a decision window and the whole trace is exported only if one of the policies
+// keeps it, or otherwise with probability FallbackRatio. Unlike head sampling, this
+// can keep every failed or slow trace while discarding most successful ones.
+//
+// Because the head sampler runs first, WithTailSampling switches it to always_on so
+// that every trace reaches the tail sampler; FallbackRatio takes the place of the
+// sampling ratio. Buffering costs memory proportional to traffic times DecisionWait,
+// bounded by MaxTraces and MaxSpansPerTrace. Traces spanning several services are
+// decided independently by each of them. Provider.TailSampler returns the processor
+// created by New, for reading its Stats.
+//
+// Example:
+//
+//	config.WithTailSampling(provider.TailSamplingConfig{
+//	    DecisionWait: 10 * time.Second,
+//	    Policies: []provider.TailSamplingPolicy{
+//	        provider.KeepErrorTraces(),
+//	        provider.KeepSlowTraces(2 * time.Second),
+//	        provider.KeepTracesWithAttribute(attribute.String("tenant.tier", "enterprise")),
+//	    },
+//	    FallbackRatio: 0.05,
+//	})
+func (pc *ProviderConfig) WithTailSampling(cfg TailSamplingConfig) *ProviderConfig {
+	pc.TailSampling = &cfg
+	pc.Config.SamplingType = config.SamplingAlwaysOn
+	return pc
+}
+
+// WithRedaction removes or obscures sensitive data in spans before they are
+// exported. Rules apply, in order, to span attributes, span event attributes such
+// as exception.message, and span status descriptions. Tail sampling, if enabled,
+// still sees the original spans; only exported spans are redacted.
+//
+// Calling WithRedaction more than once adds rules. DefaultRedactionRules masks
+// email addresses, card numbers, JWTs and common credential query parameters.
+//
+// Example:
+//
+//	config.WithRedaction(provider.DefaultRedactionRules()...).
+//	    WithRedaction(
+//	        provider.RedactQueryParams(provider.RedactDrop, "session"),
+//	        provider.RedactAttributes(provider.RedactHash, "enduser.id"),
+//	    )
+func (pc *ProviderConfig) WithRedaction(rules ...RedactionRule) *ProviderConfig {
+	pc.RedactionRules = append(pc.RedactionRules, rules...)
+	return pc
+}
+
+// WithoutGlobalRegistration keeps the provider from becoming the OpenTelemetry global
+// tracer provider, e.g. for a second provider exporting to a separate backend or for
+// tests running in parallel. Use Provider.Tracer, or Provider.SetAsGlobal later.
+//
+// Example:
+//
+//	audit, err := provider.New(ctx, provider.NewProviderConfig("audit", "v1.0.0").
+//	    WithOTLPExporter("audit-collector:4317", "grpc", false).
+//	    WithoutGlobalRegistration())
+func (pc *ProviderConfig) WithoutGlobalRegistration() *ProviderConfig {
+	pc.SkipGlobalRegistration = true
+	return pc
+}
+
+// WithMetrics enables the metrics pipeline: New creates a meter provider next to the
+// tracer provider that exports over OTLP to the traces endpoint, with /v1/traces
+// replaced by /v1/metrics, and shares its resource, headers, TLS and retry settings.
+// Metrics use gRPC when the trace protocol is "grpc" and HTTP otherwise. Set
+// Config.MetricsEndpoint to export metrics elsewhere.
+//
+// Parameters:
+//   - interval: Time between periodic exports (zero keeps the default of 60 seconds)
+//   - temporality: "cumulative", "delta" or "lowmemory" (empty keeps cumulative)
+//
+// Example:
+//
+//	config := provider.NewProviderConfig("payment-service", "v1.2.3").
+//	    WithOTLPExporter("otel-collector:4317", "grpc", true).
+//	    WithMetrics(15*time.Second, "delta")
+//	p, _ := provider.New(ctx, config)
+//	requests, _ := p.Meter("payments").Int64Counter("payments.requests")
+func (pc *ProviderConfig) WithMetrics(interval time.Duration, temporality string) *ProviderConfig {
+	pc.Config.MetricsExporter = config.MetricsExporterOTLP
+	if interval != 0 {
+		pc.Config.MetricsExportInterval = interval
+	}
+	if temporality != "" {
+		pc.Config.MetricsTemporality = temporality
+	}
+	return pc
+}
+
+// WithMetricReader attaches an additional metric reader to the meter provider, such
+// as a Prometheus exporter or, in tests, a ManualReader. Adding a reader creates the
+// meter provider even if OTLP metrics are not enabled.
+//
+// Example:
+//
+//	reader := sdkmetric.NewManualReader()
+//	p, _ := provider.New(ctx, config.WithMetricReader(reader))
+func (pc *ProviderConfig) WithMetricReader(reader sdkmetric.Reader) *ProviderConfig {
+	pc.MetricReaders = append(pc.MetricReaders, reader)
+	return pc
+}
+
+// WithLogs enables the logs pipeline: New creates a logger provider next to the tracer
+// provider that exports log records over OTLP to the traces endpoint, with /v1/traces
+// replaced by /v1/logs, and shares its resource, headers, TLS and retry settings. Set
+// Config.LogsEndpoint to export logs elsewhere. Records emitted with a context that
+// carries a span are stamped with its trace and span ID, so the backend can show the
+// logs of a trace. The provider's ForceFlush and Shutdown flush the logs as well.
+//
+// Example:
+//
+//	p, _ := provider.New(ctx, provider.NewProviderConfig("payment-service", "v1.2.3").WithLogs())
+//
+//	var record log.Record
+//	record.SetSeverity(log.SeverityInfo)
+//	record.SetBody(log.StringValue("payment authorized"))
+//	p.Logger("payments").Emit(ctx, record)
+func (pc *ProviderConfig) WithLogs() *ProviderConfig {
+	pc.Config.LogsExporter = config.LogsExporterOTLP
+	return pc
+}
+
+// WithLogProcessor attaches an additional log record processor to the logger
+// provider. Adding a processor creates the logger provider even if OTLP logs are not
+// enabled.
+//
+// Example:
+//
+//	config.WithLogProcessor(sdklog.NewSimpleProcessor(exporter))
+func (pc *ProviderConfig) WithLogProcessor(processor sdklog.Processor) *ProviderConfig {
+	pc.LogProcessors = append(pc.LogProcessors, processor)
+	return pc
+}
+
+// WithSpanLimits caps the size of spans so that, for example, a captured stack trace
+// or a huge header does not produce spans the collector rejects. Attributes beyond
+// attributeCount and events and links beyond eventCount and linkCount are dropped
+// and reported as dropped counts by the exporters. String attribute values of spans,
+// their events and links longer than attributeValueLength characters are cut and end
+// in "...[truncated]", so they can be spotted in the backend. Values are cut when the
+// span ends, after redaction rules have seen them in full.
+//
+// A negative limit means unlimited and zero keeps the default. The defaults are 128
+// attributes, events and links and unlimited value length; NewConfigFromEnv reads
+// them from OTEL_SPAN_ATTRIBUTE_COUNT_LIMIT, OTEL_SPAN_ATTRIBUTE_VALUE_LENGTH_LIMIT,
+// OTEL_SPAN_EVENT_COUNT_LIMIT and OTEL_SPAN_LINK_COUNT_LIMIT. The per-event and
+// per-link attribute limits are set with Config.EventAttributeCountLimit and
+// Config.LinkAttributeCountLimit.
+//
+// Example:
+//
+//	config.WithSpanLimits(64, 4096, 32, 32)
+func (pc *ProviderConfig) WithSpanLimits(attributeCount, attributeValueLength, eventCount, linkCount int) *ProviderConfig {
+	pc.Config.SpanAttributeCountLimit = attributeCount
+	pc.Config.SpanAttributeValueLengthLimit = attributeValueLength
+	pc.Config.SpanEventCountLimit = eventCount
+	pc.Config.SpanLinkCountLimit = linkCount
+	return pc
+}
+
+// WithStartupProbe makes New check that the OTLP endpoints accept connections
+// within timeout (zero keeps the default of 5 seconds). If failFast is set, New
+// fails when the collector is unreachable, so a misconfigured service does not start;
+// otherwise the failure is reported to the OpenTelemetry error handler and New
+// continues. SetupTracing reads the same setting from OTEL_EXPORTER_STARTUP_PROBE
+// ("warn" or "fail").
+//
+// Example:
+//
+//	p, err := provider.New(ctx, config.WithStartupProbe(3*time.Second, true))
+//	if err != nil {
+//	    log.Fatal(err) // collector unreachable
+//	}
+func (pc *ProviderConfig) WithStartupProbe(timeout time.Duration, failFast bool) *ProviderConfig {
+	pc.Config.StartupProbe = config.StartupProbeWarn
+	if failFast {
+		pc.Config.StartupProbe = config.StartupProbeFail
+	}
+	if timeout != 0 {
+		pc.Config.StartupProbeTimeout = timeout
+	}
+	return pc
+}
+
+// WithHealthFailingAfter sets how long exports must keep failing before
+// Provider.HealthChecker reports the exporter as failing rather than degraded.
+// Readiness probes using the health handler then fail only after a sustained
+// outage, not after a single failed export. The default is 2 minutes.
+func (pc *ProviderConfig) WithHealthFailingAfter(d time.Duration) *ProviderConfig {
+	pc.Config.HealthFailingAfter = d
+	return pc
+}
+
 // WithBatchOptions configures the batch processor settings for span export optimization.
 // These settings control how spans are batched and exported, affecting both performance
 // and resource usage. Tune these values based on your application's traffic patterns
@@ -223,7 +801,7 @@ func newDefaultProvider(ctx context.Context, serviceName string, serviceVersion
 //   - Standard batch processing settings
 //   - Automatic resource detection for service identification
 //
-// The provider is set as the global OpenTelemetry provider (only once per application).
+// The provider is set as the global OpenTelemetry provider, replacing any previous one.
 // For production use or when you need custom configuration, use NewProvider with NewProviderConfig.
 //
 // Note: This is the function most users will start with. It's designed to "just work"
@@ -237,22 +815,14 @@ func newDefaultProvider(ctx context.Context, serviceName string, serviceVersion
 //	}
 //	defer provider.Shutdown(ctx)
 func NewDefaultProvider(ctx context.Context, serviceName string, serviceVersion ...string) (*sdktrace.TracerProvider, error) {
-	tp, err := newDefaultProvider(ctx, serviceName, serviceVersion...)
-	if err != nil {
-		return nil, err
-	}
-	setOnce.Do(func() {
-		otel.SetTracerProvider(tp)
-	})
-	return tp, nil
+	// newDefaultProvider registers the provider through NewProvider.
+	return newDefaultProvider(ctx, serviceName, serviceVersion...)
 }
 
 // NewProvider creates and configures a new TracerProvider using the provided configuration,
-// then sets it as the global OpenTelemetry provider (only once per application lifecycle).
-// This is the recommended way to initialize tracing when you need custom configuration.
-//
-// The function ensures that the global provider is set only once, even if called multiple times.
-// This prevents conflicts and ensures consistent tracing behavior across the application.
+// then sets it as the global OpenTelemetry provider unless cfg.SkipGlobalRegistration is
+// set. Each call replaces the previous global provider. New returns a Provider handle
+// bundling the tracer provider with its lifecycle instead.
 //
 // Example:
 //
@@ -271,9 +841,9 @@ func NewProvider(ctx context.Context, cfg *ProviderConfig) (*sdktrace.TracerProv
 		return nil, err
 	}
 
-	setOnce.Do(func() {
-		otel.SetTracerProvider(tp)
-	})
+	if !cfg.SkipGlobalRegistration {
+		setGlobal(tp, nil)
+	}
 
 	return tp, nil
 }
@@ -287,6 +857,7 @@ func NewProvider(ctx context.Context, cfg *ProviderConfig) (*sdktrace.TracerProv
 //  2. Exports all remaining spans in the queue
 //  3. Closes the exporter connection
 //  4. Releases any resources held by the provider
+//  5. Replaces the global tracer provider with a no-op provider if tp is global
 //
 // Example:
 //
@@ -300,5 +871,6 @@ func ShutdownTracerProvider(ctx context.Context, tp *sdktrace.TracerProvider) er
 	if tp == nil {
 		return nil
 	}
+	unsetGlobal(tp)
 	return tp.Shutdown(ctx)
 }
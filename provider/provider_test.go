@@ -2,12 +2,16 @@ package provider
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
 	"testing"
 	"time"
 
 	"go.opentelemetry.io/otel"
 	sdkresource "go.opentelemetry.io/otel/sdk/resource"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
 
 	"github.com/kernelshard/otelkit/internal/config"
 )
@@ -350,6 +354,58 @@ func TestCreateSampler(t *testing.T) {
 	}
 }
 
+func TestCreateSampler_SpecParentBased(t *testing.T) {
+	sampledParent := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{1},
+		SpanID:     trace.SpanID{1},
+		TraceFlags: trace.FlagsSampled,
+		Remote:     true,
+	}))
+	unsampledParent := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: trace.TraceID{1},
+		SpanID:  trace.SpanID{1},
+		Remote:  true,
+	}))
+
+	tests := []struct {
+		samplingType   config.SamplingType
+		ratio          float64
+		root           bool // Sampled without a parent
+		sampledChild   bool // Sampled below a sampled parent
+		unsampledChild bool // Sampled below an unsampled parent
+	}{
+		{config.SamplingAlwaysOn, 0, true, true, true},
+		{config.SamplingAlwaysOff, 0, false, false, false},
+		{config.SamplingParentBasedAlwaysOn, 0, true, true, false},
+		{config.SamplingParentBasedAlwaysOff, 0, false, true, false},
+		{config.SamplingTraceIDRatio, 1, true, true, true},
+		{config.SamplingTraceIDRatio, 0, false, false, false},
+		{config.SamplingParentBasedTraceIDRatio, 1, true, true, false},
+		{config.SamplingParentBasedTraceIDRatio, 0, false, true, false},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s/%v", tt.samplingType, tt.ratio), func(t *testing.T) {
+			sampler := createSampler(&config.Config{SamplingType: tt.samplingType, SamplingRatio: tt.ratio})
+			sampled := func(ctx context.Context) bool {
+				return sampler.ShouldSample(sdktrace.SamplingParameters{
+					ParentContext: ctx,
+					TraceID:       trace.TraceID{1},
+					Name:          "op",
+				}).Decision == sdktrace.RecordAndSample
+			}
+			if got := sampled(context.Background()); got != tt.root {
+				t.Errorf("Root sampled = %v, want %v", got, tt.root)
+			}
+			if got := sampled(sampledParent); got != tt.sampledChild {
+				t.Errorf("Child of sampled parent sampled = %v, want %v", got, tt.sampledChild)
+			}
+			if got := sampled(unsampledParent); got != tt.unsampledChild {
+				t.Errorf("Child of unsampled parent sampled = %v, want %v", got, tt.unsampledChild)
+			}
+		})
+	}
+}
+
 func TestShutdownTracerProvider(t *testing.T) {
 	ctx := context.Background()
 
@@ -431,7 +487,7 @@ func TestCreateExporter_InvalidProtocol(t *testing.T) {
 	pc := NewProviderConfig("test-service", "1.0.0")
 	pc.Config.OTLPExporterProtocol = "invalid"
 
-	_, err := createExporter(ctx, pc)
+	_, err := createExporter(ctx, pc, nil)
 	if err == nil {
 		t.Error("Expected error for invalid OTLPExporterProtocol")
 	}
@@ -475,3 +531,66 @@ func TestNewProvider_Integration(t *testing.T) {
 		t.Error("Expected newProvider to return a TracerProvider")
 	}
 }
+
+func TestNewProvider_FanOut(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	primary := filepath.Join(dir, "primary.jsonl")
+	archive := filepath.Join(dir, "archive.jsonl")
+
+	pc := NewProviderConfig("fanout-service", "1.0.0").
+		WithFileExporter(primary, 0, 0, 0).
+		WithSampling(config.SamplingAlwaysOn, 1.0).
+		WithAdditionalExporter(ExporterConfig{
+			Name:          "archive",
+			Protocol:      config.ProtocolFile,
+			Endpoint:      archive,
+			ExportTimeout: time.Second,
+		})
+
+	tp, err := newProvider(ctx, pc)
+	if err != nil {
+		t.Fatalf("newProvider failed: %v", err)
+	}
+	_, span := tp.Tracer("test").Start(ctx, "fanout")
+	span.End()
+	if err := ShutdownTracerProvider(ctx, tp); err != nil {
+		t.Fatalf("Shutdown failed: %v", err)
+	}
+
+	for _, path := range []string{primary, archive} {
+		if lines := readLines(t, path); len(lines) != 1 {
+			t.Errorf("Expected %s to receive the span, got %d lines", path, len(lines))
+		}
+	}
+}
+
+func TestNewProvider_FanOutInvalidExporter(t *testing.T) {
+	ctx := context.Background()
+	pc := NewProviderConfig("fanout-service", "1.0.0").
+		WithAdditionalExporter(ExporterConfig{Name: "broken", Protocol: "invalid"})
+
+	_, err := newProvider(ctx, pc)
+	if err == nil {
+		t.Fatal("Expected error for invalid additional exporter")
+	}
+	var initErr *InitializationError
+	if !errors.As(err, &initErr) || initErr.Component != "exporter broken" {
+		t.Errorf("Expected InitializationError naming the exporter, got %v", err)
+	}
+}
+
+func TestProviderConfig_ForExporterInheritsBatchOptions(t *testing.T) {
+	pc := NewProviderConfig("test", "1.0.0").WithBatchOptions(time.Second, 2*time.Second, 10, 100)
+
+	derived := pc.forExporter(ExporterConfig{Name: "vendor", Protocol: "grpc", Endpoint: "vendor:4317", MaxQueueSize: 50})
+	if derived.BatchTimeout != time.Second || derived.ExportTimeout != 2*time.Second || derived.MaxExportBatchSize != 10 {
+		t.Errorf("Expected inherited batch options, got %+v", derived)
+	}
+	if derived.MaxQueueSize != 50 {
+		t.Errorf("Expected MaxQueueSize override 50, got %d", derived.MaxQueueSize)
+	}
+	if derived.Config.OTLPExporterEndpoint != "vendor:4317" || pc.Config.OTLPExporterEndpoint == "vendor:4317" {
+		t.Error("forExporter should only modify the derived config")
+	}
+}
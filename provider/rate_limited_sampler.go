@@ -0,0 +1,83 @@
+package provider
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/kernelshard/otelkit/internal/config"
+)
+
+// RateLimitedSamplerFactory creates parent-based samplers that sample at most
+// cfg.SamplingRateLimit root traces per second. Spans with a parent follow the parent's
+// decision, so only root spans consume the budget.
+type RateLimitedSamplerFactory struct{}
+
+func (f *RateLimitedSamplerFactory) CreateSampler(cfg *config.Config) sdktrace.Sampler {
+	return sdktrace.ParentBased(newRateLimitingSampler(cfg.SamplingRateLimit))
+}
+
+// rateLimitingSampler samples with a token bucket that refills at rate tokens per
+// second and holds up to one second's worth (at least one token), so short bursts
+// are sampled while the long-term rate stays capped regardless of traffic.
+type rateLimitingSampler struct {
+	rate  float64
+	burst float64
+	now   func() time.Time
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+func newRateLimitingSampler(tracesPerSecond float64) *rateLimitingSampler {
+	if tracesPerSecond < 0 {
+		tracesPerSecond = 0
+	}
+	s := &rateLimitingSampler{
+		rate:  tracesPerSecond,
+		burst: math.Max(tracesPerSecond, 1),
+		now:   time.Now,
+	}
+	s.tokens = s.burst
+	s.last = s.now()
+	return s
+}
+
+// ShouldSample samples if a token is available.
+func (s *rateLimitingSampler) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	result := sdktrace.SamplingResult{
+		Decision:   sdktrace.Drop,
+		Tracestate: trace.SpanContextFromContext(p.ParentContext).TraceState(),
+	}
+	if s.take() {
+		result.Decision = sdktrace.RecordAndSample
+	}
+	return result
+}
+
+// Description returns the sampler name and rate.
+func (s *rateLimitingSampler) Description() string {
+	return fmt.Sprintf("RateLimitingSampler{%g}", s.rate)
+}
+
+// take refills the bucket for the elapsed time and consumes a token if available.
+func (s *rateLimitingSampler) take() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	if elapsed := now.Sub(s.last); elapsed > 0 {
+		s.tokens = math.Min(s.burst, s.tokens+elapsed.Seconds()*s.rate)
+		s.last = now
+	}
+	if s.tokens < 1 {
+		return false
+	}
+	s.tokens--
+	return true
+}
@@ -0,0 +1,100 @@
+package provider
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/kernelshard/otelkit/internal/config"
+)
+
+func rootSamplingParameters() sdktrace.SamplingParameters {
+	return sdktrace.SamplingParameters{
+		ParentContext: context.Background(),
+		TraceID:       trace.TraceID{1},
+		Name:          "op",
+	}
+}
+
+func TestRateLimitingSampler_TokenBucket(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	s := newRateLimitingSampler(2)
+	s.now = func() time.Time { return now }
+	s.last = now
+
+	sampled := 0
+	for i := 0; i < 10; i++ {
+		if s.ShouldSample(rootSamplingParameters()).Decision == sdktrace.RecordAndSample {
+			sampled++
+		}
+	}
+	if sampled != 2 {
+		t.Fatalf("Expected the burst to be capped at 2 traces, got %d", sampled)
+	}
+
+	now = now.Add(500 * time.Millisecond)
+	if s.ShouldSample(rootSamplingParameters()).Decision != sdktrace.RecordAndSample {
+		t.Error("Expected one token to be refilled after 500ms")
+	}
+	if s.ShouldSample(rootSamplingParameters()).Decision != sdktrace.Drop {
+		t.Error("Expected the bucket to be empty again")
+	}
+
+	// A long pause refills at most one second's worth of tokens.
+	now = now.Add(time.Minute)
+	sampled = 0
+	for i := 0; i < 10; i++ {
+		if s.ShouldSample(rootSamplingParameters()).Decision == sdktrace.RecordAndSample {
+			sampled++
+		}
+	}
+	if sampled != 2 {
+		t.Errorf("Expected 2 traces after a pause, got %d", sampled)
+	}
+}
+
+func TestRateLimitingSampler_FractionalRate(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	s := newRateLimitingSampler(0.5)
+	s.now = func() time.Time { return now }
+	s.last = now
+
+	if s.ShouldSample(rootSamplingParameters()).Decision != sdktrace.RecordAndSample {
+		t.Fatal("Expected the first trace to be sampled")
+	}
+	now = now.Add(time.Second)
+	if s.ShouldSample(rootSamplingParameters()).Decision != sdktrace.Drop {
+		t.Error("Expected no token after one second at 0.5 traces per second")
+	}
+	now = now.Add(time.Second)
+	if s.ShouldSample(rootSamplingParameters()).Decision != sdktrace.RecordAndSample {
+		t.Error("Expected a token after two seconds")
+	}
+}
+
+func TestRateLimitedSamplerFactory_ParentBased(t *testing.T) {
+	sampler := createSampler(&config.Config{SamplingType: config.SamplingRateLimited, SamplingRateLimit: 1})
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSampler(sampler))
+	defer tp.Shutdown(context.Background())
+	tr := tp.Tracer("rate-test")
+
+	ctx, root := tr.Start(context.Background(), "root")
+	if !root.SpanContext().IsSampled() {
+		t.Fatal("Expected the first root span to be sampled")
+	}
+	_, dropped := tr.Start(context.Background(), "root2")
+	if dropped.SpanContext().IsSampled() {
+		t.Error("Expected the second root span to exceed the rate")
+	}
+	// Children of a sampled parent are sampled without consuming tokens.
+	for i := 0; i < 5; i++ {
+		_, child := tr.Start(ctx, "child")
+		if !child.SpanContext().IsSampled() {
+			t.Fatal("Expected children to follow the sampled parent")
+		}
+		child.End()
+	}
+}
@@ -0,0 +1,430 @@
+package provider
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+
+	"github.com/kernelshard/otelkit/internal/config"
+)
+
+// RedactionAction is what a redaction rule does with the data it matches.
+type RedactionAction int
+
+const (
+	// RedactDrop removes the matched attribute or query parameter.
+	RedactDrop RedactionAction = iota
+	// RedactMask replaces the matched value with "[REDACTED]".
+	RedactMask
+	// RedactHash replaces the matched value with a short SHA-256 digest, so equal
+	// values can still be correlated across spans. Low-entropy values such as emails
+	// can be recovered by guessing, so hashing is not a substitute for masking secrets.
+	RedactHash
+)
+
+// RedactionRule selects sensitive data in span attributes, span event attributes and
+// span status descriptions (error messages).
+//
+// A rule with only Keys applies to the whole value of matching attributes. Pattern
+// and QueryParams select parts of string values instead: regular expression matches
+// and the values of URL query parameters with the given names (case-insensitive).
+// With Keys, they apply only to matching attributes; without, to every string
+// attribute and to status descriptions.
+//
+// For whole values and patterns, RedactDrop removes the attribute; for query
+// parameters it removes the parameter from the URL.
+type RedactionRule struct {
+	// Keys are attribute keys, as globs where '*' matches any sequence of characters,
+	// or regular expressions when prefixed with "re:".
+	Keys []string
+
+	// Pattern matches sensitive parts of string values.
+	Pattern *regexp.Regexp
+
+	// QueryParams are names of URL query parameters whose values are sensitive.
+	QueryParams []string
+
+	// Action is applied to the matched data.
+	Action RedactionAction
+
+	// validate, if set, confirms a Pattern match, e.g. with a checksum.
+	validate func(match string) bool
+}
+
+var (
+	emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)
+	cardPattern  = regexp.MustCompile(`\b\d(?:[ -]?\d){12,18}\b`)
+	jwtPattern   = regexp.MustCompile(`\beyJ[A-Za-z0-9_-]*\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`)
+)
+
+// RedactAttributes redacts the whole value of attributes with the given keys.
+//
+// Example:
+//
+//	provider.RedactAttributes(provider.RedactHash, "enduser.id", "user.*")
+func RedactAttributes(action RedactionAction, keys ...string) RedactionRule {
+	return RedactionRule{Keys: keys, Action: action}
+}
+
+// RedactEmails redacts email addresses in string values.
+func RedactEmails(action RedactionAction) RedactionRule {
+	return RedactionRule{Pattern: emailPattern, Action: action}
+}
+
+// RedactCardNumbers redacts payment card numbers of 13 to 19 digits, optionally
+// separated by spaces or dashes, that pass the Luhn checksum.
+func RedactCardNumbers(action RedactionAction) RedactionRule {
+	return RedactionRule{Pattern: cardPattern, Action: action, validate: luhnValid}
+}
+
+// RedactJWTs redacts JSON Web Tokens, e.g. from Authorization headers.
+func RedactJWTs(action RedactionAction) RedactionRule {
+	return RedactionRule{Pattern: jwtPattern, Action: action}
+}
+
+// RedactQueryParams redacts the values of the named URL query parameters, such as
+// the http.url attribute recorded by the middleware and http.external.url recorded by
+// TracedHTTPClient.
+//
+// Example:
+//
+//	provider.RedactQueryParams(provider.RedactMask, "token", "api_key")
+//	// https://api.example.com/v1?token=[REDACTED]&page=2
+func RedactQueryParams(action RedactionAction, names ...string) RedactionRule {
+	return RedactionRule{QueryParams: names, Action: action}
+}
+
+// DefaultRedactionRules masks email addresses, card numbers, JWTs and common
+// credential query parameters.
+func DefaultRedactionRules() []RedactionRule {
+	return []RedactionRule{
+		RedactQueryParams(RedactMask, "token", "access_token", "refresh_token", "id_token",
+			"api_key", "apikey", "key", "password", "secret", "signature", "sig"),
+		RedactJWTs(RedactMask),
+		RedactEmails(RedactMask),
+		RedactCardNumbers(RedactMask),
+	}
+}
+
+// compiledRedactionRule is a RedactionRule with its keys and query parameters compiled.
+type compiledRedactionRule struct {
+	RedactionRule
+	keys  []*regexp.Regexp
+	query *regexp.Regexp
+}
+
+// RedactionProcessor redacts sensitive data from ended spans before passing them to
+// the downstream processors, typically batch span processors. Attributes added while
+// the span runs are covered as well as those set at start.
+type RedactionProcessor struct {
+	next  []sdktrace.SpanProcessor
+	rules []compiledRedactionRule
+}
+
+// NewRedactionProcessor creates a processor applying rules, in order, to spans before
+// forwarding them to next. See ProviderConfig.WithRedaction.
+func NewRedactionProcessor(rules []RedactionRule, next ...sdktrace.SpanProcessor) (*RedactionProcessor, error) {
+	p := &RedactionProcessor{next: next}
+	for i, rule := range rules {
+		field := "Redaction[" + strconv.Itoa(i) + "]"
+		if len(rule.Keys) == 0 && rule.Pattern == nil && len(rule.QueryParams) == 0 {
+			return nil, &config.ConfigError{Field: field, Message: config.ErrInvalidRedactionRule}
+		}
+		if rule.Action < RedactDrop || rule.Action > RedactHash {
+			return nil, &config.ConfigError{Field: field, Message: config.ErrInvalidRedactionAction}
+		}
+		compiled := compiledRedactionRule{RedactionRule: rule}
+		for _, key := range rule.Keys {
+			re, err := config.CompileSamplingPattern(key)
+			if err != nil {
+				return nil, &config.ConfigError{Field: field + ".Keys", Message: err.Error()}
+			}
+			compiled.keys = append(compiled.keys, re)
+		}
+		if len(rule.QueryParams) > 0 {
+			names := make([]string, len(rule.QueryParams))
+			for j, name := range rule.QueryParams {
+				names[j] = regexp.QuoteMeta(name)
+			}
+			compiled.query = regexp.MustCompile(`(?i)([?&;])(` + strings.Join(names, "|") + `)=([^&#;\s]*)`)
+		}
+		p.rules = append(p.rules, compiled)
+	}
+	return p, nil
+}
+
+// OnStart forwards s to the downstream processors.
+func (p *RedactionProcessor) OnStart(parent context.Context, s sdktrace.ReadWriteSpan) {
+	for _, sp := range p.next {
+		sp.OnStart(parent, s)
+	}
+}
+
+// OnEnd forwards a redacted view of s to the downstream processors.
+func (p *RedactionProcessor) OnEnd(s sdktrace.ReadOnlySpan) {
+	redacted := p.redact(s)
+	for _, sp := range p.next {
+		sp.OnEnd(redacted)
+	}
+}
+
+// Shutdown shuts down the downstream processors.
+func (p *RedactionProcessor) Shutdown(ctx context.Context) error {
+	var err error
+	for _, sp := range p.next {
+		err = errors.Join(err, sp.Shutdown(ctx))
+	}
+	return err
+}
+
+// ForceFlush flushes the downstream processors.
+func (p *RedactionProcessor) ForceFlush(ctx context.Context) error {
+	var err error
+	for _, sp := range p.next {
+		err = errors.Join(err, sp.ForceFlush(ctx))
+	}
+	return err
+}
+
+// redactedSpan overrides the redacted parts of a span.
+type redactedSpan struct {
+	sdktrace.ReadOnlySpan
+	attrs  []attribute.KeyValue
+	events []sdktrace.Event
+	status sdktrace.Status
+}
+
+func (s redactedSpan) Attributes() []attribute.KeyValue { return s.attrs }
+func (s redactedSpan) Events() []sdktrace.Event         { return s.events }
+func (s redactedSpan) Status() sdktrace.Status          { return s.status }
+
+// redact returns s with the rules applied, or s itself if nothing matched.
+func (p *RedactionProcessor) redact(s sdktrace.ReadOnlySpan) sdktrace.ReadOnlySpan {
+	attrs, changed := p.redactAttributes(s.Attributes())
+
+	events := s.Events()
+	var eventsChanged bool
+	for i, ev := range events {
+		evAttrs, ok := p.redactAttributes(ev.Attributes)
+		if !ok {
+			continue
+		}
+		if !eventsChanged {
+			events = append([]sdktrace.Event(nil), events...)
+			eventsChanged = true
+		}
+		events[i].Attributes = evAttrs
+	}
+
+	status := s.Status()
+	if description, ok := p.redactText(status.Description); ok {
+		status.Description = description
+		changed = true
+	}
+
+	if !changed && !eventsChanged {
+		return s
+	}
+	return redactedSpan{ReadOnlySpan: s, attrs: attrs, events: events, status: status}
+}
+
+// redactAttributes applies the rules to attrs. It returns a new slice and true if
+// any attribute was redacted.
+func (p *RedactionProcessor) redactAttributes(attrs []attribute.KeyValue) ([]attribute.KeyValue, bool) {
+	var out []attribute.KeyValue
+	for i, kv := range attrs {
+		redacted, keep := p.redactAttribute(kv)
+		if out == nil {
+			if keep && redacted == kv {
+				continue
+			}
+			out = append(make([]attribute.KeyValue, 0, len(attrs)), attrs[:i]...)
+		}
+		if keep {
+			out = append(out, redacted)
+		}
+	}
+	if out == nil {
+		return attrs, false
+	}
+	return out, true
+}
+
+// redactAttribute applies the rules to kv in order. It returns false if kv is dropped.
+func (p *RedactionProcessor) redactAttribute(kv attribute.KeyValue) (attribute.KeyValue, bool) {
+	for _, rule := range p.rules {
+		if !rule.matchesKey(string(kv.Key)) {
+			continue
+		}
+		if rule.Pattern == nil && rule.query == nil {
+			switch rule.Action {
+			case RedactDrop:
+				return kv, false
+			case RedactMask:
+				kv = attribute.String(string(kv.Key), config.RedactionMask)
+			case RedactHash:
+				kv = attribute.String(string(kv.Key), hashValue(kv.Value.Emit()))
+			}
+			continue
+		}
+
+		switch kv.Value.Type() {
+		case attribute.STRING:
+			s, matched, dropped := rule.redactString(kv.Value.AsString())
+			if dropped {
+				return kv, false
+			}
+			if matched {
+				kv = attribute.String(string(kv.Key), s)
+			}
+		case attribute.STRINGSLICE:
+			values := kv.Value.AsStringSlice()
+			var changed bool
+			for i, v := range values {
+				s, matched, dropped := rule.redactString(v)
+				if dropped {
+					return kv, false
+				}
+				if matched {
+					values[i], changed = s, true
+				}
+			}
+			if changed {
+				kv = attribute.StringSlice(string(kv.Key), values)
+			}
+		}
+	}
+	return kv, true
+}
+
+// redactText applies the rules without keys to s, e.g. a status description. A
+// dropping rule that matches clears s.
+func (p *RedactionProcessor) redactText(s string) (string, bool) {
+	if s == "" {
+		return s, false
+	}
+	var changed bool
+	for _, rule := range p.rules {
+		if len(rule.keys) > 0 {
+			continue
+		}
+		redacted, matched, dropped := rule.redactString(s)
+		if dropped {
+			return "", true
+		}
+		if matched {
+			s, changed = redacted, true
+		}
+	}
+	return s, changed
+}
+
+// matchesKey reports whether the rule applies to the attribute key.
+func (r compiledRedactionRule) matchesKey(key string) bool {
+	if len(r.keys) == 0 {
+		return true
+	}
+	for _, re := range r.keys {
+		if re.MatchString(key) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactString applies the rule's pattern and query parameters to s. It reports
+// whether anything matched and, for RedactDrop patterns, that the value is dropped.
+func (r compiledRedactionRule) redactString(s string) (result string, matched, dropped bool) {
+	if r.Pattern != nil {
+		s = r.Pattern.ReplaceAllStringFunc(s, func(m string) string {
+			if r.validate != nil && !r.validate(m) {
+				return m
+			}
+			matched = true
+			return r.replacement(m)
+		})
+		if matched && r.Action == RedactDrop {
+			return "", true, true
+		}
+	}
+	if r.query != nil {
+		var q bool
+		s, q = r.redactQuery(s)
+		matched = matched || q
+	}
+	return s, matched, false
+}
+
+// redactQuery redacts the values of the rule's query parameters in s.
+func (r compiledRedactionRule) redactQuery(s string) (string, bool) {
+	matches := r.query.FindAllStringSubmatchIndex(s, -1)
+	if matches == nil {
+		return s, false
+	}
+	var b strings.Builder
+	last := 0
+	for _, m := range matches {
+		// m holds the bounds of the match, separator, name and value.
+		sepStart, valueStart, valueEnd := m[2], m[6], m[7]
+		b.WriteString(s[last:sepStart])
+		last = m[1]
+		if r.Action == RedactDrop {
+			// Keep the '?' only if parameters follow, dropping their separator instead.
+			if s[sepStart] == '?' && last < len(s) && (s[last] == '&' || s[last] == ';') {
+				b.WriteByte('?')
+				last++
+			}
+			continue
+		}
+		b.WriteString(s[sepStart:valueStart])
+		b.WriteString(r.replacement(s[valueStart:valueEnd]))
+	}
+	b.WriteString(s[last:])
+	return b.String(), true
+}
+
+// replacement returns what a matched value is replaced with.
+func (r compiledRedactionRule) replacement(value string) string {
+	switch r.Action {
+	case RedactHash:
+		return hashValue(value)
+	case RedactMask:
+		return config.RedactionMask
+	default:
+		return ""
+	}
+}
+
+// hashValue returns a short, stable digest of v.
+func hashValue(v string) string {
+	sum := sha256.Sum256([]byte(v))
+	return "sha256:" + hex.EncodeToString(sum[:8])
+}
+
+// luhnValid reports whether the digits in s pass the Luhn checksum.
+func luhnValid(s string) bool {
+	var sum, n int
+	for i := len(s) - 1; i >= 0; i-- {
+		c := s[i]
+		if c < '0' || c > '9' {
+			continue
+		}
+		d := int(c - '0')
+		if n%2 == 1 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		n++
+	}
+	return n >= 13 && sum%10 == 0
+}
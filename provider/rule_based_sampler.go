@@ -0,0 +1,133 @@
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/kernelshard/otelkit/internal/config"
+)
+
+// SamplingRule assigns a sampling ratio to matching spans. See
+// ProviderConfig.WithSamplingRules.
+type SamplingRule = config.SamplingRule
+
+// RuleBasedSamplerFactory creates parent-based samplers from the ordered rules in
+// cfg.SamplingRules and cfg.SamplingRulesFile. Root spans are sampled at the ratio of
+// the first matching rule, or at cfg.SamplingRatio if none matches.
+//
+// Invalid rules are reported through the OpenTelemetry error handler and the
+// sampler falls back to probabilistic sampling at cfg.SamplingRatio; NewProvider
+// rejects them before this happens.
+type RuleBasedSamplerFactory struct{}
+
+func (f *RuleBasedSamplerFactory) CreateSampler(cfg *config.Config) sdktrace.Sampler {
+	sampler, err := newRuleBasedSampler(cfg)
+	if err != nil {
+		otel.Handle(fmt.Errorf("otelkit: invalid sampling rules, using probabilistic sampling: %w", err))
+		return sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SamplingRatio))
+	}
+	return sdktrace.ParentBased(sampler)
+}
+
+// ruleBasedSampler samples each root span at the ratio of the first matching rule.
+type ruleBasedSampler struct {
+	rules    []compiledSamplingRule
+	fallback sdktrace.Sampler
+}
+
+type compiledSamplingRule struct {
+	spanName   *regexp.Regexp
+	spanKind   trace.SpanKind
+	attributes map[attribute.Key]*regexp.Regexp
+	sampler    sdktrace.Sampler
+}
+
+func newRuleBasedSampler(cfg *config.Config) (*ruleBasedSampler, error) {
+	rules, err := cfg.SamplingRuleSet()
+	if err != nil {
+		return nil, err
+	}
+
+	s := &ruleBasedSampler{fallback: sdktrace.TraceIDRatioBased(cfg.SamplingRatio)}
+	for _, rule := range rules {
+		compiled := compiledSamplingRule{sampler: sdktrace.TraceIDRatioBased(rule.Ratio)}
+		if rule.SpanKind != "" {
+			compiled.spanKind = parseSpanKind(rule.SpanKind)
+		}
+		// Patterns were validated by SamplingRuleSet.
+		compiled.spanName, _ = config.CompileSamplingPattern(rule.SpanName)
+		if len(rule.Attributes) > 0 {
+			compiled.attributes = make(map[attribute.Key]*regexp.Regexp, len(rule.Attributes))
+			for key, pattern := range rule.Attributes {
+				re, _ := config.CompileSamplingPattern(pattern)
+				compiled.attributes[attribute.Key(key)] = re
+			}
+		}
+		s.rules = append(s.rules, compiled)
+	}
+	return s, nil
+}
+
+// ShouldSample delegates to the ratio sampler of the first matching rule.
+func (s *ruleBasedSampler) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	for _, rule := range s.rules {
+		if rule.matches(p) {
+			return rule.sampler.ShouldSample(p)
+		}
+	}
+	return s.fallback.ShouldSample(p)
+}
+
+// Description returns the sampler name and number of rules.
+func (s *ruleBasedSampler) Description() string {
+	return fmt.Sprintf("RuleBasedSampler{rules:%d,fallback:%s}", len(s.rules), s.fallback.Description())
+}
+
+func (r compiledSamplingRule) matches(p sdktrace.SamplingParameters) bool {
+	if r.spanKind != trace.SpanKindUnspecified && trace.ValidateSpanKind(p.Kind) != r.spanKind {
+		return false
+	}
+	if r.spanName != nil && !r.spanName.MatchString(p.Name) {
+		return false
+	}
+	for key, re := range r.attributes {
+		value, ok := attributeValue(p.Attributes, key)
+		if !ok {
+			return false
+		}
+		if re != nil && !re.MatchString(value) {
+			return false
+		}
+	}
+	return true
+}
+
+func attributeValue(attrs []attribute.KeyValue, key attribute.Key) (string, bool) {
+	for _, kv := range attrs {
+		if kv.Key == key {
+			return kv.Value.Emit(), true
+		}
+	}
+	return "", false
+}
+
+func parseSpanKind(kind string) trace.SpanKind {
+	switch strings.ToLower(kind) {
+	case "server":
+		return trace.SpanKindServer
+	case "client":
+		return trace.SpanKindClient
+	case "producer":
+		return trace.SpanKindProducer
+	case "consumer":
+		return trace.SpanKindConsumer
+	default:
+		return trace.SpanKindInternal
+	}
+}
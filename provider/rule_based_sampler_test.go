@@ -0,0 +1,107 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/kernelshard/otelkit/internal/config"
+)
+
+func TestRuleBasedSampler(t *testing.T) {
+	cfg := config.NewConfig("rules-service", "1.0.0")
+	cfg.SamplingType = config.SamplingRuleBased
+	cfg.SamplingRatio = 0
+	cfg.SamplingRules = []config.SamplingRule{
+		{SpanName: "* /checkout*", Ratio: 1},
+		{Attributes: map[string]string{"http.url": "*/healthz"}, Ratio: 0},
+		{SpanKind: "client", Attributes: map[string]string{"http.method": "re:^(POST|PUT)$"}, Ratio: 1},
+		{Attributes: map[string]string{"tenant": "vip"}, Ratio: 1},
+	}
+
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSampler(createSampler(cfg)))
+	defer tp.Shutdown(context.Background())
+	tr := tp.Tracer("rules-test")
+
+	tests := []struct {
+		name  string
+		span  string
+		opts  []trace.SpanStartOption
+		wantS bool
+	}{
+		{"checkout route", "POST /checkout/confirm", nil, true},
+		{"health check", "GET /healthz", []trace.SpanStartOption{
+			trace.WithAttributes(attribute.String("http.url", "http://svc/healthz")),
+		}, false},
+		{"client POST", "call payments", []trace.SpanStartOption{
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(attribute.String("http.method", "POST")),
+		}, true},
+		{"server POST does not match client rule", "call payments", []trace.SpanStartOption{
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(attribute.String("http.method", "POST")),
+		}, false},
+		{"attribute missing", "GET /orders", nil, false},
+		{"attribute match", "GET /orders", []trace.SpanStartOption{
+			trace.WithAttributes(attribute.String("tenant", "vip")),
+		}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, span := tr.Start(context.Background(), tt.span, tt.opts...)
+			defer span.End()
+			if got := span.SpanContext().IsSampled(); got != tt.wantS {
+				t.Errorf("Sampled = %v, want %v", got, tt.wantS)
+			}
+		})
+	}
+
+	// Children follow the root decision even when a rule would drop them.
+	ctx, root := tr.Start(context.Background(), "GET /checkout")
+	_, child := tr.Start(ctx, "GET /healthz", trace.WithAttributes(attribute.String("http.url", "/healthz")))
+	if !child.SpanContext().IsSampled() {
+		t.Error("Expected child span to follow the sampled parent")
+	}
+	child.End()
+	root.End()
+}
+
+func TestNewProvider_SamplingRulesFile(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "rules.json")
+	if err := os.WriteFile(path, []byte(`{"rules": [{"span_name": "re:(", "ratio": 1}]}`), 0o600); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+
+	pc := NewProviderConfig("rules-service", "1.0.0").
+		WithConsoleExporter(io.Discard, true).
+		WithSamplingRulesFile(path)
+	_, err := newProvider(ctx, pc)
+	var cfgErr *config.ConfigError
+	if !errors.As(err, &cfgErr) || cfgErr.Field != "SamplingRules[0].SpanName" {
+		t.Fatalf("Expected invalid rule to fail provider creation, got %v", err)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"rules": [{"span_name": "keep*", "ratio": 1}]}`), 0o600); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	pc.WithSamplingRules(0, SamplingRule{SpanName: "drop*", Ratio: 0})
+	tp, err := newProvider(ctx, pc)
+	if err != nil {
+		t.Fatalf("newProvider failed: %v", err)
+	}
+	defer tp.Shutdown(ctx)
+
+	_, kept := tp.Tracer("test").Start(ctx, "keep-me")
+	_, dropped := tp.Tracer("test").Start(ctx, "drop-me")
+	if !kept.SpanContext().IsSampled() || dropped.SpanContext().IsSampled() {
+		t.Error("Expected inline and file rules to be combined")
+	}
+}
@@ -0,0 +1,325 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"math"
+	"runtime/metrics"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+
+	"github.com/kernelshard/otelkit/internal/config"
+)
+
+// runtime/metrics samples read by RuntimeMetrics.
+const (
+	goroutinesSample    = "/sched/goroutines:goroutines"
+	memoryTotalSample   = "/memory/classes/total:bytes"
+	memoryFreedSample   = "/memory/classes/heap/released:bytes"
+	heapObjectsSample   = "/memory/classes/heap/objects:bytes"
+	heapGoalSample      = "/gc/heap/goal:bytes"
+	gcCyclesSample      = "/gc/cycles/total:gc-cycles"
+	gcPausesSample      = "/sched/pauses/total/gc:seconds"
+	schedLatencySample  = "/sched/latencies:seconds"
+	runtimeMeterName    = "github.com/kernelshard/otelkit/runtime"
+	runtimeMeterVersion = "1.0.0"
+)
+
+// latencyBuckets are the bucket upper bounds, in seconds, for GC pauses and
+// scheduling latency, from 1µs to 1s.
+var latencyBuckets = []float64{
+	1e-6, 5e-6, 1e-5, 5e-5, 1e-4, 2.5e-4, 5e-4, 1e-3, 2.5e-3, 5e-3, 1e-2, 2.5e-2, 5e-2, 0.1, 0.25, 0.5, 1,
+}
+
+// latencyBoundKey is the attribute holding the upper bound of a latency bucket.
+const latencyBoundKey = attribute.Key("bucket.upper_bound")
+
+// latencyBucketAttrs holds the bound attribute of each latency bucket, the last one
+// being "+Inf".
+var latencyBucketAttrs = func() []metric.ObserveOption {
+	attrs := make([]metric.ObserveOption, len(latencyBuckets)+1)
+	for i, bound := range latencyBuckets {
+		attrs[i] = metric.WithAttributes(latencyBoundKey.String(strconv.FormatFloat(bound, 'g', -1, 64)))
+	}
+	attrs[len(latencyBuckets)] = metric.WithAttributes(latencyBoundKey.String("+Inf"))
+	return attrs
+}()
+
+// RuntimeMetrics periodically records Go runtime and process metrics. It is created
+// by StartRuntimeMetrics or Provider.StartRuntimeMetrics.
+//
+// Recorded instruments:
+//   - go.goroutine.count: live goroutines
+//   - go.memory.used: memory mapped by the Go runtime minus memory returned to the OS
+//   - go.memory.heap.objects: heap memory occupied by live and not yet swept objects
+//   - go.memory.gc.goal: heap size target of the current GC cycle
+//   - go.gc.count: completed GC cycles
+//   - go.gc.pause.count: stop-the-world GC pauses, by duration bucket
+//   - go.schedule.wait.count: waits of goroutines to run, by duration bucket
+//   - process.cpu.time: user and system CPU time, by cpu.mode (Unix only)
+//   - process.open_file_descriptor.count: open file descriptors (Unix only)
+//
+// The two bucket counters are read from the runtime's own histograms when the
+// metrics are collected. Their bucket.upper_bound attribute holds a bound in seconds,
+// and each bucket counts all durations up to its bound, so that the "+Inf" bucket is
+// the total.
+type RuntimeMetrics struct {
+	interval time.Duration
+
+	goroutines  metric.Int64Gauge
+	memoryUsed  metric.Int64Gauge
+	heapObjects metric.Int64Gauge
+	heapGoal    metric.Int64Gauge
+	gcCount     metric.Int64Counter
+	gcPauses    metric.Int64ObservableCounter
+	schedule    metric.Int64ObservableCounter
+	cpuTime     metric.Float64Counter
+	openFDs     metric.Int64Gauge
+
+	// registration of the callback observing gcPauses and schedule.
+	registration metric.Registration
+
+	samples []metrics.Sample
+	// Previous cumulative values, to record deltas.
+	lastGCCycles uint64
+	lastCPU      processCPU
+
+	cancel   context.CancelFunc
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+// StartRuntimeMetrics starts recording Go runtime and process metrics with meters of
+// mp every interval, until Stop is called. If mp is nil, the global meter provider is
+// used; the metrics then carry the resource of the provider installed by New or
+// SetupTracing. A zero interval records every 15 seconds.
+//
+// Example:
+//
+//	rm, err := provider.StartRuntimeMetrics(p.MeterProvider(), 10*time.Second)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	defer rm.Stop(ctx)
+func StartRuntimeMetrics(mp metric.MeterProvider, interval time.Duration) (*RuntimeMetrics, error) {
+	if interval < 0 {
+		return nil, &config.ConfigError{Field: "interval", Message: config.ErrInvalidRuntimeInterval}
+	}
+	if interval == 0 {
+		interval = config.DefaultRuntimeMetricsInterval
+	}
+	if mp == nil {
+		mp = otel.GetMeterProvider()
+	}
+
+	rm, err := newRuntimeMetrics(mp.Meter(runtimeMeterName, metric.WithInstrumentationVersion(runtimeMeterVersion)))
+	if err != nil {
+		return nil, &InitializationError{Component: "runtime metrics", Cause: err}
+	}
+	rm.interval = interval
+
+	ctx, cancel := context.WithCancel(context.Background())
+	rm.cancel = cancel
+	rm.done = make(chan struct{})
+	rm.record(ctx)
+	go rm.run(ctx)
+	return rm, nil
+}
+
+// newRuntimeMetrics creates the instruments of the runtime metrics.
+func newRuntimeMetrics(meter metric.Meter) (*RuntimeMetrics, error) {
+	rm := &RuntimeMetrics{
+		samples: []metrics.Sample{
+			{Name: goroutinesSample},
+			{Name: memoryTotalSample},
+			{Name: memoryFreedSample},
+			{Name: heapObjectsSample},
+			{Name: heapGoalSample},
+			{Name: gcCyclesSample},
+		},
+	}
+	var err, e error
+	rm.goroutines, e = meter.Int64Gauge("go.goroutine.count",
+		metric.WithDescription("Count of live goroutines."), metric.WithUnit("{goroutine}"))
+	err = errors.Join(err, e)
+	rm.memoryUsed, e = meter.Int64Gauge("go.memory.used",
+		metric.WithDescription("Memory used by the Go runtime."), metric.WithUnit("By"))
+	err = errors.Join(err, e)
+	rm.heapObjects, e = meter.Int64Gauge("go.memory.heap.objects",
+		metric.WithDescription("Heap memory occupied by live and not yet swept objects."), metric.WithUnit("By"))
+	err = errors.Join(err, e)
+	rm.heapGoal, e = meter.Int64Gauge("go.memory.gc.goal",
+		metric.WithDescription("Heap size target for the end of the GC cycle."), metric.WithUnit("By"))
+	err = errors.Join(err, e)
+	rm.gcCount, e = meter.Int64Counter("go.gc.count",
+		metric.WithDescription("Count of completed GC cycles."), metric.WithUnit("{gc_cycle}"))
+	err = errors.Join(err, e)
+	rm.gcPauses, e = meter.Int64ObservableCounter("go.gc.pause.count",
+		metric.WithDescription("Count of stop-the-world GC pauses lasting at most bucket.upper_bound seconds."),
+		metric.WithUnit("{pause}"))
+	err = errors.Join(err, e)
+	rm.schedule, e = meter.Int64ObservableCounter("go.schedule.wait.count",
+		metric.WithDescription("Count of times goroutines waited at most bucket.upper_bound seconds in a runnable state before running."),
+		metric.WithUnit("{wait}"))
+	err = errors.Join(err, e)
+	rm.cpuTime, e = meter.Float64Counter("process.cpu.time",
+		metric.WithDescription("Total CPU seconds broken down by mode."), metric.WithUnit("s"))
+	err = errors.Join(err, e)
+	rm.openFDs, e = meter.Int64Gauge("process.open_file_descriptor.count",
+		metric.WithDescription("Number of file descriptors in use by the process."), metric.WithUnit("{file_descriptor}"))
+	err = errors.Join(err, e)
+	if err != nil {
+		return nil, err
+	}
+	rm.registration, err = meter.RegisterCallback(rm.observeLatencies, rm.gcPauses, rm.schedule)
+	if err != nil {
+		return nil, err
+	}
+	return rm, nil
+}
+
+// observeLatencies reports the GC pause and scheduling latency histograms of the
+// runtime, with their buckets merged into latencyBuckets. The work is proportional to
+// the number of buckets, not the number of pauses or waits.
+func (rm *RuntimeMetrics) observeLatencies(_ context.Context, o metric.Observer) error {
+	samples := []metrics.Sample{{Name: gcPausesSample}, {Name: schedLatencySample}}
+	metrics.Read(samples)
+	observeHistogramBuckets(o, rm.gcPauses, samples[0])
+	observeHistogramBuckets(o, rm.schedule, samples[1])
+	return nil
+}
+
+// run records the metrics every interval until ctx is canceled.
+func (rm *RuntimeMetrics) run(ctx context.Context) {
+	defer close(rm.done)
+	ticker := time.NewTicker(rm.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			rm.record(ctx)
+		}
+	}
+}
+
+// record reads the runtime and process metrics and records them. Cumulative values
+// are recorded as the change since the previous call.
+func (rm *RuntimeMetrics) record(ctx context.Context) {
+	metrics.Read(rm.samples)
+	var memoryTotal, memoryFreed int64
+	for _, s := range rm.samples {
+		switch s.Name {
+		case memoryTotalSample:
+			memoryTotal = sampleInt64(s)
+		case memoryFreedSample:
+			memoryFreed = sampleInt64(s)
+		case goroutinesSample:
+			rm.goroutines.Record(ctx, sampleInt64(s))
+		case heapObjectsSample:
+			rm.heapObjects.Record(ctx, sampleInt64(s))
+		case heapGoalSample:
+			rm.heapGoal.Record(ctx, sampleInt64(s))
+		case gcCyclesSample:
+			if s.Value.Kind() == metrics.KindUint64 {
+				cycles := s.Value.Uint64()
+				rm.gcCount.Add(ctx, int64(cycles-rm.lastGCCycles))
+				rm.lastGCCycles = cycles
+			}
+		}
+	}
+	rm.memoryUsed.Record(ctx, memoryTotal-memoryFreed)
+
+	if cpu, ok := readProcessCPU(); ok {
+		rm.cpuTime.Add(ctx, (cpu.user - rm.lastCPU.user).Seconds(),
+			metric.WithAttributes(attribute.String("cpu.mode", "user")))
+		rm.cpuTime.Add(ctx, (cpu.system - rm.lastCPU.system).Seconds(),
+			metric.WithAttributes(attribute.String("cpu.mode", "system")))
+		rm.lastCPU = cpu
+	}
+	if fds, ok := countOpenFDs(); ok {
+		rm.openFDs.Record(ctx, fds)
+	}
+}
+
+// Stop stops recording and waits for the recording goroutine to exit or ctx to be
+// done. Calling Stop more than once is safe.
+func (rm *RuntimeMetrics) Stop(ctx context.Context) error {
+	var err error
+	rm.stopOnce.Do(func() {
+		rm.cancel()
+		err = rm.registration.Unregister()
+	})
+	select {
+	case <-rm.done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// sampleInt64 returns the value of a uint64 sample, or 0 if the runtime does not
+// support it.
+func sampleInt64(s metrics.Sample) int64 {
+	if s.Value.Kind() != metrics.KindUint64 {
+		return 0
+	}
+	return int64(min(s.Value.Uint64(), math.MaxInt64))
+}
+
+// observeHistogramBuckets observes the cumulative counts of a runtime histogram per
+// latency bucket, assigning each runtime bucket by a representative value.
+func observeHistogramBuckets(o metric.Observer, counter metric.Int64ObservableCounter, s metrics.Sample) {
+	if s.Value.Kind() != metrics.KindFloat64Histogram {
+		return
+	}
+	hist := s.Value.Float64Histogram()
+	counts := mergeHistogramBuckets(hist.Counts, hist.Buckets)
+	for i, count := range counts {
+		o.ObserveInt64(counter, int64(min(count, math.MaxInt64)), latencyBucketAttrs[i])
+	}
+}
+
+// mergeHistogramBuckets sums the counts of runtime histogram buckets into
+// cumulative counts per latency bucket: each counts the values up to its bound, and
+// a final bucket counts all values.
+func mergeHistogramBuckets(counts []uint64, buckets []float64) []uint64 {
+	merged := make([]uint64, len(latencyBuckets)+1)
+	for i, count := range counts {
+		if count == 0 {
+			continue
+		}
+		value := bucketValue(buckets[i], buckets[i+1])
+		merged[sort.SearchFloat64s(latencyBuckets, value)] += count
+	}
+	for i := 1; i < len(merged); i++ {
+		merged[i] += merged[i-1]
+	}
+	return merged
+}
+
+// bucketValue returns the midpoint of a runtime histogram bucket, or its finite
+// bound if the bucket is unbounded.
+func bucketValue(lower, upper float64) float64 {
+	switch {
+	case math.IsInf(lower, -1):
+		return upper
+	case math.IsInf(upper, 1):
+		return lower
+	default:
+		return lower + (upper-lower)/2
+	}
+}
+
+// processCPU is the CPU time consumed by the process.
+type processCPU struct {
+	user   time.Duration
+	system time.Duration
+}
@@ -0,0 +1,13 @@
+//go:build !unix
+
+package provider
+
+// readProcessCPU is not supported on this platform.
+func readProcessCPU() (processCPU, bool) {
+	return processCPU{}, false
+}
+
+// countOpenFDs is not supported on this platform.
+func countOpenFDs() (int64, bool) {
+	return 0, false
+}
@@ -0,0 +1,195 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"math"
+	"runtime"
+	"testing"
+	"time"
+
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+
+	"github.com/kernelshard/otelkit/internal/config"
+)
+
+func collectMetricNames(t *testing.T, reader sdkmetric.Reader) map[string]metricdata.Metrics {
+	t.Helper()
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatalf("Collect failed: %v", err)
+	}
+	names := map[string]metricdata.Metrics{}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			names[m.Name] = m
+		}
+	}
+	return names
+}
+
+func TestProvider_StartRuntimeMetrics(t *testing.T) {
+	ctx := context.Background()
+	t.Cleanup(ResetGlobal)
+
+	reader := sdkmetric.NewManualReader()
+	p, err := New(ctx, newTestHandleConfig(&bytes.Buffer{}).WithMetricReader(reader))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	runtime.GC() // so that the first collection includes GC pauses
+	if err := p.StartRuntimeMetrics(time.Hour); err != nil {
+		t.Fatalf("StartRuntimeMetrics failed: %v", err)
+	}
+
+	names := collectMetricNames(t, reader)
+	for _, name := range []string{
+		"go.goroutine.count", "go.memory.used", "go.memory.heap.objects", "go.memory.gc.goal",
+		"go.gc.count", "go.gc.pause.count", "go.schedule.wait.count",
+	} {
+		if _, ok := names[name]; !ok {
+			t.Errorf("Expected metric %s, got %v", name, names)
+		}
+	}
+	pauses, ok := names["go.gc.pause.count"].Data.(metricdata.Sum[int64])
+	if !ok || len(pauses.DataPoints) != len(latencyBuckets)+1 {
+		t.Fatalf("Expected one GC pause data point per bucket, got %+v", names["go.gc.pause.count"].Data)
+	}
+	var total, below int64
+	for _, dp := range pauses.DataPoints {
+		switch bound, _ := dp.Attributes.Value(latencyBoundKey); bound.AsString() {
+		case "+Inf":
+			total = dp.Value
+		case "1":
+			below = dp.Value
+		}
+	}
+	if total == 0 {
+		t.Error("Expected GC pauses to be counted")
+	}
+	if below > total {
+		t.Errorf("Expected cumulative buckets, got %d pauses up to 1s of %d", below, total)
+	}
+	if runtime.GOOS == "linux" {
+		if _, ok := names["process.cpu.time"]; !ok {
+			t.Error("Expected process.cpu.time on linux")
+		}
+		fds, ok := names["process.open_file_descriptor.count"].Data.(metricdata.Gauge[int64])
+		if !ok || len(fds.DataPoints) != 1 || fds.DataPoints[0].Value <= 0 {
+			t.Errorf("Expected a positive file descriptor count, got %+v", names["process.open_file_descriptor.count"].Data)
+		}
+	}
+	goroutines, ok := names["go.goroutine.count"].Data.(metricdata.Gauge[int64])
+	if !ok || len(goroutines.DataPoints) != 1 || goroutines.DataPoints[0].Value <= 0 {
+		t.Errorf("Expected a positive goroutine count, got %+v", names["go.goroutine.count"].Data)
+	}
+
+	rm := p.runtime
+	if err := p.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown failed: %v", err)
+	}
+	select {
+	case <-rm.done:
+	default:
+		t.Error("Expected Shutdown to stop runtime metrics")
+	}
+}
+
+func TestProvider_StartRuntimeMetricsWithoutMetrics(t *testing.T) {
+	ctx := context.Background()
+	t.Cleanup(ResetGlobal)
+
+	p, err := New(ctx, newTestHandleConfig(&bytes.Buffer{}))
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	defer p.Shutdown(ctx)
+
+	var configErr *config.ConfigError
+	if err := p.StartRuntimeMetrics(0); !errors.As(err, &configErr) || configErr.Field != "MetricsExporter" {
+		t.Errorf("Expected a MetricsExporter ConfigError, got %v", err)
+	}
+}
+
+func TestStartRuntimeMetrics_Interval(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	defer mp.Shutdown(context.Background())
+
+	if _, err := StartRuntimeMetrics(mp, -time.Second); err == nil {
+		t.Error("Expected a negative interval to be rejected")
+	}
+
+	rm, err := StartRuntimeMetrics(mp, 10*time.Millisecond)
+	if err != nil {
+		t.Fatalf("StartRuntimeMetrics failed: %v", err)
+	}
+	runtime.GC()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		gc, _ := collectMetricNames(t, reader)["go.gc.count"].Data.(metricdata.Sum[int64])
+		if len(gc.DataPoints) == 1 && gc.DataPoints[0].Value > 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("Expected GC cycles to be recorded by periodic collection")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := rm.Stop(ctx); err != nil {
+		t.Errorf("Stop failed: %v", err)
+	}
+	if err := rm.Stop(ctx); err != nil {
+		t.Errorf("Expected a second Stop to succeed, got %v", err)
+	}
+}
+
+func TestMergeHistogramBuckets(t *testing.T) {
+	buckets := []float64{math.Inf(-1), 1e-6, 2e-6, 1e-3, 1.5e-3, 10, math.Inf(1)}
+	counts := []uint64{4, 1, 2, 3, 5, 6}
+
+	merged := mergeHistogramBuckets(counts, buckets)
+	if len(merged) != len(latencyBuckets)+1 {
+		t.Fatalf("Expected %d buckets, got %d", len(latencyBuckets)+1, len(merged))
+	}
+	want := map[int]uint64{
+		0:                   4,  // up to 1µs
+		1:                   5,  // + 1.5µs
+		2:                   5,  // up to 10µs
+		3:                   5,  // up to 50µs
+		4:                   5,  // up to 100µs
+		5:                   5,  // up to 250µs
+		6:                   5,  // up to 500µs
+		7:                   7,  // + 0.5ms
+		8:                   10, // + 1.25ms
+		len(latencyBuckets): 21, // all
+	}
+	for i := 9; i < len(latencyBuckets); i++ {
+		want[i] = 10
+	}
+	for i, count := range merged {
+		if count != want[i] {
+			t.Errorf("Bucket %d: got %d, want %d", i, count, want[i])
+		}
+	}
+}
+
+func TestBucketValue(t *testing.T) {
+	tests := []struct {
+		lower, upper, want float64
+	}{
+		{math.Inf(-1), 1e-6, 1e-6},
+		{1e-3, 3e-3, 2e-3},
+		{1, math.Inf(1), 1},
+	}
+	for _, tt := range tests {
+		if got := bucketValue(tt.lower, tt.upper); math.Abs(got-tt.want) > 1e-12 {
+			t.Errorf("bucketValue(%v, %v) = %v, want %v", tt.lower, tt.upper, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,35 @@
+//go:build unix
+
+package provider
+
+import (
+	"os"
+	"syscall"
+	"time"
+)
+
+// readProcessCPU returns the user and system CPU time of the process.
+func readProcessCPU() (processCPU, bool) {
+	var usage syscall.Rusage
+	if err := syscall.Getrusage(syscall.RUSAGE_SELF, &usage); err != nil {
+		return processCPU{}, false
+	}
+	return processCPU{
+		user:   time.Duration(usage.Utime.Nano()),
+		system: time.Duration(usage.Stime.Nano()),
+	}, true
+}
+
+// countOpenFDs returns the number of open file descriptors, read from /proc/self/fd
+// on Linux and /dev/fd elsewhere.
+func countOpenFDs() (int64, bool) {
+	for _, dir := range []string{"/proc/self/fd", "/dev/fd"} {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		// Reading the directory holds one descriptor open itself.
+		return int64(len(entries) - 1), true
+	}
+	return 0, false
+}
@@ -0,0 +1,177 @@
+package provider
+
+import (
+	"context"
+	"errors"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+
+	"github.com/kernelshard/otelkit/internal/config"
+)
+
+// spanLimits converts the limits of cfg for the tracer provider, replacing zero limits
+// with the defaults so that a Config built as a struct literal records spans in full.
+// The SDK's value length limit is left unlimited: the SDK would cut values when they
+// are recorded, before a RedactionProcessor sees them, so that a secret straddling
+// the cut would no longer match its pattern and leak in part. Values are cut by
+// truncatingProcessor instead, after redaction.
+func spanLimits(cfg *config.Config) sdktrace.SpanLimits {
+	return sdktrace.SpanLimits{
+		AttributeCountLimit:         limitOrDefault(cfg.SpanAttributeCountLimit, config.DefaultSpanAttributeCountLimit),
+		AttributeValueLengthLimit:   -1,
+		EventCountLimit:             limitOrDefault(cfg.SpanEventCountLimit, config.DefaultSpanEventCountLimit),
+		LinkCountLimit:              limitOrDefault(cfg.SpanLinkCountLimit, config.DefaultSpanLinkCountLimit),
+		AttributePerEventCountLimit: limitOrDefault(cfg.EventAttributeCountLimit, config.DefaultEventAttributeCountLimit),
+		AttributePerLinkCountLimit:  limitOrDefault(cfg.LinkAttributeCountLimit, config.DefaultLinkAttributeCountLimit),
+	}
+}
+
+// limitOrDefault returns limit, or def if limit is zero.
+func limitOrDefault(limit, def int) int {
+	if limit == 0 {
+		return def
+	}
+	return limit
+}
+
+// truncatingProcessor cuts string attribute values of ended spans, their events and
+// links that exceed limit characters and appends config.TruncatedValueSuffix, before
+// passing the spans to the downstream processors.
+type truncatingProcessor struct {
+	next  []sdktrace.SpanProcessor
+	limit int
+}
+
+func (p *truncatingProcessor) OnStart(parent context.Context, s sdktrace.ReadWriteSpan) {
+	for _, sp := range p.next {
+		sp.OnStart(parent, s)
+	}
+}
+
+func (p *truncatingProcessor) OnEnd(s sdktrace.ReadOnlySpan) {
+	truncated := p.truncate(s)
+	for _, sp := range p.next {
+		sp.OnEnd(truncated)
+	}
+}
+
+func (p *truncatingProcessor) Shutdown(ctx context.Context) error {
+	var err error
+	for _, sp := range p.next {
+		err = errors.Join(err, sp.Shutdown(ctx))
+	}
+	return err
+}
+
+func (p *truncatingProcessor) ForceFlush(ctx context.Context) error {
+	var err error
+	for _, sp := range p.next {
+		err = errors.Join(err, sp.ForceFlush(ctx))
+	}
+	return err
+}
+
+// truncatedSpan overrides the attributes, events and links of a span.
+type truncatedSpan struct {
+	sdktrace.ReadOnlySpan
+	attrs  []attribute.KeyValue
+	events []sdktrace.Event
+	links  []sdktrace.Link
+}
+
+func (s truncatedSpan) Attributes() []attribute.KeyValue { return s.attrs }
+func (s truncatedSpan) Events() []sdktrace.Event         { return s.events }
+func (s truncatedSpan) Links() []sdktrace.Link           { return s.links }
+
+// truncate returns s with oversized values truncated, or s itself if there are none.
+func (p *truncatingProcessor) truncate(s sdktrace.ReadOnlySpan) sdktrace.ReadOnlySpan {
+	attrs, changed := p.truncateAttributes(s.Attributes())
+
+	events := s.Events()
+	var eventsChanged bool
+	for i, ev := range events {
+		evAttrs, ok := p.truncateAttributes(ev.Attributes)
+		if !ok {
+			continue
+		}
+		if !eventsChanged {
+			events = append([]sdktrace.Event(nil), events...)
+			eventsChanged = true
+		}
+		events[i].Attributes = evAttrs
+	}
+
+	links := s.Links()
+	var linksChanged bool
+	for i, l := range links {
+		linkAttrs, ok := p.truncateAttributes(l.Attributes)
+		if !ok {
+			continue
+		}
+		if !linksChanged {
+			links = append([]sdktrace.Link(nil), links...)
+			linksChanged = true
+		}
+		links[i].Attributes = linkAttrs
+	}
+
+	if !changed && !eventsChanged && !linksChanged {
+		return s
+	}
+	return truncatedSpan{ReadOnlySpan: s, attrs: attrs, events: events, links: links}
+}
+
+// truncateAttributes returns a copy of attrs with oversized values truncated and
+// true, or attrs and false if no value exceeds the limit.
+func (p *truncatingProcessor) truncateAttributes(attrs []attribute.KeyValue) ([]attribute.KeyValue, bool) {
+	var out []attribute.KeyValue
+	for i, kv := range attrs {
+		switch kv.Value.Type() {
+		case attribute.STRING:
+			v, ok := truncateValue(kv.Value.AsString(), p.limit)
+			if !ok {
+				continue
+			}
+			kv = attribute.String(string(kv.Key), v)
+		case attribute.STRINGSLICE:
+			values := kv.Value.AsStringSlice()
+			var changed bool
+			for j, v := range values {
+				if t, ok := truncateValue(v, p.limit); ok {
+					values[j], changed = t, true
+				}
+			}
+			if !changed {
+				continue
+			}
+			kv = attribute.StringSlice(string(kv.Key), values)
+		default:
+			continue
+		}
+		if out == nil {
+			out = append([]attribute.KeyValue(nil), attrs...)
+		}
+		out[i] = kv
+	}
+	if out == nil {
+		return attrs, false
+	}
+	return out, true
+}
+
+// truncateValue cuts v to limit characters followed by config.TruncatedValueSuffix
+// if it is longer than limit characters.
+func truncateValue(v string, limit int) (string, bool) {
+	if len(v) <= limit {
+		return v, false
+	}
+	n := 0
+	for i := range v {
+		if n == limit {
+			return v[:i] + config.TruncatedValueSuffix, true
+		}
+		n++
+	}
+	return v, false
+}
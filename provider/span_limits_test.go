@@ -0,0 +1,219 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/kernelshard/otelkit/internal/config"
+)
+
+func TestNewProvider_WithSpanLimits(t *testing.T) {
+	ctx := context.Background()
+	var buf bytes.Buffer
+	pc := NewProviderConfig("limits-service", "1.0.0").
+		WithSampling(config.SamplingAlwaysOn, 1).
+		WithConsoleExporter(&buf, true).
+		WithSpanLimits(2, 8, 1, 128)
+
+	tp, err := newProvider(ctx, pc)
+	if err != nil {
+		t.Fatalf("newProvider failed: %v", err)
+	}
+	recorder := tracetest.NewSpanRecorder()
+	tp.RegisterSpanProcessor(&truncatingProcessor{next: []sdktrace.SpanProcessor{recorder}, limit: 8})
+
+	link := trace.Link{
+		SpanContext: trace.NewSpanContext(trace.SpanContextConfig{TraceID: trace.TraceID{1}, SpanID: trace.SpanID{1}}),
+		Attributes:  []attribute.KeyValue{attribute.String("link.reason", "retry of a long request")},
+	}
+	_, span := tp.Tracer("test").Start(ctx, "op", trace.WithLinks(link))
+	span.SetAttributes(
+		attribute.String("short", "12345678"),
+		attribute.String("long", strings.Repeat("123456789", 100)),
+		attribute.String("dropped", "x"),
+	)
+	span.AddEvent("evicted")
+	span.AddEvent("exception", trace.WithAttributes(attribute.String("exception.stacktrace", strings.Repeat("frame\n", 10))))
+	span.End()
+	if err := tp.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown failed: %v", err)
+	}
+	if out := buf.String(); !strings.Contains(out, "12345678"+config.TruncatedValueSuffix) || strings.Contains(out, "123456789") {
+		t.Errorf("Expected the exported value to be truncated, got %s", out)
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("Expected 1 span, got %d", len(spans))
+	}
+	s := spans[0]
+	want := []attribute.KeyValue{
+		attribute.String("short", "12345678"),
+		attribute.String("long", "12345678"+config.TruncatedValueSuffix),
+	}
+	if got := s.Attributes(); len(got) != 2 || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+	if s.DroppedAttributes() != 1 {
+		t.Errorf("Expected 1 dropped attribute, got %d", s.DroppedAttributes())
+	}
+	events := s.Events()
+	if len(events) != 1 || s.DroppedEvents() != 1 {
+		t.Fatalf("Expected 1 event and 1 dropped, got %d and %d", len(events), s.DroppedEvents())
+	}
+	if got := events[0].Attributes[0].Value.AsString(); got != "frame\nfr"+config.TruncatedValueSuffix {
+		t.Errorf("Expected a truncated stack trace, got %q", got)
+	}
+	if links := s.Links(); len(links) != 1 || links[0].Attributes[0].Value.AsString() != "retry of"+config.TruncatedValueSuffix {
+		t.Errorf("Expected a truncated link attribute, got %v", links)
+	}
+}
+
+func TestNewProvider_RedactsBeforeTruncating(t *testing.T) {
+	ctx := context.Background()
+	var buf bytes.Buffer
+	pc := NewProviderConfig("limits-service", "1.0.0").
+		WithSampling(config.SamplingAlwaysOn, 1).
+		WithConsoleExporter(&buf, true).
+		WithSpanLimits(128, 16, 128, 128).
+		WithRedaction(RedactionRule{Pattern: regexp.MustCompile(`secret-\d{6}`), Action: RedactMask})
+
+	tp, err := newProvider(ctx, pc)
+	if err != nil {
+		t.Fatalf("newProvider failed: %v", err)
+	}
+	_, span := tp.Tracer("test").Start(ctx, "op")
+	// The secret straddles the value length limit.
+	span.SetAttributes(attribute.String("note", "token secret-123456 expired"))
+	span.End()
+	if err := tp.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown failed: %v", err)
+	}
+
+	out := buf.String()
+	if strings.Contains(out, "secret-") {
+		t.Errorf("Expected the secret to be redacted before the value was cut, got %s", out)
+	}
+	if !strings.Contains(out, "token [REDACTED]"+config.TruncatedValueSuffix) {
+		t.Errorf("Expected the redacted value to be truncated, got %s", out)
+	}
+}
+
+func TestTruncateValue(t *testing.T) {
+	tests := []struct {
+		value string
+		limit int
+		want  string
+	}{
+		{"short", 8, "short"},
+		{"exactly8", 8, "exactly8"},
+		{"héllo wörld", 5, "héllo" + config.TruncatedValueSuffix},
+		{"héllo", 5, "héllo"},
+		{"anything", 0, config.TruncatedValueSuffix},
+	}
+	for _, tt := range tests {
+		if got, _ := truncateValue(tt.value, tt.limit); got != tt.want {
+			t.Errorf("truncateValue(%q, %d) = %q, want %q", tt.value, tt.limit, got, tt.want)
+		}
+	}
+}
+
+func TestSpanLimits_ValueLengthLeftToProcessor(t *testing.T) {
+	cfg := config.NewConfig("svc", "1.0.0")
+	cfg.SpanAttributeValueLengthLimit = 8
+	if limits := spanLimits(cfg); limits.AttributeValueLengthLimit != -1 || limits.AttributeCountLimit != cfg.SpanAttributeCountLimit {
+		t.Errorf("Expected the SDK value length limit to stay unlimited, got %+v", limits)
+	}
+}
+
+func TestNewProvider_ZeroAndNegativeSpanLimits(t *testing.T) {
+	ctx := context.Background()
+	pc := NewProviderConfig("limits-service", "1.0.0").
+		WithSampling(config.SamplingAlwaysOn, 1).
+		WithConsoleExporter(io.Discard, true).
+		WithSpanLimits(0, 0, 0, -1)
+
+	tp, err := newProvider(ctx, pc)
+	if err != nil {
+		t.Fatalf("newProvider failed: %v", err)
+	}
+	recorder := tracetest.NewSpanRecorder()
+	tp.RegisterSpanProcessor(recorder)
+
+	links := make([]trace.Link, 200)
+	for i := range links {
+		links[i] = trace.Link{SpanContext: trace.NewSpanContext(trace.SpanContextConfig{
+			TraceID: trace.TraceID{1}, SpanID: trace.SpanID{byte(i + 1)},
+		})}
+	}
+	_, span := tp.Tracer("test").Start(ctx, "op", trace.WithLinks(links...))
+	for i := range 200 {
+		span.SetAttributes(attribute.String(fmt.Sprintf("key%d", i), strings.Repeat("x", 100)))
+	}
+	span.AddEvent("kept")
+	span.End()
+	_ = tp.Shutdown(ctx)
+
+	s := recorder.Ended()[0]
+	if len(s.Attributes()) != config.DefaultSpanAttributeCountLimit || s.DroppedAttributes() != 200-config.DefaultSpanAttributeCountLimit {
+		t.Errorf("Expected a zero attribute limit to keep the default of %d, got %d", config.DefaultSpanAttributeCountLimit, len(s.Attributes()))
+	}
+	if v := s.Attributes()[0].Value.AsString(); len(v) != 100 {
+		t.Errorf("Expected a zero value length limit to keep values whole, got %q", v)
+	}
+	if len(s.Events()) != 1 {
+		t.Errorf("Expected a zero event limit to keep the default, got %d events", len(s.Events()))
+	}
+	if len(s.Links()) != len(links) {
+		t.Errorf("Expected a negative link limit to keep all %d links, got %d", len(links), len(s.Links()))
+	}
+}
+
+func TestNewProvider_StructLiteralConfigKeepsSpanData(t *testing.T) {
+	ctx := context.Background()
+	pc := &ProviderConfig{
+		Config: &config.Config{
+			ServiceName:          "limits-service",
+			ServiceVersion:       "1.0.0",
+			OTLPExporterProtocol: config.ProtocolNone,
+			SamplingType:         config.SamplingAlwaysOn,
+			SamplingRatio:        1,
+		},
+		BatchTimeout:       time.Second,
+		ExportTimeout:      time.Second,
+		MaxExportBatchSize: 512,
+		MaxQueueSize:       2048,
+	}
+
+	tp, err := newProvider(ctx, pc)
+	if err != nil {
+		t.Fatalf("newProvider failed: %v", err)
+	}
+	recorder := tracetest.NewSpanRecorder()
+	tp.RegisterSpanProcessor(recorder)
+
+	_, span := tp.Tracer("test").Start(ctx, "op")
+	span.SetAttributes(attribute.String("http.route", "/orders/{id}"))
+	span.AddEvent("retry", trace.WithAttributes(attribute.Int("attempt", 2)))
+	span.End()
+	_ = tp.Shutdown(ctx)
+
+	s := recorder.Ended()[0]
+	if len(s.Attributes()) != 1 || s.Attributes()[0].Value.AsString() != "/orders/{id}" {
+		t.Errorf("Expected the attribute to be recorded in full, got %v", s.Attributes())
+	}
+	if len(s.Events()) != 1 || len(s.Events()[0].Attributes) != 1 {
+		t.Errorf("Expected the event and its attribute to be recorded, got %+v", s.Events())
+	}
+}
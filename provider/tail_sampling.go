@@ -0,0 +1,396 @@
+package provider
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/kernelshard/otelkit/internal/config"
+)
+
+// TailSamplingPolicy decides whether a buffered trace is kept. It receives every
+// span of the trace that ended within the decision window, in the order they ended.
+type TailSamplingPolicy func(spans []sdktrace.ReadOnlySpan) bool
+
+// KeepErrorTraces keeps traces in which any span has an error status.
+func KeepErrorTraces() TailSamplingPolicy {
+	return func(spans []sdktrace.ReadOnlySpan) bool {
+		for _, s := range spans {
+			if s.Status().Code == codes.Error {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// KeepSlowTraces keeps traces whose root span took at least threshold. If the root
+// span has not ended (or lives in another process), the time between the earliest
+// start and the latest end of the buffered spans is used instead.
+func KeepSlowTraces(threshold time.Duration) TailSamplingPolicy {
+	return func(spans []sdktrace.ReadOnlySpan) bool {
+		return traceLatency(spans) >= threshold
+	}
+}
+
+// KeepTracesWithAttribute keeps traces in which any span has the attribute with the
+// given value, e.g. attribute.String("tenant.tier", "enterprise").
+func KeepTracesWithAttribute(kv attribute.KeyValue) TailSamplingPolicy {
+	return func(spans []sdktrace.ReadOnlySpan) bool {
+		for _, s := range spans {
+			for _, attr := range s.Attributes() {
+				if attr.Key == kv.Key && attr.Value == kv.Value {
+					return true
+				}
+			}
+		}
+		return false
+	}
+}
+
+// TailSamplingConfig configures the tail sampling processor. See
+// ProviderConfig.WithTailSampling.
+type TailSamplingConfig struct {
+	// DecisionWait is how long spans of a trace are buffered, counted from the first
+	// ended span, before the policies are evaluated. Default: 10 seconds.
+	DecisionWait time.Duration
+
+	// MaxTraces caps the number of buffered traces. When it is reached the oldest
+	// trace is decided early to make room. Default: 10000.
+	MaxTraces int
+
+	// MaxSpansPerTrace caps the spans buffered per trace; further spans are dropped.
+	// Default: 1000.
+	MaxSpansPerTrace int
+
+	// Policies are evaluated in order; the trace is kept if any of them matches.
+	Policies []TailSamplingPolicy
+
+	// FallbackRatio is the share of traces kept when no policy matches (0.0 - 1.0).
+	// The decision is derived from the trace ID like TraceIDRatioBased.
+	FallbackRatio float64
+}
+
+// TailSamplingStats is a snapshot of the tail sampling processor's counters.
+type TailSamplingStats struct {
+	BufferedTraces int    // Traces waiting for a decision
+	TracesKept     uint64 // Traces forwarded to the exporters
+	TracesDropped  uint64 // Traces discarded by the policies
+	EvictedTraces  uint64 // Traces decided early because MaxTraces was reached
+	SpansDropped   uint64 // Spans of discarded traces and spans over MaxSpansPerTrace
+	LateSpans      uint64 // Spans that ended after their trace had been decided
+}
+
+// TailSamplingProcessor buffers the spans of each trace for a decision window and
+// forwards whole traces to the downstream processors if a policy keeps them. Head
+// sampling still runs first, so only traces sampled by the tracer provider's sampler
+// reach it.
+//
+// Spans that end after their trace was decided follow that decision and are counted
+// as late. Decisions are remembered for one more decision window; spans arriving
+// even later start a new buffer.
+type TailSamplingProcessor struct {
+	next         []sdktrace.SpanProcessor
+	decisionWait time.Duration
+	maxTraces    int
+	maxSpans     int
+	policies     []TailSamplingPolicy
+	ratioBound   uint64
+
+	mu      sync.Mutex
+	traces  map[trace.TraceID]*tailTrace
+	order   []trace.TraceID // Buffered traces, oldest first; may hold decided IDs
+	decided map[trace.TraceID]tailDecision
+	history []trace.TraceID // Decided traces, oldest first
+	stats   TailSamplingStats
+	stopped bool
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+type tailTrace struct {
+	spans []sdktrace.ReadOnlySpan
+	first time.Time
+}
+
+type tailDecision struct {
+	keep bool
+	at   time.Time
+}
+
+// NewTailSamplingProcessor creates a tail sampling processor forwarding kept traces
+// to next, typically batch span processors. Zero-valued limits use the defaults.
+func NewTailSamplingProcessor(cfg TailSamplingConfig, next ...sdktrace.SpanProcessor) (*TailSamplingProcessor, error) {
+	if cfg.DecisionWait < 0 || cfg.MaxTraces < 0 || cfg.MaxSpansPerTrace < 0 {
+		return nil, &config.ConfigError{Field: "TailSampling", Message: config.ErrInvalidTailSampling}
+	}
+	if cfg.FallbackRatio < 0 || cfg.FallbackRatio > 1 {
+		return nil, &config.ConfigError{Field: "TailSampling.FallbackRatio", Message: config.ErrInvalidSamplingRatio}
+	}
+	if cfg.DecisionWait == 0 {
+		cfg.DecisionWait = config.DefaultTailSamplingDecisionWait
+	}
+	if cfg.MaxTraces == 0 {
+		cfg.MaxTraces = config.DefaultTailSamplingMaxTraces
+	}
+	if cfg.MaxSpansPerTrace == 0 {
+		cfg.MaxSpansPerTrace = config.DefaultTailSamplingMaxSpans
+	}
+
+	p := &TailSamplingProcessor{
+		next:         next,
+		decisionWait: cfg.DecisionWait,
+		maxTraces:    cfg.MaxTraces,
+		maxSpans:     cfg.MaxSpansPerTrace,
+		policies:     append([]TailSamplingPolicy(nil), cfg.Policies...),
+		ratioBound:   uint64(cfg.FallbackRatio * (1 << 63)),
+		traces:       make(map[trace.TraceID]*tailTrace),
+		decided:      make(map[trace.TraceID]tailDecision),
+		stop:         make(chan struct{}),
+		done:         make(chan struct{}),
+	}
+	go p.decisionLoop()
+	return p, nil
+}
+
+// OnStart does nothing; spans are only buffered once they end.
+func (p *TailSamplingProcessor) OnStart(parent context.Context, s sdktrace.ReadWriteSpan) {}
+
+// OnEnd buffers the span until its trace is decided.
+func (p *TailSamplingProcessor) OnEnd(s sdktrace.ReadOnlySpan) {
+	if !s.SpanContext().IsSampled() {
+		return
+	}
+	traceID := s.SpanContext().TraceID()
+
+	p.mu.Lock()
+	if p.stopped {
+		p.mu.Unlock()
+		return
+	}
+	if d, ok := p.decided[traceID]; ok {
+		p.stats.LateSpans++
+		if !d.keep {
+			p.stats.SpansDropped++
+		}
+		p.mu.Unlock()
+		if d.keep {
+			p.forward([]sdktrace.ReadOnlySpan{s})
+		}
+		return
+	}
+
+	var evicted []sdktrace.ReadOnlySpan
+	t, ok := p.traces[traceID]
+	if !ok {
+		if len(p.traces) >= p.maxTraces {
+			evicted = p.evictOldest(time.Now())
+		}
+		t = &tailTrace{first: time.Now()}
+		p.traces[traceID] = t
+		p.order = append(p.order, traceID)
+	}
+	if len(t.spans) < p.maxSpans {
+		t.spans = append(t.spans, s)
+	} else {
+		p.stats.SpansDropped++
+	}
+	p.mu.Unlock()
+
+	p.forward(evicted)
+}
+
+// Shutdown decides all buffered traces, then shuts down the downstream processors.
+func (p *TailSamplingProcessor) Shutdown(ctx context.Context) error {
+	p.mu.Lock()
+	if p.stopped {
+		p.mu.Unlock()
+		return nil
+	}
+	p.stopped = true
+	p.mu.Unlock()
+
+	close(p.stop)
+	<-p.done
+	p.forward(p.decideAll())
+
+	var errs []error
+	for _, sp := range p.next {
+		if err := sp.Shutdown(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ForceFlush decides all buffered traces without waiting for their decision window,
+// then flushes the downstream processors.
+func (p *TailSamplingProcessor) ForceFlush(ctx context.Context) error {
+	p.forward(p.decideAll())
+
+	var errs []error
+	for _, sp := range p.next {
+		if err := sp.ForceFlush(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Stats returns a snapshot of the processor's counters.
+func (p *TailSamplingProcessor) Stats() TailSamplingStats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	stats := p.stats
+	stats.BufferedTraces = len(p.traces)
+	return stats
+}
+
+// decisionLoop decides traces whose window has closed and forgets old decisions.
+func (p *TailSamplingProcessor) decisionLoop() {
+	defer close(p.done)
+	interval := p.decisionWait / 4
+	if interval > time.Second {
+		interval = time.Second
+	}
+	if interval < time.Millisecond {
+		interval = time.Millisecond
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.stop:
+			return
+		case now := <-ticker.C:
+			p.forward(p.decideExpired(now))
+		}
+	}
+}
+
+// decideExpired decides the traces buffered for at least the decision window and
+// returns the spans to forward.
+func (p *TailSamplingProcessor) decideExpired(now time.Time) []sdktrace.ReadOnlySpan {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var kept []sdktrace.ReadOnlySpan
+	for len(p.order) > 0 {
+		t, ok := p.traces[p.order[0]]
+		if ok && now.Sub(t.first) < p.decisionWait {
+			break
+		}
+		if ok {
+			kept = append(kept, p.decide(p.order[0], t, now)...)
+		}
+		p.order = p.order[1:]
+	}
+
+	for len(p.history) > 0 {
+		d, ok := p.decided[p.history[0]]
+		if ok && now.Sub(d.at) < p.decisionWait {
+			break
+		}
+		delete(p.decided, p.history[0])
+		p.history = p.history[1:]
+	}
+	return kept
+}
+
+// decideAll decides every buffered trace and returns the spans to forward.
+func (p *TailSamplingProcessor) decideAll() []sdktrace.ReadOnlySpan {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	var kept []sdktrace.ReadOnlySpan
+	for _, traceID := range p.order {
+		if t, ok := p.traces[traceID]; ok {
+			kept = append(kept, p.decide(traceID, t, now)...)
+		}
+	}
+	p.order = nil
+	return kept
+}
+
+// evictOldest decides the oldest buffered trace early to make room for a new one.
+// The caller must hold p.mu.
+func (p *TailSamplingProcessor) evictOldest(now time.Time) []sdktrace.ReadOnlySpan {
+	for len(p.order) > 0 {
+		traceID := p.order[0]
+		p.order = p.order[1:]
+		if t, ok := p.traces[traceID]; ok {
+			p.stats.EvictedTraces++
+			return p.decide(traceID, t, now)
+		}
+	}
+	return nil
+}
+
+// decide evaluates the policies for a buffered trace, records the decision and
+// returns its spans if the trace is kept. The caller must hold p.mu.
+func (p *TailSamplingProcessor) decide(traceID trace.TraceID, t *tailTrace, now time.Time) []sdktrace.ReadOnlySpan {
+	delete(p.traces, traceID)
+
+	keep := p.keep(traceID, t.spans)
+	p.decided[traceID] = tailDecision{keep: keep, at: now}
+	p.history = append(p.history, traceID)
+	if len(p.history) > p.maxTraces {
+		delete(p.decided, p.history[0])
+		p.history = p.history[1:]
+	}
+
+	if !keep {
+		p.stats.TracesDropped++
+		p.stats.SpansDropped += uint64(len(t.spans))
+		return nil
+	}
+	p.stats.TracesKept++
+	return t.spans
+}
+
+func (p *TailSamplingProcessor) keep(traceID trace.TraceID, spans []sdktrace.ReadOnlySpan) bool {
+	for _, policy := range p.policies {
+		if policy(spans) {
+			return true
+		}
+	}
+	return binary.BigEndian.Uint64(traceID[8:16])>>1 < p.ratioBound
+}
+
+// forward hands kept spans to the downstream processors. It must be called without
+// holding p.mu, since batch processors may block.
+func (p *TailSamplingProcessor) forward(spans []sdktrace.ReadOnlySpan) {
+	for _, s := range spans {
+		for _, sp := range p.next {
+			sp.OnEnd(s)
+		}
+	}
+}
+
+// traceLatency returns the duration of the local root span, or the extent of the
+// buffered spans if the root is not among them.
+func traceLatency(spans []sdktrace.ReadOnlySpan) time.Duration {
+	var start, end time.Time
+	for _, s := range spans {
+		if !s.Parent().IsValid() || s.Parent().IsRemote() {
+			return s.EndTime().Sub(s.StartTime())
+		}
+		if start.IsZero() || s.StartTime().Before(start) {
+			start = s.StartTime()
+		}
+		if s.EndTime().After(end) {
+			end = s.EndTime()
+		}
+	}
+	return end.Sub(start)
+}
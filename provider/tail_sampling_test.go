@@ -0,0 +1,247 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"sort"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/kernelshard/otelkit/internal/config"
+)
+
+func newTailSamplingTest(t *testing.T, cfg TailSamplingConfig) (*TailSamplingProcessor, *tracetest.SpanRecorder, trace.Tracer) {
+	t.Helper()
+	recorder := tracetest.NewSpanRecorder()
+	tsp, err := NewTailSamplingProcessor(cfg, recorder)
+	if err != nil {
+		t.Fatalf("NewTailSamplingProcessor failed: %v", err)
+	}
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(tsp))
+	t.Cleanup(func() { _ = tp.Shutdown(context.Background()) })
+	return tsp, recorder, tp.Tracer("tail-test")
+}
+
+func endedNames(recorder *tracetest.SpanRecorder) []string {
+	var names []string
+	for _, s := range recorder.Ended() {
+		names = append(names, s.Name())
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestTailSampling_Policies(t *testing.T) {
+	ctx := context.Background()
+	tsp, recorder, tr := newTailSamplingTest(t, TailSamplingConfig{
+		DecisionWait: time.Hour,
+		Policies: []TailSamplingPolicy{
+			KeepErrorTraces(),
+			KeepSlowTraces(time.Second),
+			KeepTracesWithAttribute(attribute.String("tenant.tier", "enterprise")),
+		},
+	})
+
+	// Fast, successful trace: dropped since the fallback ratio is zero.
+	octx, ok := tr.Start(ctx, "ok")
+	_, okChild := tr.Start(octx, "ok.child")
+	okChild.End()
+	ok.End()
+
+	// Error in a child span keeps the whole trace.
+	ectx, failed := tr.Start(ctx, "failed")
+	_, failedChild := tr.Start(ectx, "failed.child")
+	failedChild.SetStatus(codes.Error, "boom")
+	failedChild.End()
+	failed.End()
+
+	start := time.Now()
+	_, slow := tr.Start(ctx, "slow", trace.WithTimestamp(start))
+	slow.End(trace.WithTimestamp(start.Add(2 * time.Second)))
+
+	_, vip := tr.Start(ctx, "vip", trace.WithAttributes(attribute.String("tenant.tier", "enterprise")))
+	vip.End()
+
+	if got := len(recorder.Ended()); got != 0 {
+		t.Fatalf("Expected spans to be buffered until the decision, %d forwarded", got)
+	}
+	if err := tsp.ForceFlush(ctx); err != nil {
+		t.Fatalf("ForceFlush failed: %v", err)
+	}
+
+	want := []string{"failed", "failed.child", "slow", "vip"}
+	got := endedNames(recorder)
+	if len(got) != len(want) {
+		t.Fatalf("Expected kept spans %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Expected kept spans %v, got %v", want, got)
+			break
+		}
+	}
+
+	stats := tsp.Stats()
+	if stats.TracesKept != 3 || stats.TracesDropped != 1 || stats.SpansDropped != 2 || stats.BufferedTraces != 0 {
+		t.Errorf("Unexpected stats: %+v", stats)
+	}
+}
+
+func TestTailSampling_DecisionWindow(t *testing.T) {
+	_, recorder, tr := newTailSamplingTest(t, TailSamplingConfig{
+		DecisionWait:  20 * time.Millisecond,
+		FallbackRatio: 1,
+	})
+
+	_, span := tr.Start(context.Background(), "op")
+	span.End()
+	if got := len(recorder.Ended()); got != 0 {
+		t.Fatalf("Expected span to wait for the decision window, %d forwarded", got)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for len(recorder.Ended()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if got := len(recorder.Ended()); got != 1 {
+		t.Errorf("Expected the trace to be kept by the fallback ratio after the window, got %d spans", got)
+	}
+}
+
+func TestTailSampling_LateSpansFollowDecision(t *testing.T) {
+	ctx := context.Background()
+	tsp, recorder, tr := newTailSamplingTest(t, TailSamplingConfig{
+		DecisionWait: time.Hour,
+		Policies:     []TailSamplingPolicy{KeepErrorTraces()},
+	})
+
+	kctx, kept := tr.Start(ctx, "kept")
+	kept.SetStatus(codes.Error, "boom")
+	kept.End()
+	dctx, dropped := tr.Start(ctx, "dropped")
+	dropped.End()
+	_ = tsp.ForceFlush(ctx)
+
+	// Children ending after the decision, e.g. from a detached goroutine.
+	_, keptLate := tr.Start(kctx, "kept.late")
+	keptLate.End()
+	_, droppedLate := tr.Start(dctx, "dropped.late")
+	droppedLate.End()
+
+	got := endedNames(recorder)
+	if len(got) != 2 || got[0] != "kept" || got[1] != "kept.late" {
+		t.Errorf("Expected late span to follow the kept decision, got %v", got)
+	}
+	if stats := tsp.Stats(); stats.LateSpans != 2 || stats.SpansDropped != 2 {
+		t.Errorf("Expected 2 late and 2 dropped spans, got %+v", stats)
+	}
+}
+
+func TestTailSampling_MemoryLimits(t *testing.T) {
+	ctx := context.Background()
+	tsp, recorder, tr := newTailSamplingTest(t, TailSamplingConfig{
+		DecisionWait:     time.Hour,
+		MaxTraces:        2,
+		MaxSpansPerTrace: 2,
+		FallbackRatio:    1,
+	})
+
+	pctx, parent := tr.Start(ctx, "first")
+	for i := 0; i < 3; i++ {
+		_, child := tr.Start(pctx, "first.child")
+		child.End()
+	}
+	parent.End()
+	for _, name := range []string{"second", "third"} {
+		_, span := tr.Start(ctx, name)
+		span.End()
+	}
+
+	// The third trace evicted the oldest one, which was decided early.
+	if got := len(recorder.Ended()); got != 2 {
+		t.Fatalf("Expected the evicted trace's 2 buffered spans to be forwarded, got %d", got)
+	}
+	stats := tsp.Stats()
+	if stats.EvictedTraces != 1 || stats.BufferedTraces != 2 || stats.SpansDropped != 2 {
+		t.Errorf("Unexpected stats: %+v", stats)
+	}
+}
+
+func TestNewTailSamplingProcessor_InvalidConfig(t *testing.T) {
+	tests := []struct {
+		name  string
+		cfg   TailSamplingConfig
+		field string
+	}{
+		{"negative wait", TailSamplingConfig{DecisionWait: -time.Second}, "TailSampling"},
+		{"negative limit", TailSamplingConfig{MaxTraces: -1}, "TailSampling"},
+		{"ratio above one", TailSamplingConfig{FallbackRatio: 1.5}, "TailSampling.FallbackRatio"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewTailSamplingProcessor(tt.cfg)
+			var cfgErr *config.ConfigError
+			if !errors.As(err, &cfgErr) || cfgErr.Field != tt.field {
+				t.Errorf("Expected ConfigError for %s, got %v", tt.field, err)
+			}
+		})
+	}
+}
+
+func TestNew_WithTailSampling(t *testing.T) {
+	ctx := context.Background()
+	var buf bytes.Buffer
+	pc := NewProviderConfig("tail-service", "1.0.0").
+		WithSampling(config.SamplingProbabilistic, 0.1).
+		WithConsoleExporter(&buf, true).
+		WithTailSampling(TailSamplingConfig{
+			DecisionWait: time.Hour,
+			Policies:     []TailSamplingPolicy{KeepErrorTraces()},
+		})
+	if pc.Config.SamplingType != config.SamplingAlwaysOn {
+		t.Errorf("Expected head sampling to switch to always_on, got %s", pc.Config.SamplingType)
+	}
+
+	p, err := New(ctx, pc.WithoutGlobalRegistration())
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	tsp := p.TailSampler()
+	if tsp == nil {
+		t.Fatal("Expected TailSampler to return the created processor")
+	}
+
+	// A second provider from the same config gets its own processor.
+	other, err := New(ctx, pc)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	if other.TailSampler() == tsp {
+		t.Error("Expected each provider to have its own tail sampler")
+	}
+	_ = other.Shutdown(ctx)
+
+	for i := 0; i < 5; i++ {
+		_, span := p.Tracer("test").Start(ctx, "op")
+		if i == 0 {
+			span.SetStatus(codes.Error, "boom")
+		}
+		span.End()
+	}
+	if err := p.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown failed: %v", err)
+	}
+	if stats := tsp.Stats(); stats.TracesKept != 1 || stats.TracesDropped != 4 {
+		t.Errorf("Expected 1 kept and 4 dropped traces, got %+v", stats)
+	}
+	if got := bytes.Count(buf.Bytes(), []byte("\n")); got != 1 {
+		t.Errorf("Expected 1 exported span, got %d", got)
+	}
+}
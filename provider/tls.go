@@ -0,0 +1,208 @@
+package provider
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel"
+
+	"github.com/kernelshard/otelkit/internal/config"
+)
+
+// certReloader holds the CA bundle and client key pair used for exporter TLS and
+// re-reads them from disk when the files change, so rotated certificates are picked
+// up without restarting the process. Files are checked at most once per interval,
+// lazily during TLS handshakes; no background goroutine is needed.
+type certReloader struct {
+	caFile   string
+	certFile string
+	keyFile  string
+	interval time.Duration
+
+	mu        sync.Mutex
+	lastCheck time.Time
+	modTimes  [3]time.Time
+	roots     *x509.CertPool
+	cert      *tls.Certificate
+
+	now func() time.Time
+}
+
+// newCertReloader loads the configured files once and fails if any of them cannot
+// be read or parsed. A non-positive interval disables reloading.
+func newCertReloader(caFile, certFile, keyFile string, interval time.Duration) (*certReloader, error) {
+	r := &certReloader{
+		caFile:   caFile,
+		certFile: certFile,
+		keyFile:  keyFile,
+		interval: interval,
+		now:      time.Now,
+	}
+	if err := r.load(); err != nil {
+		return nil, err
+	}
+	r.lastCheck = r.now()
+	return r, nil
+}
+
+// load reads the CA bundle and key pair and records the file modification times.
+func (r *certReloader) load() error {
+	modTimes, err := r.stat()
+	if err != nil {
+		return err
+	}
+
+	var roots *x509.CertPool
+	if r.caFile != "" {
+		pem, err := os.ReadFile(r.caFile)
+		if err != nil {
+			return err
+		}
+		roots = x509.NewCertPool()
+		if !roots.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("no certificates found in %s", r.caFile)
+		}
+	}
+
+	var cert *tls.Certificate
+	if r.certFile != "" {
+		pair, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+		if err != nil {
+			return err
+		}
+		cert = &pair
+	}
+
+	r.roots = roots
+	r.cert = cert
+	r.modTimes = modTimes
+	return nil
+}
+
+func (r *certReloader) stat() ([3]time.Time, error) {
+	var modTimes [3]time.Time
+	for i, name := range []string{r.caFile, r.certFile, r.keyFile} {
+		if name == "" {
+			continue
+		}
+		info, err := os.Stat(name)
+		if err != nil {
+			return modTimes, err
+		}
+		modTimes[i] = info.ModTime()
+	}
+	return modTimes, nil
+}
+
+// maybeReload re-reads the files if the check interval has elapsed and any file
+// changed. On failure the previously loaded material is kept and the error is
+// reported through the OpenTelemetry error handler.
+func (r *certReloader) maybeReload() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.interval <= 0 || r.now().Sub(r.lastCheck) < r.interval {
+		return
+	}
+	r.lastCheck = r.now()
+
+	modTimes, err := r.stat()
+	if err != nil {
+		otel.Handle(fmt.Errorf("otelkit: tls reload: %w", err))
+		return
+	}
+	if modTimes == r.modTimes {
+		return
+	}
+	if err := r.load(); err != nil {
+		otel.Handle(fmt.Errorf("otelkit: tls reload: %w", err))
+	}
+}
+
+// clientCertificate returns the current client key pair.
+func (r *certReloader) clientCertificate(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	r.maybeReload()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.cert == nil {
+		return &tls.Certificate{}, nil
+	}
+	return r.cert, nil
+}
+
+// verifyConnection verifies the server certificate chain against the current CA
+// bundle. It replaces the standard verification so a rotated bundle takes effect
+// for new connections without rebuilding the transport.
+func (r *certReloader) verifyConnection(serverName string) func(tls.ConnectionState) error {
+	return func(cs tls.ConnectionState) error {
+		r.maybeReload()
+		r.mu.Lock()
+		roots := r.roots
+		r.mu.Unlock()
+
+		if len(cs.PeerCertificates) == 0 {
+			return errors.New("tls: server presented no certificates")
+		}
+		name := serverName
+		if name == "" {
+			name = cs.ServerName
+		}
+		opts := x509.VerifyOptions{
+			Roots:         roots,
+			DNSName:       name,
+			Intermediates: x509.NewCertPool(),
+		}
+		for _, cert := range cs.PeerCertificates[1:] {
+			opts.Intermediates.AddCert(cert)
+		}
+		_, err := cs.PeerCertificates[0].Verify(opts)
+		return err
+	}
+}
+
+// tlsConfig builds a client TLS configuration backed by the reloader.
+func (r *certReloader) tlsConfig(serverName string) *tls.Config {
+	cfg := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: serverName,
+	}
+	if r.certFile != "" {
+		cfg.GetClientCertificate = r.clientCertificate
+	}
+	if r.caFile != "" {
+		// Standard verification is disabled only because it would pin the CA pool
+		// loaded at startup; verifyConnection performs the full chain and hostname
+		// verification against the current pool instead.
+		cfg.InsecureSkipVerify = true
+		cfg.VerifyConnection = r.verifyConnection(serverName)
+	}
+	return cfg
+}
+
+// createTLSConfig returns the exporter TLS configuration, or nil when no custom
+// CA bundle or client certificate is configured and the system defaults apply.
+func createTLSConfig(cfg *config.Config, endpoint config.Endpoint) (*tls.Config, error) {
+	if endpoint.Insecure(cfg.OTLPExporterInsecure) || !cfg.HasCustomTLS() {
+		return nil, nil
+	}
+	reloader, err := newCertReloader(cfg.TLSCAFile, cfg.TLSClientCertFile, cfg.TLSClientKeyFile, cfg.TLSReloadInterval)
+	if err != nil {
+		return nil, &InitializationError{Component: "tls", Cause: err}
+	}
+	return reloader.tlsConfig(endpointHost(endpoint)), nil
+}
+
+// endpointHost returns the host name of an endpoint, without the port.
+func endpointHost(endpoint config.Endpoint) string {
+	if host, _, err := net.SplitHostPort(endpoint.Host); err == nil {
+		return host
+	}
+	return strings.Trim(endpoint.Host, "[]")
+}
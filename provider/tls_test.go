@@ -0,0 +1,274 @@
+package provider
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/kernelshard/otelkit/internal/config"
+)
+
+// testCA is a throwaway certificate authority for TLS tests.
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  []byte
+}
+
+func newTestCA(t *testing.T, name string) *testCA {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey failed: %v", err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("CreateCertificate failed: %v", err)
+	}
+	cert, _ := x509.ParseCertificate(der)
+	return &testCA{cert: cert, key: key, pem: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})}
+}
+
+// issue creates a leaf certificate signed by the CA and returns PEM cert and key.
+func (ca *testCA) issue(t *testing.T, cn string, serial int64, usage x509.ExtKeyUsage) ([]byte, []byte) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey failed: %v", err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: cn},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+		DNSNames:     []string{"localhost"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca.cert, &key.PublicKey, ca.key)
+	if err != nil {
+		t.Fatalf("CreateCertificate failed: %v", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("MarshalECPrivateKey failed: %v", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+func writeFile(t *testing.T, path string, data []byte) {
+	t.Helper()
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+}
+
+func TestHTTPExporter_MutualTLS(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	ca := newTestCA(t, "otelkit-test-ca")
+
+	serverCertPEM, serverKeyPEM := ca.issue(t, "collector", 2, x509.ExtKeyUsageServerAuth)
+	serverCert, err := tls.X509KeyPair(serverCertPEM, serverKeyPEM)
+	if err != nil {
+		t.Fatalf("X509KeyPair failed: %v", err)
+	}
+	clientPool := x509.NewCertPool()
+	clientPool.AddCert(ca.cert)
+
+	var authenticated atomic.Bool
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.TLS != nil && len(r.TLS.PeerCertificates) > 0 && r.TLS.PeerCertificates[0].Subject.CommonName == "exporter" {
+			authenticated.Store(true)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	srv.TLS = &tls.Config{
+		Certificates: []tls.Certificate{serverCert},
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		ClientCAs:    clientPool,
+	}
+	srv.StartTLS()
+	defer srv.Close()
+
+	clientCertPEM, clientKeyPEM := ca.issue(t, "exporter", 3, x509.ExtKeyUsageClientAuth)
+	caFile := filepath.Join(dir, "ca.pem")
+	certFile := filepath.Join(dir, "client.pem")
+	keyFile := filepath.Join(dir, "client-key.pem")
+	writeFile(t, caFile, ca.pem)
+	writeFile(t, certFile, clientCertPEM)
+	writeFile(t, keyFile, clientKeyPEM)
+
+	pc := NewProviderConfig("mtls-service", "1.0.0").
+		WithOTLPExporter(strings.TrimPrefix(srv.URL, "https://"), config.ProtocolHTTP, false).
+		WithSampling(config.SamplingAlwaysOn, 1.0).
+		WithTLS(caFile, certFile, keyFile)
+
+	exporter, err := createExporter(ctx, pc, nil)
+	if err != nil {
+		t.Fatalf("createExporter failed: %v", err)
+	}
+	defer exporter.Shutdown(ctx)
+
+	if err := exporter.ExportSpans(ctx, testSpans(t)); err != nil {
+		t.Fatalf("ExportSpans over mTLS failed: %v", err)
+	}
+	if !authenticated.Load() {
+		t.Error("Expected the collector to see the client certificate")
+	}
+}
+
+func TestCertReloader_PicksUpRotatedCertificate(t *testing.T) {
+	dir := t.TempDir()
+	ca := newTestCA(t, "otelkit-test-ca")
+	certFile := filepath.Join(dir, "client.pem")
+	keyFile := filepath.Join(dir, "client-key.pem")
+
+	certPEM, keyPEM := ca.issue(t, "first", 10, x509.ExtKeyUsageClientAuth)
+	writeFile(t, certFile, certPEM)
+	writeFile(t, keyFile, keyPEM)
+
+	r, err := newCertReloader("", certFile, keyFile, time.Minute)
+	if err != nil {
+		t.Fatalf("newCertReloader failed: %v", err)
+	}
+	now := time.Now()
+	r.now = func() time.Time { return now }
+	r.lastCheck = now
+
+	certPEM, keyPEM = ca.issue(t, "second", 11, x509.ExtKeyUsageClientAuth)
+	writeFile(t, certFile, certPEM)
+	writeFile(t, keyFile, keyPEM)
+	later := time.Now().Add(time.Second)
+	_ = os.Chtimes(certFile, later, later)
+	_ = os.Chtimes(keyFile, later, later)
+
+	commonName := func() string {
+		cert, err := r.clientCertificate(nil)
+		if err != nil {
+			t.Fatalf("clientCertificate failed: %v", err)
+		}
+		leaf, err := x509.ParseCertificate(cert.Certificate[0])
+		if err != nil {
+			t.Fatalf("ParseCertificate failed: %v", err)
+		}
+		return leaf.Subject.CommonName
+	}
+
+	if got := commonName(); got != "first" {
+		t.Errorf("Expected cached certificate before the interval elapsed, got %s", got)
+	}
+	now = now.Add(2 * time.Minute)
+	if got := commonName(); got != "second" {
+		t.Errorf("Expected rotated certificate after the interval, got %s", got)
+	}
+}
+
+func TestWithTLS_ReloadsByDefault(t *testing.T) {
+	dir := t.TempDir()
+	ca := newTestCA(t, "otelkit-test-ca")
+	certFile := filepath.Join(dir, "client.pem")
+	keyFile := filepath.Join(dir, "client-key.pem")
+	certPEM, keyPEM := ca.issue(t, "first", 30, x509.ExtKeyUsageClientAuth)
+	writeFile(t, certFile, certPEM)
+	writeFile(t, keyFile, keyPEM)
+
+	pc := NewProviderConfig("tls-service", "1.0.0").WithTLS("", certFile, keyFile)
+	if pc.Config.TLSReloadInterval != config.DefaultTLSReloadInterval {
+		t.Fatalf("Expected the default reload interval, got %v", pc.Config.TLSReloadInterval)
+	}
+
+	r, err := newCertReloader(pc.Config.TLSCAFile, pc.Config.TLSClientCertFile, pc.Config.TLSClientKeyFile, pc.Config.TLSReloadInterval)
+	if err != nil {
+		t.Fatalf("newCertReloader failed: %v", err)
+	}
+	now := time.Now()
+	r.now = func() time.Time { return now }
+
+	certPEM, keyPEM = ca.issue(t, "second", 31, x509.ExtKeyUsageClientAuth)
+	writeFile(t, certFile, certPEM)
+	writeFile(t, keyFile, keyPEM)
+	later := time.Now().Add(time.Second)
+	_ = os.Chtimes(certFile, later, later)
+	_ = os.Chtimes(keyFile, later, later)
+
+	now = now.Add(config.DefaultTLSReloadInterval + time.Second)
+	cert, err := r.clientCertificate(nil)
+	if err != nil {
+		t.Fatalf("clientCertificate failed: %v", err)
+	}
+	if leaf, _ := x509.ParseCertificate(cert.Certificate[0]); leaf == nil || leaf.Subject.CommonName != "second" {
+		t.Error("Expected the rotated certificate to be picked up without setting a reload interval")
+	}
+}
+
+func TestCertReloader_RejectsUnknownCA(t *testing.T) {
+	dir := t.TempDir()
+	trusted := newTestCA(t, "trusted")
+	other := newTestCA(t, "other")
+
+	caFile := filepath.Join(dir, "ca.pem")
+	writeFile(t, caFile, trusted.pem)
+	r, err := newCertReloader(caFile, "", "", 0)
+	if err != nil {
+		t.Fatalf("newCertReloader failed: %v", err)
+	}
+
+	certPEM, _ := other.issue(t, "collector", 20, x509.ExtKeyUsageServerAuth)
+	block, _ := pem.Decode(certPEM)
+	leaf, _ := x509.ParseCertificate(block.Bytes)
+
+	verify := r.verifyConnection("localhost")
+	if err := verify(tls.ConnectionState{PeerCertificates: []*x509.Certificate{leaf}}); err == nil {
+		t.Error("Expected verification to fail for a certificate from an untrusted CA")
+	}
+}
+
+func TestCreateTLSConfig(t *testing.T) {
+	cfg := config.NewConfig("test", "1.0.0")
+	endpoint := config.Endpoint{Host: cfg.OTLPExporterEndpoint}
+	if tlsCfg, err := createTLSConfig(cfg, endpoint); err != nil || tlsCfg != nil {
+		t.Errorf("Expected no custom TLS config by default, got %v, %v", tlsCfg, err)
+	}
+
+	cfg.TLSCAFile = filepath.Join(t.TempDir(), "missing.pem")
+	if _, err := createTLSConfig(cfg, endpoint); err == nil {
+		t.Error("Expected error for missing CA file")
+	}
+
+	cfg.OTLPExporterInsecure = true
+	if tlsCfg, err := createTLSConfig(cfg, endpoint); err != nil || tlsCfg != nil {
+		t.Error("Expected TLS settings to be ignored for insecure exporters")
+	}
+
+	cfg.OTLPExporterInsecure = false
+	if tlsCfg, err := createTLSConfig(cfg, config.Endpoint{Scheme: "http", Host: "collector:4318"}); err != nil || tlsCfg != nil {
+		t.Error("Expected TLS settings to be ignored for http:// endpoints")
+	}
+}
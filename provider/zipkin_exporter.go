@@ -0,0 +1,271 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/kernelshard/otelkit/internal/config"
+)
+
+// zipkinExporter posts spans to a Zipkin v2 collector as JSON. The mapping follows
+// the OpenTelemetry specification for Zipkin exporters: the service name becomes the
+// local endpoint, span and resource attributes become tags (so otelkit's service
+// instance ID and environment are preserved), events become annotations and span
+// kinds map to CLIENT, SERVER, PRODUCER and CONSUMER; internal spans have no kind.
+type zipkinExporter struct {
+	url     string
+	client  *http.Client
+	headers map[string]string
+	// ownsClient reports whether client was created for this exporter, in which case
+	// Shutdown closes its idle connections.
+	ownsClient bool
+
+	mu      sync.Mutex
+	stopped bool
+}
+
+// newZipkinExporter creates an exporter posting to url, which must be the collector's
+// spans endpoint, e.g. http://localhost:9411/api/v2/spans. A nil client is replaced
+// by one the exporter owns; a client passed in is left open on Shutdown.
+func newZipkinExporter(url string, client *http.Client, headers map[string]string) *zipkinExporter {
+	e := &zipkinExporter{url: url, client: client, headers: headers}
+	if client == nil {
+		e.client = &http.Client{Transport: http.DefaultTransport.(*http.Transport).Clone()}
+		e.ownsClient = true
+	}
+	return e
+}
+
+// zipkinSpan is the Zipkin v2 JSON span model.
+type zipkinSpan struct {
+	TraceID        string             `json:"traceId"`
+	ID             string             `json:"id"`
+	ParentID       string             `json:"parentId,omitempty"`
+	Name           string             `json:"name,omitempty"`
+	Kind           string             `json:"kind,omitempty"`
+	Timestamp      int64              `json:"timestamp,omitempty"` // Microseconds since the epoch
+	Duration       int64              `json:"duration,omitempty"`  // Microseconds
+	LocalEndpoint  *zipkinEndpoint    `json:"localEndpoint,omitempty"`
+	RemoteEndpoint *zipkinEndpoint    `json:"remoteEndpoint,omitempty"`
+	Annotations    []zipkinAnnotation `json:"annotations,omitempty"`
+	Tags           map[string]string  `json:"tags,omitempty"`
+}
+
+type zipkinEndpoint struct {
+	ServiceName string `json:"serviceName,omitempty"`
+	IPv4        string `json:"ipv4,omitempty"`
+	IPv6        string `json:"ipv6,omitempty"`
+}
+
+type zipkinAnnotation struct {
+	Timestamp int64  `json:"timestamp"`
+	Value     string `json:"value"`
+}
+
+// ExportSpans converts the batch to Zipkin JSON and posts it in a single request.
+func (e *zipkinExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	e.mu.Lock()
+	stopped := e.stopped
+	e.mu.Unlock()
+	if stopped || len(spans) == 0 {
+		return nil
+	}
+
+	models := make([]zipkinSpan, 0, len(spans))
+	for _, s := range spans {
+		models = append(models, toZipkinSpan(s))
+	}
+	body, err := json.Marshal(models)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range e.headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("zipkin collector returned %s", resp.Status)
+	}
+	return nil
+}
+
+// Shutdown stops the exporter; later exports are dropped.
+func (e *zipkinExporter) Shutdown(ctx context.Context) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.stopped = true
+	if e.ownsClient {
+		e.client.CloseIdleConnections()
+	}
+	return nil
+}
+
+func toZipkinSpan(s sdktrace.ReadOnlySpan) zipkinSpan {
+	sc := s.SpanContext()
+	out := zipkinSpan{
+		TraceID:   sc.TraceID().String(),
+		ID:        sc.SpanID().String(),
+		Name:      s.Name(),
+		Kind:      zipkinKind(s.SpanKind()),
+		Timestamp: s.StartTime().UnixMicro(),
+		Duration:  s.EndTime().Sub(s.StartTime()).Microseconds(),
+		LocalEndpoint: &zipkinEndpoint{
+			ServiceName: zipkinServiceName(s),
+		},
+		Tags: zipkinTags(s),
+	}
+	if parent := s.Parent(); parent.IsValid() {
+		out.ParentID = parent.SpanID().String()
+	}
+	if kind := s.SpanKind(); kind == trace.SpanKindClient || kind == trace.SpanKindProducer {
+		out.RemoteEndpoint = zipkinRemoteEndpoint(s.Attributes())
+	}
+	for _, ev := range s.Events() {
+		out.Annotations = append(out.Annotations, zipkinAnnotation{
+			Timestamp: ev.Time.UnixMicro(),
+			Value:     zipkinAnnotationValue(ev),
+		})
+	}
+	return out
+}
+
+func zipkinKind(kind trace.SpanKind) string {
+	switch kind {
+	case trace.SpanKindServer:
+		return "SERVER"
+	case trace.SpanKindClient:
+		return "CLIENT"
+	case trace.SpanKindProducer:
+		return "PRODUCER"
+	case trace.SpanKindConsumer:
+		return "CONSUMER"
+	default:
+		return ""
+	}
+}
+
+func zipkinServiceName(s sdktrace.ReadOnlySpan) string {
+	if res := s.Resource(); res != nil {
+		if v, ok := res.Set().Value(semconv.ServiceNameKey); ok && v.AsString() != "" {
+			return v.AsString()
+		}
+	}
+	return config.DefaultServiceName
+}
+
+// zipkinTags merges resource and span attributes, span attributes taking precedence,
+// and adds the status and instrumentation scope tags defined by the specification.
+func zipkinTags(s sdktrace.ReadOnlySpan) map[string]string {
+	tags := make(map[string]string)
+	if res := s.Resource(); res != nil {
+		for _, kv := range res.Attributes() {
+			tags[string(kv.Key)] = zipkinTagValue(kv.Value)
+		}
+	}
+	for _, kv := range s.Attributes() {
+		tags[string(kv.Key)] = zipkinTagValue(kv.Value)
+	}
+
+	switch status := s.Status(); status.Code {
+	case codes.Ok:
+		tags["otel.status_code"] = "OK"
+	case codes.Error:
+		tags["otel.status_code"] = "ERROR"
+		tags["error"] = status.Description
+	}
+	if scope := s.InstrumentationScope(); scope.Name != "" {
+		tags["otel.scope.name"] = scope.Name
+		if scope.Version != "" {
+			tags["otel.scope.version"] = scope.Version
+		}
+	}
+	if len(tags) == 0 {
+		return nil
+	}
+	return tags
+}
+
+// zipkinTagValue renders an attribute value as a tag string. Zipkin tags are plain
+// strings, so slices are encoded as JSON arrays.
+func zipkinTagValue(v attribute.Value) string {
+	switch v.Type() {
+	case attribute.BOOLSLICE, attribute.INT64SLICE, attribute.FLOAT64SLICE, attribute.STRINGSLICE:
+		data, err := json.Marshal(v.AsInterface())
+		if err != nil {
+			return v.Emit()
+		}
+		return string(data)
+	default:
+		return v.Emit()
+	}
+}
+
+// zipkinAnnotationValue renders an event as an annotation: the event name, followed
+// by its attributes as a JSON object if it has any.
+func zipkinAnnotationValue(ev sdktrace.Event) string {
+	if len(ev.Attributes) == 0 {
+		return ev.Name
+	}
+	data, err := json.Marshal(attributeMap(ev.Attributes))
+	if err != nil {
+		return ev.Name
+	}
+	return ev.Name + ": " + string(data)
+}
+
+// zipkinRemoteEndpoint derives the remote endpoint of client and producer spans from
+// the peer attributes, preferring the logical service name over the host name.
+func zipkinRemoteEndpoint(attrs []attribute.KeyValue) *zipkinEndpoint {
+	values := make(map[attribute.Key]string, len(attrs))
+	for _, kv := range attrs {
+		values[kv.Key] = kv.Value.Emit()
+	}
+
+	endpoint := &zipkinEndpoint{}
+	for _, key := range []attribute.Key{semconv.PeerServiceKey, semconv.ServerAddressKey, semconv.NetPeerNameKey} {
+		if v := values[key]; v != "" {
+			endpoint.ServiceName = v
+			break
+		}
+	}
+	for _, key := range []attribute.Key{"network.peer.address", semconv.NetSockPeerAddrKey} {
+		if ip := net.ParseIP(strings.Trim(values[key], "[]")); ip != nil {
+			if ip.To4() != nil {
+				endpoint.IPv4 = ip.String()
+			} else {
+				endpoint.IPv6 = ip.String()
+			}
+			break
+		}
+	}
+	if *endpoint == (zipkinEndpoint{}) {
+		return nil
+	}
+	return endpoint
+}
@@ -0,0 +1,177 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/kernelshard/otelkit/internal/config"
+)
+
+// newZipkinTestServer starts a Zipkin stand-in that records posted spans and headers.
+func newZipkinTestServer(t *testing.T) (*httptest.Server, func() ([]zipkinSpan, http.Header)) {
+	t.Helper()
+	var mu sync.Mutex
+	var spans []zipkinSpan
+	var header http.Header
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v2/spans" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		var batch []zipkinSpan
+		if err := json.NewDecoder(r.Body).Decode(&batch); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		mu.Lock()
+		spans = append(spans, batch...)
+		header = r.Header.Clone()
+		mu.Unlock()
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv, func() ([]zipkinSpan, http.Header) {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]zipkinSpan(nil), spans...), header
+	}
+}
+
+func TestZipkinExporter_MapsResourceAndKinds(t *testing.T) {
+	ctx := context.Background()
+	srv, received := newZipkinTestServer(t)
+
+	pc := NewProviderConfig("zipkin-service", "1.0.0").
+		WithZipkinExporter(srv.URL+"/api/v2/spans").
+		WithSampling(config.SamplingAlwaysOn, 1.0).
+		WithHeaders(map[string]string{"x-api-key": "secret"})
+
+	tp, err := newProvider(ctx, pc)
+	if err != nil {
+		t.Fatalf("newProvider failed: %v", err)
+	}
+	tr := tp.Tracer("zipkin-test")
+	for _, kind := range []trace.SpanKind{trace.SpanKindServer, trace.SpanKindClient, trace.SpanKindInternal} {
+		_, span := tr.Start(ctx, kind.String(), trace.WithSpanKind(kind))
+		span.End()
+	}
+	if err := ShutdownTracerProvider(ctx, tp); err != nil {
+		t.Fatalf("Shutdown failed: %v", err)
+	}
+
+	spans, header := received()
+	if len(spans) != 3 {
+		t.Fatalf("Expected 3 spans, got %d", len(spans))
+	}
+	if header.Get("x-api-key") != "secret" {
+		t.Errorf("Expected x-api-key header, got %q", header.Get("x-api-key"))
+	}
+
+	wantKinds := map[string]string{"server": "SERVER", "client": "CLIENT", "internal": ""}
+	for _, s := range spans {
+		if s.LocalEndpoint == nil || s.LocalEndpoint.ServiceName != "zipkin-service" {
+			t.Errorf("%s: expected local endpoint service zipkin-service, got %+v", s.Name, s.LocalEndpoint)
+		}
+		if s.Tags["service.instance.id"] != pc.Config.InstanceID {
+			t.Errorf("%s: expected service.instance.id tag %q, got %q", s.Name, pc.Config.InstanceID, s.Tags["service.instance.id"])
+		}
+		if s.Kind != wantKinds[s.Name] {
+			t.Errorf("%s: expected kind %q, got %q", s.Name, wantKinds[s.Name], s.Kind)
+		}
+	}
+}
+
+func TestToZipkinSpan_StatusEventsAndRemoteEndpoint(t *testing.T) {
+	ctx := context.Background()
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	_, span := tp.Tracer("zipkin-test", trace.WithInstrumentationVersion("1.2.3")).Start(ctx, "GET /users",
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("peer.service", "users-api"),
+			attribute.String("network.peer.address", "10.0.0.7"),
+			attribute.StringSlice("tags", []string{"a", "b"}),
+		))
+	span.AddEvent("retry", trace.WithAttributes(attribute.Int("attempt", 2)))
+	span.SetStatus(codes.Error, "timeout")
+	span.End()
+
+	got := toZipkinSpan(recorder.Ended()[0])
+	if got.RemoteEndpoint == nil || got.RemoteEndpoint.ServiceName != "users-api" || got.RemoteEndpoint.IPv4 != "10.0.0.7" {
+		t.Errorf("Unexpected remote endpoint %+v", got.RemoteEndpoint)
+	}
+	if got.Tags["otel.status_code"] != "ERROR" || got.Tags["error"] != "timeout" {
+		t.Errorf("Unexpected status tags %v", got.Tags)
+	}
+	if got.Tags["tags"] != `["a","b"]` {
+		t.Errorf("Expected slice tag encoded as JSON, got %q", got.Tags["tags"])
+	}
+	if got.Tags["otel.scope.name"] != "zipkin-test" || got.Tags["otel.scope.version"] != "1.2.3" {
+		t.Errorf("Unexpected scope tags %v", got.Tags)
+	}
+	if len(got.Annotations) != 1 || got.Annotations[0].Value != `retry: {"attempt":2}` {
+		t.Errorf("Unexpected annotations %+v", got.Annotations)
+	}
+	if len(got.TraceID) != 32 || len(got.ID) != 16 {
+		t.Errorf("Unexpected IDs %s/%s", got.TraceID, got.ID)
+	}
+}
+
+func TestZipkinExporter_ErrorStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	e := newZipkinExporter(srv.URL, nil, nil)
+	if err := e.ExportSpans(context.Background(), testSpans(t)); err == nil {
+		t.Error("Expected error for a failed request")
+	}
+}
+
+// closeTrackingTransport records whether CloseIdleConnections was called.
+type closeTrackingTransport struct {
+	http.RoundTripper
+	closed bool
+}
+
+func (t *closeTrackingTransport) CloseIdleConnections() { t.closed = true }
+
+func TestZipkinExporter_ShutdownLeavesCallerClientOpen(t *testing.T) {
+	ctx := context.Background()
+	shared := &closeTrackingTransport{RoundTripper: http.DefaultTransport}
+	e := newZipkinExporter("http://localhost:9411/api/v2/spans", &http.Client{Transport: shared}, nil)
+	if err := e.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown failed: %v", err)
+	}
+	if shared.closed {
+		t.Error("Expected a caller-supplied client to keep its idle connections")
+	}
+
+	owned := &closeTrackingTransport{RoundTripper: http.DefaultTransport}
+	e = newZipkinExporter("http://localhost:9411/api/v2/spans", &http.Client{Transport: owned}, nil)
+	e.ownsClient = true
+	if err := e.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown failed: %v", err)
+	}
+	if !owned.closed {
+		t.Error("Expected the exporter's own client to close its idle connections")
+	}
+
+	if e := newZipkinExporter("http://localhost:9411/api/v2/spans", nil, nil); !e.ownsClient || e.client == http.DefaultClient {
+		t.Error("Expected a nil client to be replaced by one the exporter owns")
+	}
+}
@@ -11,6 +11,8 @@ type traceContextKey string
 const traceIDKey traceContextKey = "trace_id"
 
 // InjectTraceIDIntoContext adds trace ID into the context (as a new value).
+// To correlate log/slog records with traces, NewSlogHandler reads the span from the
+// context directly and needs no injection.
 func InjectTraceIDIntoContext(ctx context.Context, span trace.Span) context.Context {
 	if span == nil {
 		return ctx
@@ -3,6 +3,7 @@ package tracer
 import (
 	"context"
 
+	"go.opentelemetry.io/otel"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 
 	"github.com/kernelshard/otelkit/internal/config"
@@ -10,8 +11,12 @@ import (
 )
 
 // createTracingConfig creates a tracing configuration from environment variables and parameters.
+// Environment settings that were ignored are reported through the OpenTelemetry error handler.
 func createTracingConfig(serviceName string, serviceVersion string) (*config.Config, error) {
-	cfg := config.NewConfigFromEnv()
+	cfg, warnings := config.NewConfigFromEnv()
+	for _, w := range warnings {
+		otel.Handle(w)
+	}
 	cfg.ServiceName = serviceName
 	cfg.ServiceVersion = serviceVersion
 
@@ -21,25 +26,28 @@ func createTracingConfig(serviceName string, serviceVersion string) (*config.Con
 	return cfg, nil
 }
 
-// createTracingProvider creates a tracer provider from the given configuration.
-func createTracingProvider(ctx context.Context, cfg *config.Config) (*provider.ProviderConfig, *sdktrace.TracerProvider, error) {
-	providerCfg := &provider.ProviderConfig{
+// newProviderConfig wraps cfg in a provider configuration with the default batch settings.
+func newProviderConfig(cfg *config.Config) *provider.ProviderConfig {
+	return &provider.ProviderConfig{
 		Config:             cfg,
 		BatchTimeout:       config.DefaultBatchTimeout,
 		ExportTimeout:      config.DefaultExportTimeout,
 		MaxExportBatchSize: config.DefaultMaxExportBatchSize,
 		MaxQueueSize:       config.DefaultMaxQueueSize,
 	}
+}
 
-	tp, err := provider.NewProvider(ctx, providerCfg)
-	if err != nil {
-		return nil, nil, err
-	}
-	return providerCfg, tp, nil
+// createTracingProvider creates a provider from the given configuration, including
+// the meter and logger providers if OTLP metrics or logs are enabled.
+func createTracingProvider(ctx context.Context, cfg *config.Config) (*provider.Provider, error) {
+	return provider.New(ctx, newProviderConfig(cfg))
 }
 
 // SetupTracing initializes OpenTelemetry tracing with sensible defaults.
-// This is the simplest way to get started with tracing.
+// This is the simplest way to get started with tracing. If OTEL_METRICS_EXPORTER or
+// OTEL_LOGS_EXPORTER is "otlp", metrics or logs are set up as well, and the returned
+// shutdown function flushes and stops them together with tracing. With
+// OTEL_EXPORTER_STARTUP_PROBE=fail it returns an error if the collector is unreachable.
 //
 // Example:
 //
@@ -59,13 +67,44 @@ func SetupTracing(ctx context.Context, serviceName string, serviceVersion ...str
 		return nil, err
 	}
 
-	_, tp, err := createTracingProvider(ctx, cfg)
+	p, err := createTracingProvider(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.Shutdown, nil
+}
+
+// SetupMetrics initializes an OTLP metrics pipeline configured like SetupTracing,
+// from the same environment variables, without setting up tracing. The meter provider
+// is installed as the global meter provider, so otel.Meter can be used.
+//
+// Example:
+//
+//	shutdown, err := tracer.SetupMetrics(ctx, "my-service")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	defer shutdown(ctx)
+func SetupMetrics(ctx context.Context, serviceName string, serviceVersion ...string) (func(context.Context) error, error) {
+	version := "1.0.0"
+	if len(serviceVersion) > 0 {
+		version = serviceVersion[0]
+	}
+
+	cfg, err := createTracingConfig(serviceName, version)
+	if err != nil {
+		return nil, err
+	}
+	cfg.MetricsExporter = config.MetricsExporterOTLP
+
+	mp, err := provider.NewMeterProvider(ctx, newProviderConfig(cfg))
 	if err != nil {
 		return nil, err
 	}
 
 	shutdown := func(ctx context.Context) error {
-		return provider.ShutdownTracerProvider(ctx, tp)
+		return provider.ShutdownMeterProvider(ctx, mp)
 	}
 
 	return shutdown, nil
@@ -2,10 +2,15 @@ package tracer
 
 import (
 	"context"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"sync"
 	"testing"
 
 	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/log/global"
 
 	"github.com/kernelshard/otelkit/internal/config"
 	"github.com/kernelshard/otelkit/provider"
@@ -108,6 +113,22 @@ func TestSetupTracing_InvalidConfig(t *testing.T) {
 	}
 }
 
+func TestCreateTracingConfig_ReportsEnvWarnings(t *testing.T) {
+	t.Setenv(config.EnvSamplingType, "xray")
+
+	var handled []error
+	prev := otel.GetErrorHandler()
+	otel.SetErrorHandler(otel.ErrorHandlerFunc(func(err error) { handled = append(handled, err) }))
+	t.Cleanup(func() { otel.SetErrorHandler(prev) })
+
+	if _, err := createTracingConfig("warn-service", "1.0.0"); err != nil {
+		t.Fatalf("createTracingConfig failed: %v", err)
+	}
+	if len(handled) != 1 {
+		t.Errorf("Expected the unsupported sampler to be reported, got %v", handled)
+	}
+}
+
 func TestSetupTracingWithDefaults(t *testing.T) {
 	ctx := context.Background()
 	serviceName := "test-defaults-service"
@@ -375,3 +396,68 @@ func TestSetupFunctions_Integration(t *testing.T) {
 		})
 	}
 }
+
+func TestSetupTracing_WithMetricsAndLogs(t *testing.T) {
+	ctx := context.Background()
+	t.Cleanup(provider.ResetGlobal)
+
+	var mu sync.Mutex
+	received := map[string]bool{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		received[r.URL.Path] = true
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", srv.URL)
+	t.Setenv("OTEL_EXPORTER_OTLP_PROTOCOL", "http")
+	t.Setenv("OTEL_METRICS_EXPORTER", "otlp")
+	t.Setenv("OTEL_LOGS_EXPORTER", "otlp")
+
+	shutdown, err := SetupTracing(ctx, "metrics-setup-service")
+	if err != nil {
+		t.Fatalf("SetupTracing failed: %v", err)
+	}
+	counter, err := otel.Meter("setup-test").Int64Counter("requests")
+	if err != nil {
+		t.Fatalf("Int64Counter failed: %v", err)
+	}
+	counter.Add(ctx, 1)
+	var record log.Record
+	record.SetBody(log.StringValue("setup test"))
+	global.Logger("setup-test").Emit(ctx, record)
+
+	if err := shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown failed: %v", err)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if !received["/v1/metrics"] || !received["/v1/logs"] {
+		t.Errorf("Expected shutdown to export metrics and logs, got requests for %v", received)
+	}
+}
+
+func TestSetupMetrics(t *testing.T) {
+	ctx := context.Background()
+	t.Cleanup(provider.ResetGlobal)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", srv.URL)
+	t.Setenv("OTEL_EXPORTER_OTLP_PROTOCOL", "http")
+
+	before := otel.GetTracerProvider()
+	shutdown, err := SetupMetrics(ctx, "metrics-only-service")
+	if err != nil {
+		t.Fatalf("SetupMetrics failed: %v", err)
+	}
+	if otel.GetTracerProvider() != before {
+		t.Error("Expected SetupMetrics to leave the tracer provider unchanged")
+	}
+	if err := shutdown(ctx); err != nil {
+		t.Errorf("Shutdown failed: %v", err)
+	}
+}
@@ -0,0 +1,196 @@
+package tracer
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Field names added to log records by SlogHandler.
+const (
+	SlogTraceIDKey    = "trace_id"
+	SlogSpanIDKey     = "span_id"
+	SlogTraceFlagsKey = "trace_flags"
+)
+
+// SlogOption configures a SlogHandler.
+type SlogOption func(*slogConfig)
+
+type slogConfig struct {
+	spanEvents     bool
+	spanEventLevel slog.Level
+}
+
+// WithSpanEvents mirrors records at or above level as events on the active span, with
+// the message as the event name and the record's attributes, including those added
+// with Logger.With, as event attributes. Use slog.LevelWarn to see warnings and errors
+// in the trace view next to the spans they occurred in.
+func WithSpanEvents(level slog.Level) SlogOption {
+	return func(c *slogConfig) {
+		c.spanEvents = true
+		c.spanEventLevel = level
+	}
+}
+
+// SlogHandler is a slog.Handler that adds the trace_id, span_id and trace_flags of the
+// span in the record's context to every record before passing it to the wrapped
+// handler. Unlike InjectTraceIDIntoContext it needs no plumbing beyond passing the
+// context to the logger, e.g. with slog.InfoContext.
+//
+// The fields are added to the record, so under Logger.WithGroup they are nested in
+// the group like other record attributes.
+type SlogHandler struct {
+	next slog.Handler
+	cfg  slogConfig
+
+	// group and attrs track WithGroup and WithAttrs for span events, which cannot
+	// read them back from next.
+	group string
+	attrs []attribute.KeyValue
+}
+
+// NewSlogHandler wraps next so that records logged with a context carrying a span
+// are correlated with the trace.
+//
+// Example:
+//
+//	logger := slog.New(tracer.NewSlogHandler(slog.NewJSONHandler(os.Stdout, nil),
+//	    tracer.WithSpanEvents(slog.LevelWarn)))
+//	slog.SetDefault(logger)
+//
+//	ctx, span := tr.Start(ctx, "charge")
+//	defer span.End()
+//	slog.InfoContext(ctx, "charging card", "amount", 42)
+//	// {"level":"INFO","msg":"charging card","amount":42,"trace_id":"4bf9...","span_id":"00f0...","trace_flags":"01"}
+func NewSlogHandler(next slog.Handler, opts ...SlogOption) *SlogHandler {
+	h := &SlogHandler{next: next}
+	for _, opt := range opts {
+		opt(&h.cfg)
+	}
+	return h
+}
+
+// Enabled reports whether the wrapped handler handles records at level.
+func (h *SlogHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Handle adds the trace fields of the span in ctx to r, mirrors r as a span event if
+// enabled, and passes r to the wrapped handler. Records without a valid span context
+// are passed through unchanged.
+func (h *SlogHandler) Handle(ctx context.Context, r slog.Record) error {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return h.next.Handle(ctx, r)
+	}
+
+	if h.cfg.spanEvents && r.Level >= h.cfg.spanEventLevel {
+		h.addSpanEvent(trace.SpanFromContext(ctx), r)
+	}
+
+	r = r.Clone()
+	r.AddAttrs(
+		slog.String(SlogTraceIDKey, sc.TraceID().String()),
+		slog.String(SlogSpanIDKey, sc.SpanID().String()),
+		slog.String(SlogTraceFlagsKey, sc.TraceFlags().String()),
+	)
+	return h.next.Handle(ctx, r)
+}
+
+// WithAttrs returns a handler whose records include attrs.
+func (h *SlogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	h2 := *h
+	h2.next = h.next.WithAttrs(attrs)
+	if h.cfg.spanEvents {
+		h2.attrs = append([]attribute.KeyValue(nil), h.attrs...)
+		for _, a := range attrs {
+			h2.attrs = appendSlogAttr(h2.attrs, h.group, a)
+		}
+	}
+	return &h2
+}
+
+// WithGroup returns a handler that nests subsequent attributes in the group name.
+// Span event attributes are flattened to dotted keys such as "request.id".
+func (h *SlogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	h2 := *h
+	h2.next = h.next.WithGroup(name)
+	h2.group = h.group + name + "."
+	return &h2
+}
+
+// addSpanEvent records r as an event on span.
+func (h *SlogHandler) addSpanEvent(span trace.Span, r slog.Record) {
+	if !span.IsRecording() {
+		return
+	}
+	attrs := make([]attribute.KeyValue, 0, 1+len(h.attrs)+r.NumAttrs())
+	attrs = append(attrs, attribute.String("log.severity", r.Level.String()))
+	attrs = append(attrs, h.attrs...)
+	r.Attrs(func(a slog.Attr) bool {
+		attrs = appendSlogAttr(attrs, h.group, a)
+		return true
+	})
+
+	opts := []trace.EventOption{trace.WithAttributes(attrs...)}
+	if !r.Time.IsZero() {
+		opts = append(opts, trace.WithTimestamp(r.Time))
+	}
+	span.AddEvent(r.Message, opts...)
+}
+
+// appendSlogAttr converts a to span attributes with keys prefixed by group. Groups are
+// flattened to dotted keys and values without an attribute type are formatted as
+// strings.
+func appendSlogAttr(attrs []attribute.KeyValue, group string, a slog.Attr) []attribute.KeyValue {
+	v := a.Value.Resolve()
+	if a.Key == "" && v.Kind() != slog.KindGroup {
+		return attrs
+	}
+	key := group + a.Key
+
+	switch v.Kind() {
+	case slog.KindString:
+		return append(attrs, attribute.String(key, v.String()))
+	case slog.KindInt64:
+		return append(attrs, attribute.Int64(key, v.Int64()))
+	case slog.KindUint64:
+		if u := v.Uint64(); u <= math.MaxInt64 {
+			return append(attrs, attribute.Int64(key, int64(u)))
+		}
+		return append(attrs, attribute.String(key, v.String()))
+	case slog.KindFloat64:
+		return append(attrs, attribute.Float64(key, v.Float64()))
+	case slog.KindBool:
+		return append(attrs, attribute.Bool(key, v.Bool()))
+	case slog.KindDuration:
+		return append(attrs, attribute.String(key, v.Duration().String()))
+	case slog.KindTime:
+		return append(attrs, attribute.String(key, v.Time().Format(time.RFC3339Nano)))
+	case slog.KindGroup:
+		prefix := group
+		if a.Key != "" {
+			prefix = key + "."
+		}
+		for _, ga := range v.Group() {
+			attrs = appendSlogAttr(attrs, prefix, ga)
+		}
+		return attrs
+	default:
+		if err, ok := v.Any().(error); ok {
+			return append(attrs, attribute.String(key, err.Error()))
+		}
+		return append(attrs, attribute.String(key, fmt.Sprint(v.Any())))
+	}
+}
@@ -0,0 +1,107 @@
+package tracer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func newSlogTestProvider(t *testing.T) (*sdktrace.TracerProvider, *tracetest.InMemoryExporter) {
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	t.Cleanup(func() { _ = tp.Shutdown(context.Background()) })
+	return tp, exporter
+}
+
+func decodeLogLines(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+	var lines []map[string]any
+	dec := json.NewDecoder(buf)
+	for dec.More() {
+		var line map[string]any
+		if err := dec.Decode(&line); err != nil {
+			t.Fatalf("Invalid log line: %v", err)
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func TestSlogHandler_AddsTraceFields(t *testing.T) {
+	tp, _ := newSlogTestProvider(t)
+	var buf bytes.Buffer
+	logger := slog.New(NewSlogHandler(slog.NewJSONHandler(&buf, nil)))
+
+	ctx, span := tp.Tracer("slog-test").Start(context.Background(), "operation")
+	logger.InfoContext(ctx, "inside span", "user", "alice")
+	span.End()
+	logger.InfoContext(context.Background(), "outside span")
+
+	lines := decodeLogLines(t, &buf)
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 log lines, got %d", len(lines))
+	}
+	sc := span.SpanContext()
+	if lines[0][SlogTraceIDKey] != sc.TraceID().String() || lines[0][SlogSpanIDKey] != sc.SpanID().String() ||
+		lines[0][SlogTraceFlagsKey] != "01" || lines[0]["user"] != "alice" {
+		t.Errorf("Unexpected fields: %v", lines[0])
+	}
+	if _, ok := lines[1][SlogTraceIDKey]; ok {
+		t.Errorf("Expected no trace fields outside a span, got %v", lines[1])
+	}
+}
+
+func TestSlogHandler_SpanEvents(t *testing.T) {
+	tp, exporter := newSlogTestProvider(t)
+	logger := slog.New(NewSlogHandler(slog.NewJSONHandler(&bytes.Buffer{}, nil), WithSpanEvents(slog.LevelWarn))).
+		With("component", "billing").
+		WithGroup("request")
+
+	ctx, span := tp.Tracer("slog-test").Start(context.Background(), "operation")
+	logger.InfoContext(ctx, "not mirrored")
+	logger.WarnContext(ctx, "retrying", "attempt", 2)
+	logger.ErrorContext(ctx, "charge failed", "err", errors.New("card declined"), slog.Group("card", "brand", "visa"))
+	span.End()
+
+	spans := exporter.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("Expected 1 span, got %d", len(spans))
+	}
+	events := spans[0].Events
+	if len(events) != 2 || events[0].Name != "retrying" || events[1].Name != "charge failed" {
+		t.Fatalf("Expected the warn and error records as events, got %+v", events)
+	}
+
+	want := map[attribute.Key]attribute.Value{
+		"log.severity":       attribute.StringValue("ERROR"),
+		"component":          attribute.StringValue("billing"),
+		"request.err":        attribute.StringValue("card declined"),
+		"request.card.brand": attribute.StringValue("visa"),
+	}
+	got := map[attribute.Key]attribute.Value{}
+	for _, kv := range events[1].Attributes {
+		got[kv.Key] = kv.Value
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("Expected %s=%s, got %v", k, v.Emit(), got)
+		}
+	}
+	if events[0].Attributes[len(events[0].Attributes)-1] != attribute.Int64("request.attempt", 2) {
+		t.Errorf("Expected the attempt attribute, got %v", events[0].Attributes)
+	}
+}
+
+func TestSlogHandler_DelegatesEnabled(t *testing.T) {
+	h := NewSlogHandler(slog.NewJSONHandler(&bytes.Buffer{}, &slog.HandlerOptions{Level: slog.LevelWarn}))
+	if h.Enabled(context.Background(), slog.LevelInfo) || !h.Enabled(context.Background(), slog.LevelError) {
+		t.Error("Expected Enabled to follow the wrapped handler's level")
+	}
+}